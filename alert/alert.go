@@ -0,0 +1,382 @@
+// Package alert provides an [io.Writer] that posts alerts to a chat (Slack, Telegram) when a fatal entry
+// is written or when a number of errors in a time window crosses a threshold, so small services
+// get paging without extra infrastructure:
+//
+//	w := alert.New(alert.Slack("https://hooks.slack.com/services/..."), alert.WithService("billing"), alert.WithErrorRate(10, time.Minute))
+//	logger := logze.New(logze.NewConfig(os.Stderr, w))
+//	defer logger.Close()
+//
+// Alerts with the same text are deduplicated and the number of alerts is rate limited,
+// suppressed alerts are counted in the next one. Alerts are sent in background, so a slow chat doesn't block logging.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureAlert)
+}
+
+const (
+	// DefaultErrorThreshold is a default number of errors in [DefaultErrorWindow] that triggers an alert.
+	DefaultErrorThreshold = 10
+	// DefaultErrorWindow is a default time window of counting errors.
+	DefaultErrorWindow = time.Minute
+	// DefaultDedupWindow is a default time during which an alert with the same text is not sent again.
+	DefaultDedupWindow = 10 * time.Minute
+	// DefaultRateLimit is a default max number of alerts sent in [DefaultRateInterval].
+	DefaultRateLimit = 10
+	// DefaultRateInterval is a default interval of the rate limit of alerts.
+	DefaultRateInterval = time.Hour
+	// DefaultTimeout is a default timeout of sending one alert.
+	DefaultTimeout = 10 * time.Second
+	// DefaultQueueSize is a default max number of alerts waiting to be sent.
+	DefaultQueueSize = 16
+)
+
+// errorRateKey is a key of deduplication of error rate alerts.
+const errorRateKey = "\x00errors"
+
+// ErrQueueFull is passed to the error callback when an alert is dropped because the queue is full.
+var ErrQueueFull = errors.New("alert: queue is full")
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithService sets a name of a service that prefixes texts of alerts, default value is empty.
+func WithService(service string) Option {
+	return func(o *options) {
+		o.service = service
+	}
+}
+
+// WithErrorRate sets a number of error entries in a time window that triggers an alert,
+// default values are [DefaultErrorThreshold] and [DefaultErrorWindow]. Zero threshold disables error rate alerts.
+func WithErrorRate(threshold int, window time.Duration) Option {
+	return func(o *options) {
+		o.errorThreshold = threshold
+		o.errorWindow = window
+	}
+}
+
+// WithDedup sets a time during which an alert with the same text is not sent again (error rate alerts
+// are sent once in the window),
+// default value is [DefaultDedupWindow]. Zero window disables deduplication.
+func WithDedup(window time.Duration) Option {
+	return func(o *options) {
+		o.dedupWindow = window
+	}
+}
+
+// WithRateLimit sets a max number of alerts sent in an interval, default values are
+// [DefaultRateLimit] and [DefaultRateInterval]. Zero limit disables rate limiting.
+func WithRateLimit(limit int, interval time.Duration) Option {
+	return func(o *options) {
+		o.rateLimit = limit
+		o.rateInterval = interval
+	}
+}
+
+// WithTimeout sets a timeout of sending one alert, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithOnError sets a callback that is called when an alert can't be sent, by default errors are printed to stderr.
+func WithOnError(onError func(err error)) Option {
+	return func(o *options) {
+		o.onError = onError
+	}
+}
+
+type options struct {
+	service        string
+	errorThreshold int
+	errorWindow    time.Duration
+	dedupWindow    time.Duration
+	rateLimit      int
+	rateInterval   time.Duration
+	timeout        time.Duration
+	onError        func(err error)
+}
+
+// Writer watches written entries and sends alerts in background, it is safe for concurrent use.
+// [Writer.Close] must be called to send the rest of alerts.
+type Writer struct {
+	sender Sender
+	o      options
+
+	mu         sync.Mutex
+	closed     bool
+	errors     []time.Time
+	lastSent   map[string]time.Time
+	sent       []time.Time
+	suppressed int
+
+	errorLevel  []byte
+	fatalLevels [][]byte
+
+	queue chan string
+	flush chan chan error
+	done  chan struct{}
+
+	failed atomic.Int64
+}
+
+// New returns [Writer] that sends alerts using the sender and starts its background goroutine.
+func New(sender Sender, opts ...Option) *Writer {
+	o := options{
+		errorThreshold: DefaultErrorThreshold,
+		errorWindow:    DefaultErrorWindow,
+		dedupWindow:    DefaultDedupWindow,
+		rateLimit:      DefaultRateLimit,
+		rateInterval:   DefaultRateInterval,
+		timeout:        DefaultTimeout,
+		onError: func(err error) {
+			fmt.Fprintf(os.Stderr, "WRN: %v\n", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.errorWindow <= 0 {
+		o.errorWindow = DefaultErrorWindow
+	}
+	if o.rateInterval <= 0 {
+		o.rateInterval = DefaultRateInterval
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+
+	w := &Writer{
+		sender:      sender,
+		o:           o,
+		lastSent:    make(map[string]time.Time),
+		errorLevel:  levelPattern(zerolog.LevelErrorValue),
+		fatalLevels: [][]byte{levelPattern(zerolog.LevelFatalValue), levelPattern(zerolog.LevelPanicValue)},
+		queue:       make(chan string, DefaultQueueSize),
+		flush:       make(chan chan error),
+		done:        make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write checks a level of an entry and queues an alert if it is needed, it never blocks.
+// Entries of levels below error are ignored. It returns [os.ErrClosed] after [Writer.Close].
+func (w *Writer) Write(p []byte) (int, error) {
+	fatal := bytes.Contains(p, w.fatalLevels[0]) || bytes.Contains(p, w.fatalLevels[1])
+	if !fatal && !bytes.Contains(p, w.errorLevel) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.closed {
+			return 0, os.ErrClosed
+		}
+		return len(p), nil
+	}
+
+	e, err := logze.ParseEntry(p)
+	if err != nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	now := time.Now()
+	var key, text string
+	if fatal {
+		text = strings.ToUpper(e.Level) + ": " + describe(e)
+		key = text
+	} else {
+		// All error rate alerts are deduplicated together, their texts differ by a number of errors
+		text, key = w.countError(e, now), errorRateKey
+	}
+	if text == "" {
+		return len(p), nil
+	}
+	if w.o.service != "" {
+		text = "[" + w.o.service + "] " + text
+	}
+	w.send(key, text, now)
+	return len(p), nil
+}
+
+// ParsesEntries marks the writer as [logze.EntryParser], so it gets entries with standard field names.
+func (w *Writer) ParsesEntries() {}
+
+// Flush sends alerts that are queued before the call and returns the last error of sending since the previous Flush.
+func (w *Writer) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case w.flush <- ch:
+		return <-ch
+	case <-w.done:
+		return nil
+	}
+}
+
+// Failed returns a number of alerts that are not sent since the writer is created.
+func (w *Writer) Failed() int64 {
+	return w.failed.Load()
+}
+
+// Close sends the rest of alerts and stops the background goroutine.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	before := w.failed.Load()
+	<-w.done
+	if n := w.failed.Load() - before; n > 0 {
+		return fmt.Errorf("alert: %d alerts are not sent on close", n)
+	}
+	return nil
+}
+
+// countError adds an error to the window and returns a text of an alert if the threshold is crossed.
+func (w *Writer) countError(e logze.Entry, now time.Time) string {
+	if w.o.errorThreshold <= 0 {
+		return ""
+	}
+	w.errors = append(trimBefore(w.errors, now.Add(-w.o.errorWindow)), now)
+	if len(w.errors) < w.o.errorThreshold {
+		return ""
+	}
+	n := len(w.errors)
+	// The next alert needs another threshold of errors
+	w.errors = w.errors[:0]
+	return fmt.Sprintf("%d errors in %s, last: %s", n, w.o.errorWindow, describe(e))
+}
+
+// send applies deduplication and rate limiting and queues an alert. It is called with locked mutex.
+func (w *Writer) send(key, text string, now time.Time) {
+	if w.o.dedupWindow > 0 {
+		for k, t := range w.lastSent {
+			if now.Sub(t) >= w.o.dedupWindow {
+				delete(w.lastSent, k)
+			}
+		}
+		if _, ok := w.lastSent[key]; ok {
+			return
+		}
+		w.lastSent[key] = now
+	}
+	if w.o.rateLimit > 0 {
+		w.sent = trimBefore(w.sent, now.Add(-w.o.rateInterval))
+		if len(w.sent) >= w.o.rateLimit {
+			w.suppressed++
+			return
+		}
+		w.sent = append(w.sent, now)
+	}
+	if w.suppressed > 0 {
+		text += fmt.Sprintf(" (%d alerts suppressed)", w.suppressed)
+		w.suppressed = 0
+	}
+
+	select {
+	case w.queue <- text:
+	default:
+		w.fail(ErrQueueFull)
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	// err is the last error of sending since the previous Flush, an alert queued before Flush
+	// may be already taken from the queue when Flush is called
+	var err error
+	for {
+		select {
+		case text, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			if sendErr := w.deliver(text); sendErr != nil {
+				err = sendErr
+			}
+		case ch := <-w.flush:
+			for n := len(w.queue); n > 0; n-- {
+				text, ok := <-w.queue
+				if !ok {
+					break
+				}
+				if sendErr := w.deliver(text); sendErr != nil {
+					err = sendErr
+				}
+			}
+			ch <- err
+			err = nil
+		}
+	}
+}
+
+func (w *Writer) deliver(text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := w.sender.Send(ctx, text); err != nil {
+		err = fmt.Errorf("alert: %w", err)
+		w.fail(err)
+		return err
+	}
+	return nil
+}
+
+func (w *Writer) fail(err error) {
+	w.failed.Add(1)
+	if w.o.onError != nil {
+		w.o.onError(err)
+	}
+}
+
+// describe returns a message of an entry with its error.
+func describe(e logze.Entry) string {
+	msg := e.Message
+	if err, ok := e.Fields[zerolog.ErrorFieldName].(string); ok && err != "" {
+		if msg == "" {
+			return err
+		}
+		msg += ": " + err
+	}
+	return msg
+}
+
+// levelPattern returns a level field of an entry to find entries of the level without parsing them.
+func levelPattern(level string) []byte {
+	return []byte(`"` + zerolog.LevelFieldName + `":"` + level + `"`)
+}
+
+// trimBefore removes times before the limit from a sorted slice.
+func trimBefore(times []time.Time, limit time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(limit) {
+		i++
+	}
+	return append(times[:0], times[i:]...)
+}
@@ -0,0 +1,158 @@
+package alert_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/alert"
+)
+
+type fakeSender struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (s *fakeSender) Send(_ context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = append(s.texts, text)
+	return nil
+}
+
+func (s *fakeSender) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.texts...)
+}
+
+func TestFatal(t *testing.T) {
+	s := &fakeSender{}
+	w := alert.New(s, alert.WithService("billing"))
+	defer w.Close()
+
+	w.Write([]byte(`{"level":"info","message":"started"}` + "\n"))
+	w.Write([]byte(`{"level":"fatal","error":"connection refused","message":"cannot open db"}` + "\n"))
+	w.Write([]byte(`{"level":"fatal","error":"connection refused","message":"cannot open db"}` + "\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	texts := s.get()
+	if len(texts) != 1 {
+		t.Fatalf("expected 1 deduplicated alert, got %v", texts)
+	}
+	if expected := "[billing] FATAL: cannot open db: connection refused"; texts[0] != expected {
+		t.Errorf("expected %q, got %q", expected, texts[0])
+	}
+}
+
+func TestOutputModes(t *testing.T) {
+	for name, cfg := range map[string]func(w *alert.Writer) logze.Config{
+		"plain": func(w *alert.Writer) logze.Config { return logze.NewConfig(w) },
+		"ecs":   func(w *alert.Writer) logze.Config { return logze.NewConfig(w).WithECS() },
+		"field names": func(w *alert.Writer) logze.Config {
+			return logze.NewConfig(w).WithFieldNames(logze.FieldNames{Level: "severity"})
+		},
+	} {
+		s := &fakeSender{}
+		w := alert.New(s, alert.WithErrorRate(1, time.Minute))
+		logze.New(cfg(w).WithNoDiode()).Error("cannot open db")
+		if err := w.Flush(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if texts := s.get(); len(texts) != 1 {
+			t.Errorf("%s: expected 1 alert, got %v", name, texts)
+		}
+		w.Close()
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	s := &fakeSender{}
+	w := alert.New(s, alert.WithErrorRate(3, time.Minute))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Warn("slow request")
+	logger.Error("cannot connect")
+	logger.Error("cannot connect")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if texts := s.get(); len(texts) != 0 {
+		t.Fatalf("expected no alerts below threshold, got %v", texts)
+	}
+
+	logger.Err(errors.New("timeout"), "cannot connect")
+	// Error rate alerts are deduplicated, so the next burst doesn't send an alert
+	for i := 0; i < 3; i++ {
+		logger.Error("cannot connect")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	texts := s.get()
+	if len(texts) != 1 {
+		t.Fatalf("expected 1 alert, got %v", texts)
+	}
+	if expected := "3 errors in 1m0s, last: cannot connect: timeout"; texts[0] != expected {
+		t.Errorf("expected %q, got %q", expected, texts[0])
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte(`{"level":"error"}`)); err == nil {
+		t.Error("expected error after close")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := &fakeSender{}
+	w := alert.New(s, alert.WithRateLimit(1, time.Hour), alert.WithDedup(0))
+	defer w.Close()
+
+	w.Write([]byte(`{"level":"fatal","message":"first"}`))
+	w.Write([]byte(`{"level":"fatal","message":"second"}`))
+	w.Write([]byte(`{"level":"panic","message":"third"}`))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	texts := s.get()
+	if len(texts) != 1 || texts[0] != "FATAL: first" {
+		t.Errorf("expected only the first alert, got %v", texts)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	var onError []error
+	var mu sync.Mutex
+	w := alert.New(alert.SenderFunc(func(context.Context, string) error {
+		return errors.New("unavailable")
+	}), alert.WithOnError(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		onError = append(onError, err)
+	}))
+
+	w.Write([]byte(`{"level":"fatal","message":"db down"}`))
+	if err := w.Flush(); err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("expected send error, got %v", err)
+	}
+	w.Write([]byte(`{"level":"fatal","message":"disk full"}`))
+	if err := w.Close(); err == nil {
+		t.Error("expected error on close")
+	}
+	if w.Failed() != 2 {
+		t.Errorf("expected 2 failed alerts, got %d", w.Failed())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(onError) != 2 {
+		t.Errorf("expected 2 errors in callback, got %v", onError)
+	}
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// TelegramURL is a base URL of the Telegram Bot API.
+var TelegramURL = "https://api.telegram.org"
+
+// Sender posts a text of an alert to a chat.
+type Sender interface {
+	Send(ctx context.Context, text string) error
+}
+
+// SenderFunc is an adapter to use a function as [Sender].
+type SenderFunc func(ctx context.Context, text string) error
+
+// Send calls f(ctx, text).
+func (f SenderFunc) Send(ctx context.Context, text string) error {
+	return f(ctx, text)
+}
+
+// Slack returns [Sender] that posts alerts to a Slack incoming webhook.
+func Slack(webhookURL string) Sender {
+	return SenderFunc(func(ctx context.Context, text string) error {
+		if err := postJSON(ctx, webhookURL, map[string]string{"text": text}); err != nil {
+			return fmt.Errorf("slack: %w", err)
+		}
+		return nil
+	})
+}
+
+// Telegram returns [Sender] that posts alerts to a chat using a token of a Telegram bot.
+func Telegram(token, chatID string) Sender {
+	return SenderFunc(func(ctx context.Context, text string) error {
+		body := map[string]string{"chat_id": chatID, "text": text}
+		if err := postJSON(ctx, TelegramURL+"/bot"+token+"/sendMessage", body); err != nil {
+			return fmt.Errorf("telegram: %w", err)
+		}
+		return nil
+	})
+}
+
+func postJSON(ctx context.Context, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", stripURL(err))
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// stripURL removes a URL from an error of a request, because URLs of webhooks and bots contain secrets.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package alert_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2/alert"
+)
+
+func TestSlack(t *testing.T) {
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	if err := alert.Slack(srv.URL).Send(context.Background(), "db down"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body["text"] != "db down" {
+		t.Errorf("expected text %q, got %v", "db down", body)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var path string
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["chat_id"] == "bad" {
+			http.Error(w, `{"ok":false,"description":"chat not found"}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	old := alert.TelegramURL
+	alert.TelegramURL = srv.URL
+	defer func() { alert.TelegramURL = old }()
+
+	if err := alert.Telegram("secret", "42").Send(context.Background(), "db down"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/botsecret/sendMessage" {
+		t.Errorf("expected path /botsecret/sendMessage, got %q", path)
+	}
+	if body["chat_id"] != "42" || body["text"] != "db down" {
+		t.Errorf("expected chat_id and text, got %v", body)
+	}
+
+	err := alert.Telegram("secret", "bad").Send(context.Background(), "db down")
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("expected status error, got %v", err)
+	}
+
+	srv.Close()
+	err = alert.Telegram("secret", "42").Send(context.Background(), "db down")
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected error without token, got %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+// Package appinsights provides an [io.Writer] that sends log entries to Azure Application Insights
+// as trace telemetry and errors as exception telemetry, for teams on Azure Monitor.
+//
+// The package doesn't depend on the Application Insights SDK, telemetry is sent by a [Transmitter].
+// For example, with github.com/microsoft/ApplicationInsights-Go:
+//
+//	client := ai.NewTelemetryClient(instrumentationKey)
+//	w := appinsights.New(appinsights.TransmitterFunc(func(ctx context.Context, t *appinsights.Telemetry) error {
+//		if t.Kind == appinsights.KindException {
+//			ex := ai.NewExceptionTelemetry(t.Error)
+//			ex.SeverityLevel = contracts.SeverityLevel(t.SeverityLevel)
+//			ex.Timestamp, ex.Properties = t.Timestamp, t.Properties
+//			ex.Tags.Operation().SetId(t.OperationID)
+//			client.Track(ex)
+//			return nil
+//		}
+//		tr := ai.NewTraceTelemetry(t.Message, contracts.SeverityLevel(t.SeverityLevel))
+//		tr.Timestamp, tr.Properties = t.Timestamp, t.Properties
+//		tr.Tags.Operation().SetId(t.OperationID)
+//		client.Track(tr)
+//		return nil
+//	}))
+//	logger := logze.New(logze.NewConfig(w).WithStackTrace())
+package appinsights
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureAppInsights)
+}
+
+// DefaultTimeout is a default timeout of transmitting one telemetry item.
+const DefaultTimeout = 5 * time.Second
+
+// Kinds of telemetry items.
+const (
+	KindTrace     = "trace"
+	KindException = "exception"
+)
+
+// SeverityLevel is a severity level of Application Insights, it has the same values as contracts.SeverityLevel of the SDK.
+type SeverityLevel int
+
+// Enumerating severity levels of Application Insights.
+const (
+	Verbose     SeverityLevel = 0
+	Information SeverityLevel = 1
+	Warning     SeverityLevel = 2
+	Error       SeverityLevel = 3
+	Critical    SeverityLevel = 4
+)
+
+// Telemetry is a telemetry item that is transmitted for an entry.
+type Telemetry struct {
+	// Kind is [KindTrace] or [KindException].
+	Kind string
+
+	// Timestamp is a time of an entry.
+	Timestamp time.Time
+
+	// Message is a message of an entry.
+	Message string
+
+	// SeverityLevel is a severity level mapped from a level of an entry: trace and debug are [Verbose],
+	// info and entries without level are [Information], warn is [Warning], error is [Error], fatal and panic are [Critical].
+	SeverityLevel SeverityLevel
+
+	// Error is a message of an exception: an error of an entry or its message if there is no error.
+	// It is empty for traces.
+	Error string
+
+	// Stack is a stack trace of an exception from the newest frame to the oldest one, as Application Insights
+	// expects in parsedStack. It is taken from the stack field, so enable it with [logze.Config.WithStackTrace].
+	Stack []logze.Frame
+
+	// OperationID is an ID of an operation (ai.operation.id tag) taken from a trace ID field.
+	OperationID string
+
+	// ParentID is an ID of a parent of an operation (ai.operation.parentId tag) taken from a span ID field.
+	ParentID string
+
+	// Properties are all other fields of an entry as strings, objects and arrays are encoded as JSON.
+	Properties map[string]string
+}
+
+// Transmitter sends telemetry to Application Insights, it is implemented by an adapter of the SDK.
+// It may send telemetry asynchronously, in that case it should implement Flush(ctx) error.
+type Transmitter interface {
+	Transmit(ctx context.Context, t *Telemetry) error
+}
+
+// TransmitterFunc is an adapter to use a function as [Transmitter].
+type TransmitterFunc func(ctx context.Context, t *Telemetry) error
+
+// Transmit calls f(ctx, t).
+func (f TransmitterFunc) Transmit(ctx context.Context, t *Telemetry) error {
+	return f(ctx, t)
+}
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithExceptionLevels sets levels of entries that are sent as exception telemetry,
+// default value is error, fatal and panic. Entries of other levels are sent as traces.
+func WithExceptionLevels(levels ...string) Option {
+	return func(o *options) {
+		o.exceptionLevels = levels
+	}
+}
+
+// WithOperationFields sets names of fields of entries with IDs of an operation and its parent,
+// default value is trace_id and span_id that are set by tracing middlewares. Empty name disables a field.
+func WithOperationFields(operationID, parentID string) Option {
+	return func(o *options) {
+		o.operationField = operationID
+		o.parentField = parentID
+	}
+}
+
+// WithTimeout sets a timeout of transmitting one telemetry item, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+type options struct {
+	exceptionLevels []string
+	operationField  string
+	parentField     string
+	timeout         time.Duration
+}
+
+// Writer transmits every written entry as a telemetry item. It is safe for concurrent use if the transmitter is.
+// Errors of the transmitter are returned, so they are counted in errors of [logze.Logger.Close].
+type Writer struct {
+	t Transmitter
+	o options
+}
+
+// New returns [Writer] that sends entries to Application Insights using the transmitter.
+func New(t Transmitter, opts ...Option) *Writer {
+	o := options{
+		exceptionLevels: []string{logze.LevelError, logze.LevelFatal, zerolog.LevelPanicValue},
+		operationField:  "trace_id",
+		parentField:     "span_id",
+		timeout:         DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+	return &Writer{t: t, o: o}
+}
+
+// Write transmits a telemetry item built from an entry, data that is not a JSON entry is sent as a trace message.
+func (w *Writer) Write(p []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := w.t.Transmit(ctx, w.telemetry(p)); err != nil {
+		return 0, fmt.Errorf("appinsights: %w", err)
+	}
+	return len(p), nil
+}
+
+// ParsesEntries marks the writer as [logze.EntryParser], so it gets entries with standard field names.
+func (w *Writer) ParsesEntries() {}
+
+// Flush waits for telemetry sent asynchronously if the transmitter implements Flush(ctx) error.
+func (w *Writer) Flush() error {
+	f, ok := w.t.(interface{ Flush(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := f.Flush(ctx); err != nil {
+		return fmt.Errorf("appinsights: %w", err)
+	}
+	return nil
+}
+
+// Name returns a name of the writer for errors of [logze.Logger.Close].
+func (w *Writer) Name() string {
+	return "appinsights"
+}
+
+func (w *Writer) telemetry(p []byte) *Telemetry {
+	e, err := logze.ParseEntry(p)
+	if err != nil {
+		return &Telemetry{
+			Kind:          KindTrace,
+			Timestamp:     time.Now(),
+			Message:       strings.TrimRight(string(p), "\n"),
+			SeverityLevel: Information,
+		}
+	}
+
+	t := &Telemetry{
+		Kind:          KindTrace,
+		Timestamp:     time.Now(),
+		Message:       e.Message,
+		SeverityLevel: severityLevel(e.Level),
+		Properties:    make(map[string]string, len(e.Fields)),
+	}
+	for _, level := range w.o.exceptionLevels {
+		if e.Level == level {
+			t.Kind = KindException
+			t.Error = e.Message
+			break
+		}
+	}
+
+	for k, v := range e.Fields {
+		switch {
+		case k == zerolog.TimestampFieldName:
+			if s, ok := v.(string); ok {
+				if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					t.Timestamp = ts
+					continue
+				}
+			}
+			t.Properties[k], _ = e.FieldString(k)
+
+		case k == zerolog.ErrorFieldName && t.Kind == KindException:
+			t.Error, _ = e.FieldString(k)
+
+		case k == zerolog.ErrorStackFieldName && t.Kind == KindException:
+			t.Stack = e.Stack()
+
+		case k == w.o.operationField:
+			t.OperationID, _ = e.FieldString(k)
+
+		case k == w.o.parentField:
+			t.ParentID, _ = e.FieldString(k)
+
+		default:
+			t.Properties[k], _ = e.FieldString(k)
+		}
+	}
+	return t
+}
+
+// severityLevel returns a severity level of Application Insights for a level.
+func severityLevel(level string) SeverityLevel {
+	switch level {
+	case logze.LevelTrace, logze.LevelDebug:
+		return Verbose
+	case logze.LevelWarn:
+		return Warning
+	case logze.LevelError:
+		return Error
+	case logze.LevelFatal, zerolog.LevelPanicValue:
+		return Critical
+	}
+	return Information
+}
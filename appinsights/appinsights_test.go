@@ -0,0 +1,110 @@
+package appinsights_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/appinsights"
+)
+
+type fakeTransmitter struct {
+	mu      sync.Mutex
+	items   []*appinsights.Telemetry
+	err     error
+	flushed bool
+}
+
+func (t *fakeTransmitter) Transmit(_ context.Context, item *appinsights.Telemetry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return t.err
+	}
+	t.items = append(t.items, item)
+	return nil
+}
+
+func (t *fakeTransmitter) Flush(context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushed = true
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	tr := &fakeTransmitter{}
+	logger := logze.New(logze.NewConfig(appinsights.New(tr)).WithNoDiode().WithStackTrace(), "service", "billing")
+
+	logger.Warn("slow request", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7", "status", 200)
+	logger.Err(errors.New("connection refused"), "cannot charge", "order", 42)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tr.items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(tr.items))
+	}
+	if !tr.flushed {
+		t.Error("expected flush of the transmitter on close")
+	}
+
+	trace := tr.items[0]
+	if trace.Kind != appinsights.KindTrace || trace.Message != "slow request" || trace.SeverityLevel != appinsights.Warning {
+		t.Errorf("expected warning trace, got %+v", trace)
+	}
+	if trace.OperationID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.ParentID != "00f067aa0ba902b7" {
+		t.Errorf("expected operation and parent IDs, got %q %q", trace.OperationID, trace.ParentID)
+	}
+	if trace.Properties["service"] != "billing" || trace.Properties["status"] != "200" {
+		t.Errorf("expected properties, got %v", trace.Properties)
+	}
+	if _, ok := trace.Properties["trace_id"]; ok || trace.Timestamp.IsZero() {
+		t.Errorf("expected no trace ID in properties and timestamp, got %+v", trace)
+	}
+
+	ex := tr.items[1]
+	if ex.Kind != appinsights.KindException || ex.Error != "connection refused" || ex.SeverityLevel != appinsights.Error {
+		t.Errorf("expected error exception, got %+v", ex)
+	}
+	if ex.Message != "cannot charge" || ex.Properties["order"] != "42" {
+		t.Errorf("expected message and properties, got %+v", ex)
+	}
+	found := false
+	for _, f := range ex.Stack {
+		found = found || strings.HasSuffix(f.Function, "TestWriter")
+	}
+	if !found {
+		t.Errorf("expected stack with the test, got %+v", ex.Stack)
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	tr := &fakeTransmitter{}
+	w := appinsights.New(tr, appinsights.WithExceptionLevels(logze.LevelWarn), appinsights.WithOperationFields("request_id", ""))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Warn("disk is almost full", "request_id", "req-1", "span_id", "s")
+	logger.Error("not an exception")
+	if len(tr.items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(tr.items))
+	}
+	if ex := tr.items[0]; ex.Kind != appinsights.KindException || ex.Error != "disk is almost full" || ex.OperationID != "req-1" || ex.Properties["span_id"] != "s" {
+		t.Errorf("expected exception with message as error, got %+v", ex)
+	}
+	if tr.items[1].Kind != appinsights.KindTrace {
+		t.Errorf("expected trace for error level, got %+v", tr.items[1])
+	}
+
+	tr.err = errors.New("quota exceeded")
+	if _, err := w.Write([]byte("raw line\n")); err == nil || !strings.Contains(err.Error(), "appinsights: quota exceeded") {
+		t.Errorf("expected error of the transmitter, got %v", err)
+	}
+	tr.err = nil
+	if _, err := w.Write([]byte("raw line\n")); err != nil || tr.items[2].Message != "raw line" {
+		t.Errorf("expected raw line as trace, got %v %+v", err, tr.items[2])
+	}
+}
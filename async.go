@@ -0,0 +1,169 @@
+package logze
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Enumerating policies of [AsyncWriter] when its queue is full, see [Config.WithAsync].
+const (
+	// AsyncDropNewest drops an entry that is being written, it is how diode works.
+	AsyncDropNewest = "drop_newest"
+	// AsyncDropOldest drops the oldest entry in the queue to make space for a new one.
+	AsyncDropOldest = "drop_oldest"
+	// AsyncBlock blocks a logging call until there is space in the queue or [AsyncOptions.BlockTimeout] is passed,
+	// it is intended for services that must not lose entries (e.g. audit logs).
+	AsyncBlock = "block"
+)
+
+// AsyncOptions is using for configuring [NewAsyncWriter] and [Config.WithAsync].
+type AsyncOptions struct {
+	// Policy is a behavior when the queue is full: drop_newest, drop_oldest or block.
+	// Default value is drop_newest.
+	Policy string
+
+	// Size is a max number of entries in the queue. Default value is [DefaultDiodeSize].
+	Size int
+
+	// BlockTimeout is a max time to wait for space in the queue with block policy, then an entry is dropped.
+	// Default value is 0, it means to wait until there is space.
+	BlockTimeout time.Duration
+}
+
+// AsyncWriter is an [io.Writer] that writes entries to the underlying writer in a background goroutine,
+// so logging calls don't wait for slow IO. Unlike diode it has a selectable policy when its queue is full,
+// see [AsyncOptions]. It is safe for concurrent use. Use [NewAsyncWriter] to create it.
+type AsyncWriter struct {
+	w    io.Writer
+	opts AsyncOptions
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	done   chan struct{}
+
+	dropped *dropCounter
+}
+
+// NewAsyncWriter returns [AsyncWriter] writing to w and starts its background goroutine.
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) *AsyncWriter {
+	return newAsyncWriter(w, opts, &dropCounter{})
+}
+
+func newAsyncWriter(w io.Writer, opts AsyncOptions, dropped *dropCounter) *AsyncWriter {
+	if opts.Policy == "" {
+		opts.Policy = AsyncDropNewest
+	}
+	if opts.Size <= 0 {
+		opts.Size = DefaultDiodeSize
+	}
+	aw := &AsyncWriter{
+		w:       w,
+		opts:    opts,
+		queue:   make(chan []byte, opts.Size),
+		done:    make(chan struct{}),
+		dropped: dropped,
+	}
+	go aw.run()
+	return aw
+}
+
+// Write adds a copy of p to the queue. If the queue is full, it drops an entry or blocks according to the policy.
+// It returns [os.ErrClosed] after [AsyncWriter.Close].
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	entry := append([]byte(nil), p...)
+	select {
+	case w.queue <- entry:
+		return len(p), nil
+	default:
+	}
+
+	switch w.opts.Policy {
+	case AsyncDropOldest:
+		for {
+			select {
+			case w.queue <- entry:
+				return len(p), nil
+			default:
+			}
+			select {
+			case <-w.queue:
+				w.dropped.add(1)
+			default:
+			}
+		}
+	case AsyncBlock:
+		if w.opts.BlockTimeout <= 0 {
+			w.queue <- entry
+			return len(p), nil
+		}
+		timer := time.NewTimer(w.opts.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case w.queue <- entry:
+			return len(p), nil
+		case <-timer.C:
+		}
+	}
+	w.dropped.add(1)
+	return len(p), nil
+}
+
+// Len returns a number of entries waiting to be written.
+func (w *AsyncWriter) Len() int {
+	return len(w.queue)
+}
+
+// Cap returns a max number of entries in the queue.
+func (w *AsyncWriter) Cap() int {
+	return cap(w.queue)
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *AsyncWriter) Dropped() int64 {
+	return w.dropped.n.Load()
+}
+
+// Close writes entries left in the queue and closes the underlying writer if it implements [io.Closer],
+// except [os.Stdout] and [os.Stderr].
+func (w *AsyncWriter) Close() error {
+	w.stop()
+	return closeWriter("underlying", w.w)
+}
+
+// stop writes entries left in the queue and stops the background goroutine, the underlying writer is not closed.
+func (w *AsyncWriter) stop() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for p := range w.queue {
+		// Errors are tracked by writers of the logger
+		_, _ = w.w.Write(p)
+	}
+}
+
+// closeAsync flushes and stops a background writer of the logger output (diode or async writer),
+// underlying writers are not closed.
+func closeAsync(w io.Writer) {
+	if aw, ok := w.(*AsyncWriter); ok {
+		aw.stop()
+		return
+	}
+	closeDiode(w)
+}
@@ -0,0 +1,146 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// blockingWriter blocks every write until it is released.
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriterPolicies(t *testing.T) {
+	tests := []struct {
+		policy   string
+		expected string
+		dropped  int64
+	}{
+		{logze.AsyncDropNewest, "0\n1\n2\n", 2},
+		{logze.AsyncDropOldest, "0\n3\n4\n", 2},
+	}
+	for _, tt := range tests {
+		target := newBlockingWriter()
+		w := logze.NewAsyncWriter(target, logze.AsyncOptions{Policy: tt.policy, Size: 2})
+
+		w.Write([]byte("0\n"))
+		// Wait for the first entry to be taken by the background goroutine
+		<-target.started
+		for _, p := range []string{"1\n", "2\n", "3\n", "4\n"} {
+			w.Write([]byte(p))
+		}
+		if w.Len() != 2 || w.Cap() != 2 {
+			t.Errorf("%s: expected 2 queued entries, got %d of %d", tt.policy, w.Len(), w.Cap())
+		}
+		close(target.release)
+		if err := w.Close(); err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.policy, err)
+		}
+
+		if target.String() != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.policy, tt.expected, target.String())
+		}
+		if w.Dropped() != tt.dropped {
+			t.Errorf("%s: expected %d dropped, got %d", tt.policy, tt.dropped, w.Dropped())
+		}
+	}
+}
+
+func TestAsyncWriterBlock(t *testing.T) {
+	target := newBlockingWriter()
+	w := logze.NewAsyncWriter(target, logze.AsyncOptions{Policy: logze.AsyncBlock, Size: 1, BlockTimeout: 10 * time.Millisecond})
+
+	w.Write([]byte("0\n"))
+	<-target.started
+	w.Write([]byte("1\n"))
+
+	start := time.Now()
+	w.Write([]byte("2\n"))
+	if time.Since(start) < 10*time.Millisecond {
+		t.Errorf("expected write to block until timeout")
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped, got %d", w.Dropped())
+	}
+
+	written := make(chan struct{})
+	w2 := logze.NewAsyncWriter(target, logze.AsyncOptions{Policy: logze.AsyncBlock, Size: 1})
+	w2.Write([]byte("a\n"))
+	go func() {
+		w2.Write([]byte("b\n"))
+		w2.Write([]byte("c\n"))
+		close(written)
+	}()
+	close(target.release)
+	<-written
+	w.Close()
+	w2.Close()
+	if w2.Dropped() != 0 {
+		t.Errorf("expected 0 dropped, got %d", w2.Dropped())
+	}
+	if out := target.String(); !strings.Contains(out, "c\n") || strings.Contains(out, "2\n") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestLoggerAsync(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithAsync(logze.AsyncOptions{Policy: logze.AsyncBlock}))
+
+	for i := 0; i < 100; i++ {
+		logger.Info("message", "i", i)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if n := strings.Count(b.String(), `"message":"message"`); n != 100 {
+		t.Errorf("expected 100 messages, got %d", n)
+	}
+}
+
+func TestAsyncConfig(t *testing.T) {
+	cfg, err := logze.ParseConfig([]byte(`
+async:
+  policy: block
+  size: 10
+  block_timeout: 50ms
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Async.Policy != logze.AsyncBlock || cfg.Async.Size != 10 || cfg.Async.BlockTimeout != 50*time.Millisecond {
+		t.Errorf("unexpected async settings: %+v", cfg.Async)
+	}
+
+	if err := logze.NewConfig().WithAsync(logze.AsyncOptions{Policy: "wait"}).Validate(); err == nil ||
+		!strings.Contains(err.Error(), "invalid async policy") {
+		t.Errorf("expected invalid async policy error, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime/debug"
+	"strings"
 	"testing"
 
 	"github.com/maxbolgarin/logze/v2"
@@ -50,6 +51,16 @@ func BenchmarkLogzeInfo(b *testing.B) {
 	}
 }
 
+func BenchmarkLogzeInfoEvent(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.E(logze.LevelInfo).Str("key", "value").Int("number", 123).Msg("error message")
+	}
+}
+
 func BenchmarkSLogInfo(b *testing.B) {
 	var buffer bytes.Buffer
 	logger := setupSLogger(&buffer)
@@ -82,6 +93,35 @@ func BenchmarkLogzeInfoFormat(b *testing.B) {
 	}
 }
 
+func BenchmarkZerologInfofOnly(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupZerologLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.Info().Msgf("request %s handled in %d ms, %d%% of budget", "GET /users", 42, 30)
+	}
+}
+
+func BenchmarkLogzeInfofOnly(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.Infof("request %s handled in %d ms, %d%% of budget", "GET /users", 42, 30)
+	}
+}
+
+func BenchmarkLogzeInfofDisabled(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		logger.Tracef("request %s handled in %d ms", "GET /users", 42, "key", "value")
+	}
+}
+
 func BenchmarkSLogInfoFormat(b *testing.B) {
 	var buffer bytes.Buffer
 	logger := setupSLogger(&buffer)
@@ -151,6 +191,17 @@ func BenchmarkLogzeErrorWithStack(b *testing.B) {
 	}
 }
 
+func BenchmarkLogzeErrorWithCaller(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := logze.New(logze.C(&buffer).WithLevel(logze.LevelDebug).WithNoDiode().WithErrorCaller())
+	err := errors.New("an error occurred")
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.Err(err, "error message", "key", "value", "number", 123)
+	}
+}
+
 func BenchmarkSLogErrorWithStack(b *testing.B) {
 	var buffer bytes.Buffer
 	logger := setupSLogger(&buffer)
@@ -227,3 +278,65 @@ func BenchmarkLogzeToIgnore5(b *testing.B) {
 		logger.Error("error message", "error", err, "key", "value", "number", 123)
 	}
 }
+
+func toIgnore(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("skip %d", i)
+	}
+	return out
+}
+
+func BenchmarkLogzeToIgnore30(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer).WithToIgnore(toIgnore(30)...)
+	err := errors.New("an error occurred")
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.Error("error message from http handler", "error", err, "key", "value", "number", 123)
+	}
+}
+
+// BenchmarkContainsToIgnore30 is a baseline for matching of messages to ignore: one [strings.Contains] per pattern.
+func BenchmarkContainsToIgnore30(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+	patterns := toIgnore(30)
+	err := errors.New("an error occurred")
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		msg := "error message from http handler"
+		ignored := false
+		for _, p := range patterns {
+			if strings.Contains(msg, p) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			logger.Error(msg, "error", err, "key", "value", "number", 123)
+		}
+	}
+}
+
+func BenchmarkLogzeWithFields(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.WithFields("route", "/api/users", "handler", "users").Info("request handled")
+	}
+}
+
+func BenchmarkLogzeCachedWith(b *testing.B) {
+	var buffer bytes.Buffer
+	logger := setupLogzeLogger(&buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		logger.CachedWith("/api/users", "route", "/api/users", "handler", "users").Info("request handled")
+	}
+}
@@ -0,0 +1,62 @@
+package logze
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MaxCachedLoggers is a max number of child loggers cached by [Logger.CachedWith] for one logger,
+// children with new keys are created without caching after the limit is reached.
+const MaxCachedLoggers = 1024
+
+// CachedWith returns [Logger] with applied fields as [Logger.WithFields] does, but a child for every key is created
+// only once and reused after that, so per-request hot paths don't rebuild the same context over and over:
+//
+//	lg := logger.CachedWith("handler:"+route, "route", route, "handler", name)
+//
+// Fields of the first call with a key are used, so the key should identify a set of fields. Children with the same key
+// share the level. Settings that don't affect fields (e.g. caller skip, list of messages to ignore or error counter)
+// are taken from the logger that CachedWith is called on. Fields should be known in advance and their number
+// should be limited, see [MaxCachedLoggers]; unique per-request values should be added with [Logger.WithFields].
+//
+// Fields added with [Logger.WithFields] are encoded once when a logger is created, so they don't add cost
+// to logging calls. Loggers created with [Logger.WithFields] don't cache their children.
+func (l Logger) CachedWith(key string, fields ...any) Logger {
+	if l.cache == nil {
+		return l.WithFields(fields...)
+	}
+	if v, ok := l.cache.m.Load(key); ok {
+		return l.withCached(v.(*Logger))
+	}
+
+	child := l.WithFields(fields...)
+	child.cache = newFieldsCache()
+	if l.cache.n.Load() >= MaxCachedLoggers {
+		return child
+	}
+	if v, loaded := l.cache.m.LoadOrStore(key, &child); loaded {
+		return l.withCached(v.(*Logger))
+	}
+	l.cache.n.Add(1)
+	return child
+}
+
+// withCached returns a copy of the logger with fields, group and level of the cached child.
+func (l Logger) withCached(c *Logger) Logger {
+	l.l = c.l
+	l.group = c.group
+	l.level = c.level
+	l.cache = c.cache
+	return l
+}
+
+// fieldsCache keeps children of a logger created with [Logger.CachedWith], it is shared between copies of [Logger]
+// with the same fields, group and level.
+type fieldsCache struct {
+	m sync.Map
+	n atomic.Int32
+}
+
+func newFieldsCache() *fieldsCache {
+	return &fieldsCache{}
+}
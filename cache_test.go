@@ -0,0 +1,91 @@
+package logze_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestCachedWith(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode().WithNoTimestamp())
+
+	logger.CachedWith("route", "route", "/a").Info("first")
+	// Fields of the first call are used for the key
+	logger.CachedWith("route", "route", "/b").Info("second")
+	logger.CachedWith("other", "route", "/c").Info("third")
+
+	expected := []string{
+		`{"level":"info","route":"/a","message":"first"}`,
+		`{"level":"info","route":"/a","message":"second"}`,
+		`{"level":"info","route":"/c","message":"third"}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestCachedWithDerived(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode().WithNoTimestamp())
+
+	logger.CachedWith("key", "k", 1).Info("parent")
+	logger.Named("child").CachedWith("key", "k", 2).Info("named")
+	logger.WithFields("f", true).CachedWith("key", "k", 3).Info("with fields")
+	logger.WithGroup("g").CachedWith("key", "k", 4).Info("group")
+
+	expected := []string{
+		`{"level":"info","k":1,"message":"parent"}`,
+		`{"level":"info","k":2,"logger":"child","message":"named"}`,
+		`{"level":"info","f":true,"k":3,"message":"with fields"}`,
+		`{"level":"info","g":{"k":4},"message":"group"}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestCachedWithLevel(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode().WithNoTimestamp())
+
+	logger.CachedWith("key", "k", 1).Debug("hidden")
+	logger.SetLevel(logze.LevelDebug)
+	logger.CachedWith("key", "k", 1).Debug("shown")
+
+	if output := b.String(); strings.Contains(output, "hidden") || !strings.Contains(output, "shown") {
+		t.Errorf("expected level to follow the parent, got %s", output)
+	}
+}
+
+func TestCachedWithNoAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector allocates")
+	}
+	logger := logze.New(logze.NewConfig(io.Discard).WithLevel(logze.LevelInfo).WithNoDiode())
+	child := logger.WithFields("service", "api", "version", "1.0.0", "region", "eu")
+	logger.CachedWith("key", "service", "api")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		child.Info("message")
+		logger.CachedWith("key", "service", "api").Info("message")
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs, got %v", allocs)
+	}
+}
@@ -0,0 +1,104 @@
+package logze
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// WithCallerLevels returns [Config] with a list of levels for which information about method caller
+// will be added to messages. By default caller is added only to trace level.
+// Call it without arguments to disable caller for all levels.
+func (c Config) WithCallerLevels(levels ...string) Config {
+	if levels == nil {
+		levels = []string{}
+	}
+	c.CallerLevels = levels
+	return c
+}
+
+// WithCallerSkip returns [Config] with a number of additional stack frames to skip when caller and error caller
+// are reported. Use it when logze is wrapped by another helper, so the reported frame is the real call site.
+func (c Config) WithCallerSkip(skip int) Config {
+	c.CallerSkip = skip
+	return c
+}
+
+// WithCallerSkip returns [Logger] that skips additional stack frames when caller and error caller are reported.
+// Skips are added to the skips of the logger, so it can be used in a helper for a single call:
+//
+//	func logRequest(lg logze.Logger, r *http.Request) {
+//		lg.WithCallerSkip(1).Info("request", "path", r.URL.Path)
+//	}
+func (l Logger) WithCallerSkip(skip int) Logger {
+	l.callerSkip += skip
+	return l
+}
+
+// WithCallerMarshaler returns [Config] with a function that formats caller and error caller fields
+// instead of global [zerolog.CallerMarshalFunc] with full absolute paths.
+// Use [ShortCaller] or [FuncCaller] for built-in formats.
+func (c Config) WithCallerMarshaler(marshaler func(pc uintptr, file string, line int) string) Config {
+	c.CallerMarshaler = marshaler
+	return c
+}
+
+// ShortCaller formats a caller as a file with its directory and a line, e.g. logze/logze.go:42.
+// It can be used with [Config.WithCallerMarshaler].
+func ShortCaller(_ uintptr, file string, line int) string {
+	dir, name := filepath.Split(file)
+	return filepath.Base(dir) + "/" + name + ":" + strconv.Itoa(line)
+}
+
+// FuncCaller formats a caller as [ShortCaller] with a name of a function, e.g. logze/logze.go:42 Logger.Info.
+// It can be used with [Config.WithCallerMarshaler].
+func FuncCaller(pc uintptr, file string, line int) string {
+	s := ShortCaller(pc, file, line)
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		s += " " + shortFuncName(fn.Name())
+	}
+	return s
+}
+
+func (l Logger) marshalCaller(pc uintptr, file string, line int) string {
+	if l.callerMarshaler != nil {
+		return l.callerMarshaler(pc, file, line)
+	}
+	return zerolog.CallerMarshalFunc(pc, file, line)
+}
+
+// levelSet is a set of levels stored as a bit mask.
+type levelSet uint16
+
+// levelSetExplicit is a bit that marks the set as configured, so empty set is not treated as default.
+const levelSetExplicit levelSet = 1 << 15
+
+func newCallerLevels(levels []string) levelSet {
+	if levels == nil {
+		return 0
+	}
+	s := levelSetExplicit
+	for _, level := range levels {
+		lvl, err := zerolog.ParseLevel(level)
+		if err != nil {
+			continue
+		}
+		s |= levelBit(lvl)
+	}
+	return s
+}
+
+// has returns true if the level is in the set. Default (zero) set contains only trace level.
+func (s levelSet) has(level zerolog.Level) bool {
+	if s == 0 {
+		return level == zerolog.TraceLevel
+	}
+	return s&levelBit(level) != 0
+}
+
+func levelBit(level zerolog.Level) levelSet {
+	// Trace level is -1, so shift all levels by one
+	return 1 << uint(level+1)
+}
@@ -0,0 +1,122 @@
+package logze_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestCallerLevelsDefault(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+
+	logger.Trace("trace message")
+	if !strings.Contains(b.String(), `"caller":"`) || !strings.Contains(b.String(), "caller_test.go:") {
+		t.Errorf("expected caller in trace level, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Debug("debug message")
+	if strings.Contains(b.String(), `"caller"`) {
+		t.Errorf("expected no caller in debug level, got %s", b.String())
+	}
+}
+
+func TestCallerLevels(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode().WithCallerLevels(logze.LevelDebug, logze.LevelError)
+	logger := logze.New(cfg)
+
+	logger.Debug("debug message")
+	logger.Errorf("error message %d", 1)
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.Contains(line, "caller_test.go:") {
+			t.Errorf("expected caller with test file, got %s", line)
+		}
+	}
+
+	b.Reset()
+	logger.Trace("trace message")
+	logger.Info("info message")
+	if strings.Contains(b.String(), `"caller"`) {
+		t.Errorf("expected no caller, got %s", b.String())
+	}
+
+	b.Reset()
+	logger = logze.New(cfg.WithCallerLevels())
+	logger.Trace("trace message")
+	if strings.Contains(b.String(), `"caller"`) {
+		t.Errorf("expected no caller, got %s", b.String())
+	}
+}
+
+func TestGlobalCallerLevels(t *testing.T) {
+	var b bytes.Buffer
+	logze.Init(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode().WithCallerLevels(logze.LevelTrace, logze.LevelInfo))
+
+	logze.Trace("trace message")
+	logze.Tracef("trace message %d", 1)
+	logze.Info("info message")
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.Contains(line, "caller_test.go:") {
+			t.Errorf("expected caller with test file, got %s", line)
+		}
+	}
+}
+
+func TestValidateCallerLevels(t *testing.T) {
+	err := logze.NewConfig(&bytes.Buffer{}).WithCallerLevels("unknown").Validate()
+	if err == nil || !strings.Contains(err.Error(), "caller level") {
+		t.Errorf("expected invalid caller level error, got %v", err)
+	}
+}
+
+func logFromHelper(lg logze.Logger) {
+	lg.WithCallerSkip(1).Info("from helper")
+}
+
+func TestCallerSkip(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithCallerLevels(logze.LevelInfo))
+
+	_, _, line, _ := runtime.Caller(0)
+	logFromHelper(logger)
+	if expected := fmt.Sprintf("caller_test.go:%d", line+1); !strings.Contains(b.String(), expected) {
+		t.Errorf("expected caller %s, got %s", expected, b.String())
+	}
+
+	b.Reset()
+	logger = logze.New(logze.NewConfig(&b).WithNoDiode().WithCallerLevels(logze.LevelInfo).WithCallerSkip(1))
+	_, _, line, _ = runtime.Caller(0)
+	func() {
+		logger.Info("from closure")
+	}()
+	if expected := fmt.Sprintf("caller_test.go:%d", line+3); !strings.Contains(b.String(), expected) {
+		t.Errorf("expected caller %s, got %s", expected, b.String())
+	}
+}
+
+func TestCallerMarshaler(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithNoDiode().WithCallerLevels(logze.LevelInfo).WithErrorCaller()
+
+	logze.New(cfg.WithCallerMarshaler(logze.ShortCaller)).Info("short")
+	_, _, line, _ := runtime.Caller(0)
+	if expected := fmt.Sprintf(`/caller_test.go:%d"`, line-1); !strings.Contains(b.String(), expected) {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+	if strings.Contains(b.String(), `"caller":"/`) {
+		t.Errorf("expected short path, got %s", b.String())
+	}
+
+	b.Reset()
+	logze.New(cfg.WithCallerMarshaler(logze.FuncCaller)).Error("func")
+	_, _, line, _ = runtime.Caller(0)
+	if expected := fmt.Sprintf(`/caller_test.go:%d TestCallerMarshaler"`, line-1); !strings.Contains(b.String(), expected) {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+}
@@ -0,0 +1,35 @@
+package logze
+
+// CauseFieldName is a field name for a list of messages of all wrap layers of an error,
+// it is added if stack trace is enabled, see [Config.WithStackTrace].
+var CauseFieldName = "cause"
+
+// maxCauseDepth limits the number of unwrapped errors to protect from cycles in Unwrap chains.
+const maxCauseDepth = 32
+
+// causeChain returns messages of the error and all errors it wraps, that are obtained with repeated Unwrap
+// (or Cause of github.com/pkg/errors). Layers that don't change the message, like errors.WithStack, are skipped.
+// It returns nil if the error doesn't wrap other errors.
+func causeChain(err error) []string {
+	var chain []string
+	for i := 0; err != nil && i < maxCauseDepth; i++ {
+		if msg := err.Error(); len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+		err = unwrapCause(err)
+	}
+	if len(chain) < 2 {
+		return nil
+	}
+	return chain
+}
+
+func unwrapCause(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	}
+	return nil
+}
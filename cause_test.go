@@ -0,0 +1,51 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestCauseChain(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithStackTrace())
+
+	base := errors.New("connection refused")
+	err := fmt.Errorf("query users: %w", pkgerrors.Wrap(base, "dial db"))
+	logger.Err(err, "cannot handle request")
+
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cause, ok := entry[logze.CauseFieldName].([]any)
+	if !ok {
+		t.Fatalf("expected cause array, got %s", b.String())
+	}
+	expected := []string{"query users: dial db: connection refused", "dial db: connection refused", "connection refused"}
+	if len(cause) != len(expected) {
+		t.Fatalf("expected %d causes, got %v", len(expected), cause)
+	}
+	for i, msg := range expected {
+		if cause[i] != msg {
+			t.Errorf("expected cause #%d %q, got %q", i, msg, cause[i])
+		}
+	}
+
+	b.Reset()
+	logger.Err(base, "not wrapped")
+	if bytes.Contains(b.Bytes(), []byte(`"cause"`)) {
+		t.Errorf("expected no cause for not wrapped error, got %s", b.String())
+	}
+
+	b.Reset()
+	logze.New(logze.NewConfig(&b).WithNoDiode()).Err(err, "no stack trace")
+	if bytes.Contains(b.Bytes(), []byte(`"cause"`)) {
+		t.Errorf("expected no cause without stack trace, got %s", b.String())
+	}
+}
@@ -0,0 +1,222 @@
+package logze
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CEFOptions is using for creating a CEF writer with [NewCEFWriter] or [Config.WithCEF].
+type CEFOptions struct {
+	// LEEF if true, will write IBM QRadar LEEF 1.0 lines instead of ArcSight CEF.
+	LEEF bool
+
+	// Vendor is a vendor of a device in a header. Default value is "logze".
+	Vendor string
+
+	// Product is a product of a device in a header. Default value is a name of the executable.
+	Product string
+
+	// Version is a version of a device in a header. Default value is "1.0".
+	Version string
+
+	// EventIDField is a name of a field of entries with an ID of an event (Signature ID in CEF, Event ID in LEEF).
+	// Default value is "event", a message is used if an entry doesn't have this field.
+	EventIDField string
+
+	// Mapping is a table of names of fields of entries to keys of extensions, e.g. "user" to "suser" and
+	// "ip" to "src". Only mapped fields are written if it is not empty, otherwise all fields are written
+	// with their own names.
+	Mapping map[string]string
+
+	// Filter selects entries that are written, e.g. audit and security events. Default value is nil, all entries are written.
+	Filter func(e Entry) bool
+}
+
+// CEFWriter converts JSON entries to ArcSight CEF or IBM LEEF lines for SIEM export and writes them
+// to the underlying writer. Severity is mapped from a level: trace is 1, debug is 2, info is 3, warn is 5,
+// error is 7, fatal is 9 and panic is 10. Time becomes rt extension in CEF and devTime in LEEF.
+// Use [NewCEFWriter] to create it.
+type CEFWriter struct {
+	w    io.Writer
+	opts CEFOptions
+}
+
+// NewCEFWriter returns [CEFWriter] that writes CEF or LEEF lines to w.
+func NewCEFWriter(w io.Writer, opts CEFOptions) *CEFWriter {
+	if opts.Vendor == "" {
+		opts.Vendor = "logze"
+	}
+	if opts.Product == "" {
+		opts.Product = filepath.Base(os.Args[0])
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0"
+	}
+	if opts.EventIDField == "" {
+		opts.EventIDField = "event"
+	}
+	return &CEFWriter{w: w, opts: opts}
+}
+
+// WithCEF returns [Config] with added [CEFWriter] that writes CEF or LEEF lines to w.
+// Usually it is used with [CEFOptions.Filter] to export only audit and security events to a SIEM.
+func (c Config) WithCEF(w io.Writer, opts CEFOptions) Config {
+	return c.WithWriter(NewCEFWriter(w, opts))
+}
+
+// Write converts an entry to a CEF or LEEF line and writes it, entries that are not selected by the filter
+// and data that is not a JSON entry are skipped.
+func (w *CEFWriter) Write(p []byte) (int, error) {
+	line, ok := w.format(p)
+	if !ok {
+		return len(p), nil
+	}
+	if _, err := w.w.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *CEFWriter) format(p []byte) ([]byte, bool) {
+	e, err := ParseEntry(p)
+	if err != nil {
+		return nil, false
+	}
+	if w.opts.Filter != nil && !w.opts.Filter(e) {
+		return nil, false
+	}
+
+	eventID := e.Message
+	if v, ok := e.Fields[w.opts.EventIDField]; ok {
+		eventID = cefString(v)
+		delete(e.Fields, w.opts.EventIDField)
+	}
+	ts := time.Now()
+	if v, ok := e.Fields[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			ts = t
+		}
+	}
+	delete(e.Fields, zerolog.TimestampFieldName)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		if len(w.opts.Mapping) == 0 || w.opts.Mapping[k] != "" {
+			keys = append(keys, k)
+		}
+	}
+	name := func(k string) string {
+		if mapped := w.opts.Mapping[k]; mapped != "" {
+			return mapped
+		}
+		return k
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return name(keys[i]) < name(keys[j])
+	})
+
+	var b strings.Builder
+	severity := strconv.Itoa(cefSeverity(e.Level))
+	if w.opts.LEEF {
+		b.WriteString("LEEF:1.0|")
+		for _, s := range []string{w.opts.Vendor, w.opts.Product, w.opts.Version, eventID} {
+			b.WriteString(leefHeader(s))
+			b.WriteByte('|')
+		}
+		b.WriteString("devTime=" + strconv.FormatInt(ts.UnixMilli(), 10))
+		b.WriteString("\tdevTimeFormat=epoch\tsev=" + severity)
+		if e.Message != "" {
+			b.WriteString("\tmsg=" + leefValue(e.Message))
+		}
+		for _, k := range keys {
+			b.WriteString("\t" + leefValue(name(k)) + "=" + leefValue(cefString(e.Fields[k])))
+		}
+	} else {
+		b.WriteString("CEF:0|")
+		for _, s := range []string{w.opts.Vendor, w.opts.Product, w.opts.Version, eventID, e.Message, severity} {
+			b.WriteString(cefHeader(s))
+			b.WriteByte('|')
+		}
+		b.WriteString("rt=" + strconv.FormatInt(ts.UnixMilli(), 10))
+		for _, k := range keys {
+			b.WriteString(" " + cefKey(name(k)) + "=" + cefValue(cefString(e.Fields[k])))
+		}
+	}
+	b.WriteByte('\n')
+	return []byte(b.String()), true
+}
+
+// cefSeverity returns a severity from 0 to 10 for a level.
+func cefSeverity(level string) int {
+	switch level {
+	case LevelTrace:
+		return 1
+	case LevelDebug:
+		return 2
+	case LevelInfo:
+		return 3
+	case LevelWarn:
+		return 5
+	case LevelError:
+		return 7
+	case LevelFatal:
+		return 9
+	case zerolog.LevelPanicValue:
+		return 10
+	}
+	return 0
+}
+
+// cefString returns a value of a field as a string, objects and arrays are encoded as JSON.
+func cefString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+var (
+	cefHeaderReplacer = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefValueReplacer  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+	leefReplacer      = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+)
+
+func cefHeader(s string) string {
+	return cefHeaderReplacer.Replace(s)
+}
+
+func cefValue(s string) string {
+	return cefValueReplacer.Replace(s)
+}
+
+// cefKey removes characters that are not allowed in keys of extensions.
+func cefKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == ' ' || r == '\\' || r == '\n' || r == '\r' {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func leefHeader(s string) string {
+	return strings.ReplaceAll(leefReplacer.Replace(s), "|", " ")
+}
+
+func leefValue(s string) string {
+	return leefReplacer.Replace(s)
+}
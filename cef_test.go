@@ -0,0 +1,54 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestCEFWriter(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig().WithNoDiode().WithCEF(&b, logze.CEFOptions{
+		Vendor:  "Acme",
+		Product: "Billing|API",
+		Mapping: map[string]string{"user": "suser", "ip": "src", "query": "request"},
+		Filter: func(e logze.Entry) bool {
+			return e.Fields["audit"] == true
+		},
+	}))
+
+	logger.Info("started")
+	if b.Len() != 0 {
+		t.Fatalf("expected filtered entry to be skipped, got %q", b.String())
+	}
+
+	logger.Warn("login failed", "audit", true, "event", "auth.failure", "user", "bob", "ip", "10.0.0.1", "query", "a=b\nc")
+	line := b.String()
+	if !strings.HasPrefix(line, `CEF:0|Acme|Billing\|API|1.0|auth.failure|login failed|5|rt=`) {
+		t.Errorf("expected CEF header, got %q", line)
+	}
+	if !strings.HasSuffix(line, ` request=a\=b\nc src=10.0.0.1 suser=bob`+"\n") {
+		t.Errorf("expected mapped and escaped extensions, got %q", line)
+	}
+	if strings.Contains(line, "audit=") {
+		t.Errorf("expected unmapped fields to be skipped, got %q", line)
+	}
+}
+
+func TestLEEFWriter(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(logze.NewCEFWriter(&b, logze.CEFOptions{LEEF: true, Product: "billing"})).WithNoDiode())
+
+	logger.Error("access denied", "user", "bob", "reason", "no\trole")
+	line := b.String()
+	if !strings.HasPrefix(line, "LEEF:1.0|logze|billing|1.0|access denied|devTime=") {
+		t.Errorf("expected LEEF header, got %q", line)
+	}
+	for _, expected := range []string{"\tsev=7", "\tmsg=access denied", "\treason=no role", "\tuser=bob\n"} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("expected %q in %q", expected, line)
+		}
+	}
+}
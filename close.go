@@ -0,0 +1,185 @@
+package logze
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Close flushes buffered entries of the logger (e.g. in diode) and flushes and closes its writers
+// that implement Flush() error or [io.Closer], except [os.Stdout], [os.Stderr] and console writers to them.
+// It returns a joined error describing which writers failed to flush or close and how many entries were lost
+// per writer and dropped by diode, so shutdown code can report loss of logs accurately.
+// The logger shouldn't be used after closing.
+func (l Logger) Close() error {
+	if l.out == nil {
+		return nil
+	}
+	out := l.out.load()
+
+	var errs []error
+	closeAsync(out.buffer)
+	if out.dropped != nil {
+		if n := out.dropped.n.Load(); n > 0 {
+			errs = append(errs, fmt.Errorf("%s: %d entries dropped", bufferName(out.buffer), n))
+		}
+	}
+	for i, w := range out.writers {
+		name := writerName(underlying(w.w))
+		if err := w.close(); err != nil {
+			errs = append(errs, fmt.Errorf("writer #%d (%s): %w", i, name, err))
+		}
+		if n, err := w.lostEntries(); n > 0 {
+			errs = append(errs, fmt.Errorf("writer #%d (%s): %d entries lost: %w", i, name, n, err))
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// Flush flushes writers of the logger that implement Flush() error (e.g. batching writers) without closing them,
+// so entries are delivered before a process is frozen, e.g. at the end of a serverless invocation.
+// Entries buffered in diode or async writer are not waited for, disable them with [Config.WithNoDiode].
+func (l Logger) Flush() error {
+	if l.out == nil {
+		return nil
+	}
+	var errs []error
+	for i, w := range l.out.load().writers {
+		dst := underlying(w.w)
+		if f, ok := dst.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, fmt.Errorf("writer #%d (%s): flush: %w", i, writerName(dst), err))
+			}
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// trackedWriter counts entries that are failed to be written to the underlying writer.
+type trackedWriter struct {
+	w    io.Writer
+	lost atomic.Int64
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+func trackWriters(writers []io.Writer) []*trackedWriter {
+	out := make([]*trackedWriter, len(writers))
+	for i, w := range writers {
+		out[i] = &trackedWriter{w: w}
+	}
+	return out
+}
+
+func (w *trackedWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.track(n, len(p), err)
+	return n, err
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *trackedWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	n, err := lw.WriteLevel(level, p)
+	w.track(n, len(p), err)
+	return n, err
+}
+
+func (w *trackedWriter) track(n, size int, err error) {
+	if err == nil && n == size {
+		return
+	}
+	if err == nil {
+		err = io.ErrShortWrite
+	}
+	w.lost.Add(1)
+	w.mu.Lock()
+	w.lastErr = err
+	w.mu.Unlock()
+}
+
+// lostEntries returns the number of entries that are failed to be written and the last error.
+func (w *trackedWriter) lostEntries() (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lost.Load(), w.lastErr
+}
+
+func (w *trackedWriter) close() error {
+	dst := underlying(w.w)
+	if f, ok := dst.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+	}
+	if isStdStream(dst) {
+		return nil
+	}
+	if c, ok := dst.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("close: %w", err)
+		}
+	}
+	return nil
+}
+
+// closeWriters closes writers like [Logger.Close] does, except [os.Stdout] and [os.Stderr].
+func closeWriters(writers []*trackedWriter) error {
+	var errs []error
+	for i, w := range writers {
+		if err := w.close(); err != nil {
+			errs = append(errs, fmt.Errorf("writer #%d (%s): %w", i, writerName(underlying(w.w)), err))
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// underlying returns a writer wrapped by writers that convert entries of the logger for field names,
+// time formats and output modes, so it is flushed, closed and named by itself.
+func underlying(w io.Writer) io.Writer {
+	for {
+		cw, ok := w.(convertWriter)
+		if !ok {
+			return w
+		}
+		w = cw.w
+	}
+}
+
+// isStdStream returns true if w writes to stdout or stderr directly or through a console writer,
+// such writers are not closed, because ConsoleWriter.Close closes its output.
+func isStdStream(w io.Writer) bool {
+	for {
+		switch cw := w.(type) {
+		case zerolog.ConsoleWriter:
+			w = cw.Out
+		case *zerolog.ConsoleWriter:
+			w = cw.Out
+		default:
+			return w == os.Stdout || w == os.Stderr
+		}
+	}
+}
+
+func writerName(w io.Writer) string {
+	if f, ok := w.(interface{ Name() string }); ok {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", w)
+}
+
+// bufferName returns a name of a writer that buffers entries of the logger in background.
+func bufferName(w io.Writer) string {
+	if _, ok := w.(*AsyncWriter); ok {
+		return "async writer"
+	}
+	return "diode"
+}
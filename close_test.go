@@ -0,0 +1,104 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(f, &b))
+
+	for i := 0; i < 10; i++ {
+		logger.Info("message", "i", i)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 10 {
+		t.Errorf("expected 10 flushed entries, got %d", n)
+	}
+	if _, err := f.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected file to be closed, got %v", err)
+	}
+	if err := logze.New(logze.NewConfig(os.Stderr)).Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stderr.Stat(); err != nil {
+		t.Errorf("expected stderr not to be closed, got %v", err)
+	}
+}
+
+func TestCloseLostEntries(t *testing.T) {
+	failing := errors.New("disk is full")
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b, errorWriter{err: failing}).WithNoDiode())
+
+	logger.Info("first")
+	logger.Info("second")
+
+	err := logger.Close()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "writer #1 (logze_test.errorWriter): 2 entries lost: disk is full") {
+		t.Errorf("expected lost entries of the failed writer, got %v", err)
+	}
+	if strings.Contains(err.Error(), "writer #0") {
+		t.Errorf("expected no errors of the working writer, got %v", err)
+	}
+}
+
+func TestCloseWrappedWriters(t *testing.T) {
+	for name, cfg := range map[string]logze.Config{
+		"field names": logze.NewConfig().WithFieldNames(logze.FieldNames{Message: "msg"}),
+		"ecs":         logze.NewConfig().WithECS(),
+		"gcp":         logze.NewConfig().WithGCP(),
+		"datadog":     logze.NewConfig().WithDatadog(logze.DatadogOptions{}),
+	} {
+		path := filepath.Join(t.TempDir(), "app.log")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger := logze.New(cfg.WithWriter(f).WithNoDiode())
+		logger.Info("message")
+		if err := logger.Close(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if _, err := f.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+			t.Errorf("%s: expected file to be closed, got %v", name, err)
+		}
+	}
+}
+
+func TestCloseConsoleKeepsStderr(t *testing.T) {
+	for name, cfg := range map[string]logze.Config{
+		"console":     logze.NewConfig().WithConsole().WithNoDiode(),
+		"development": logze.NewConfig().WithDevelopment().WithNoDiode(),
+	} {
+		logger := logze.New(cfg)
+		if err := logger.Close(); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if _, err := os.Stderr.Write(nil); err != nil {
+			t.Fatalf("%s: expected stderr to stay open after close, got %v", name, err)
+		}
+	}
+}
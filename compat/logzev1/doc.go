@@ -0,0 +1,14 @@
+// Package logze is a compatibility shim for github.com/maxbolgarin/logze (v1) import path
+// that forwards to github.com/maxbolgarin/logze/v2: types are aliases, functions are wrappers,
+// so loggers and configs can be passed between packages using v1 and v2 import paths.
+// It allows migrating a large codebase module-by-module without a big-bang import rewrite.
+//
+// Constants and variables are copied from v2 package during initialization,
+// so settings like field names should be changed in v2 package.
+//
+// The shim is generated from v2 package using go generate in the root of the repository.
+// It is a separate module github.com/maxbolgarin/logze/compat/logzev1 tagged with compat/logzev1/vX.Y.Z,
+// replace v1 module with it to keep v1 import paths working:
+//
+//	replace github.com/maxbolgarin/logze => github.com/maxbolgarin/logze/compat/logzev1 v1.0.0
+package logze
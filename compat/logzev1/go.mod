@@ -0,0 +1,19 @@
+module github.com/maxbolgarin/logze/compat/logzev1
+
+go 1.19
+
+require (
+	github.com/go-logr/logr v1.4.2
+	github.com/maxbolgarin/logze/v2 v2.0.0
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/maxbolgarin/logze/v2 => ../../
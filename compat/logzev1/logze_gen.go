@@ -0,0 +1,814 @@
+// Code generated by genglobal; DO NOT EDIT.
+
+package logze
+
+import (
+	"context"
+	"io"
+	stdlog "log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	v2 "github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+type (
+	AsyncOptions       = v2.AsyncOptions
+	AsyncWriter        = v2.AsyncWriter
+	CEFOptions         = v2.CEFOptions
+	CEFWriter          = v2.CEFWriter
+	Config             = v2.Config
+	ConfigWatcher      = v2.ConfigWatcher
+	ConsoleOptions     = v2.ConsoleOptions
+	DatadogOptions     = v2.DatadogOptions
+	DictField          = v2.DictField
+	DropCounter        = v2.DropCounter
+	Entry              = v2.Entry
+	EntryHook          = v2.EntryHook
+	EntryParser        = v2.EntryParser
+	ErrorCounter       = v2.ErrorCounter
+	Event              = v2.Event
+	FIFOOptions        = v2.FIFOOptions
+	FIFOWriter         = v2.FIFOWriter
+	FailoverOptions    = v2.FailoverOptions
+	FailoverWriter     = v2.FailoverWriter
+	FieldNames         = v2.FieldNames
+	FileAsyncConfig    = v2.FileAsyncConfig
+	FileConfig         = v2.FileConfig
+	FileDiodeConfig    = v2.FileDiodeConfig
+	Frame              = v2.Frame
+	GCPServiceContext  = v2.GCPServiceContext
+	Lambda             = v2.Lambda
+	LogfmtWriter       = v2.LogfmtWriter
+	Logger             = v2.Logger
+	Metric             = v2.Metric
+	NetOptions         = v2.NetOptions
+	NetWriter          = v2.NetWriter
+	NopStats           = v2.NopStats
+	ObjField           = v2.ObjField
+	OutputStats        = v2.OutputStats
+	PostWriteHook      = v2.PostWriteHook
+	RetryOptions       = v2.RetryOptions
+	RetryWriter        = v2.RetryWriter
+	SimpleErrorCounter = v2.SimpleErrorCounter
+	SyslogOptions      = v2.SyslogOptions
+	SyslogWriter       = v2.SyslogWriter
+	TracedReader       = v2.TracedReader
+	TracedWriter       = v2.TracedWriter
+	Valuer             = v2.Valuer
+	WatchOptions       = v2.WatchOptions
+)
+
+const (
+	AsyncBlock                   = v2.AsyncBlock
+	AsyncDropNewest              = v2.AsyncDropNewest
+	AsyncDropOldest              = v2.AsyncDropOldest
+	DefaultDiodePollingInterval  = v2.DefaultDiodePollingInterval
+	DefaultDiodeSize             = v2.DefaultDiodeSize
+	DefaultFIFOReopenInterval    = v2.DefaultFIFOReopenInterval
+	DefaultFIFOWriteTimeout      = v2.DefaultFIFOWriteTimeout
+	DefaultFailoverProbeInterval = v2.DefaultFailoverProbeInterval
+	DefaultNetDialTimeout        = v2.DefaultNetDialTimeout
+	DefaultNetReconnectInterval  = v2.DefaultNetReconnectInterval
+	DefaultNetWriteTimeout       = v2.DefaultNetWriteTimeout
+	DefaultRetryMaxBackoff       = v2.DefaultRetryMaxBackoff
+	DefaultRetryMinBackoff       = v2.DefaultRetryMinBackoff
+	DefaultRetryQueueSize        = v2.DefaultRetryQueueSize
+	DefaultTraceSampleEvery      = v2.DefaultTraceSampleEvery
+	DefaultWatchDebounce         = v2.DefaultWatchDebounce
+	EMFFieldName                 = v2.EMFFieldName
+	FeatureAlert                 = v2.FeatureAlert
+	FeatureAppInsights           = v2.FeatureAppInsights
+	FeatureConfigFile            = v2.FeatureConfigFile
+	FeatureConfigWatch           = v2.FeatureConfigWatch
+	FeatureConsole               = v2.FeatureConsole
+	FeatureDiode                 = v2.FeatureDiode
+	FeatureEchoMiddleware        = v2.FeatureEchoMiddleware
+	FeatureElastic               = v2.FeatureElastic
+	FeatureEntryHooks            = v2.FeatureEntryHooks
+	FeatureGORMLogger            = v2.FeatureGORMLogger
+	FeatureGRPCMiddleware        = v2.FeatureGRPCMiddleware
+	FeatureGinMiddleware         = v2.FeatureGinMiddleware
+	FeatureGzip                  = v2.FeatureGzip
+	FeatureHTTPMiddleware        = v2.FeatureHTTPMiddleware
+	FeatureJournald              = v2.FeatureJournald
+	FeatureKafka                 = v2.FeatureKafka
+	FeatureLevelHandler          = v2.FeatureLevelHandler
+	FeatureLogrusHook            = v2.FeatureLogrusHook
+	FeatureLoki                  = v2.FeatureLoki
+	FeatureNATS                  = v2.FeatureNATS
+	FeatureNamed                 = v2.FeatureNamed
+	FeatureOTel                  = v2.FeatureOTel
+	FeatureRedis                 = v2.FeatureRedis
+	FeatureSentry                = v2.FeatureSentry
+	FeatureWebhook               = v2.FeatureWebhook
+	FeatureZapCompat             = v2.FeatureZapCompat
+	FeatureZstd                  = v2.FeatureZstd
+	FormatConsole                = v2.FormatConsole
+	FormatConsoleNoColor         = v2.FormatConsoleNoColor
+	FormatJSON                   = v2.FormatJSON
+	FormatLogfmt                 = v2.FormatLogfmt
+	GCPErrorReportingType        = v2.GCPErrorReportingType
+	GCPSeverityFieldName         = v2.GCPSeverityFieldName
+	GCPSourceLocationFieldName   = v2.GCPSourceLocationFieldName
+	GCPSpanIDFieldName           = v2.GCPSpanIDFieldName
+	GCPTraceFieldName            = v2.GCPTraceFieldName
+	LevelDebug                   = v2.LevelDebug
+	LevelDisabled                = v2.LevelDisabled
+	LevelError                   = v2.LevelError
+	LevelFatal                   = v2.LevelFatal
+	LevelInfo                    = v2.LevelInfo
+	LevelTrace                   = v2.LevelTrace
+	LevelWarn                    = v2.LevelWarn
+	MaxCachedLoggers             = v2.MaxCachedLoggers
+	NilErrorsError               = v2.NilErrorsError
+	NilErrorsInfo                = v2.NilErrorsInfo
+	NilErrorsPanic               = v2.NilErrorsPanic
+	NilErrorsSkip                = v2.NilErrorsSkip
+	NoWritersDiscard             = v2.NoWritersDiscard
+	NoWritersError               = v2.NoWritersError
+	NoWritersStderr              = v2.NoWritersStderr
+	NoWritersWarn                = v2.NoWritersWarn
+	SyslogRFC3164                = v2.SyslogRFC3164
+	SyslogRFC5424                = v2.SyslogRFC5424
+	TimeFormatHighRes            = v2.TimeFormatHighRes
+	UnitBytes                    = v2.UnitBytes
+	UnitCount                    = v2.UnitCount
+	UnitCountSecond              = v2.UnitCountSecond
+	UnitMicroseconds             = v2.UnitMicroseconds
+	UnitMilliseconds             = v2.UnitMilliseconds
+	UnitNone                     = v2.UnitNone
+	UnitPercent                  = v2.UnitPercent
+	UnitSeconds                  = v2.UnitSeconds
+	Version                      = v2.Version
+	WriterConsole                = v2.WriterConsole
+	WriterConsoleNoColor         = v2.WriterConsoleNoColor
+	WriterFIFOPrefix             = v2.WriterFIFOPrefix
+	WriterLogfmt                 = v2.WriterLogfmt
+	WriterStderr                 = v2.WriterStderr
+	WriterStdout                 = v2.WriterStdout
+	WriterUnixPrefix             = v2.WriterUnixPrefix
+)
+
+var (
+	CauseFieldName           = v2.CauseFieldName
+	DatadogSpanIDSourceName  = v2.DatadogSpanIDSourceName
+	DatadogTraceIDSourceName = v2.DatadogTraceIDSourceName
+	DefaultStdPrefixLevels   = v2.DefaultStdPrefixLevels
+	ECSVersion               = v2.ECSVersion
+	ErrReemitLoop            = v2.ErrReemitLoop
+	ErrRetryQueueFull        = v2.ErrRetryQueueFull
+	ErrorCallerFieldName     = v2.ErrorCallerFieldName
+	Formats                  = v2.Formats
+	GCPSpanIDSourceName      = v2.GCPSpanIDSourceName
+	GCPTraceIDSourceName     = v2.GCPTraceIDSourceName
+	GoroutinesFieldName      = v2.GoroutinesFieldName
+	LambdaColdStartFieldName = v2.LambdaColdStartFieldName
+	LambdaRequestIDFieldName = v2.LambdaRequestIDFieldName
+	Levels                   = v2.Levels
+	LevelsAny                = v2.LevelsAny
+	LogfmtMessageFieldName   = v2.LogfmtMessageFieldName
+	LoggerFieldName          = v2.LoggerFieldName
+	MaxReemitDepth           = v2.MaxReemitDepth
+	NopStatsLastMessages     = v2.NopStatsLastMessages
+	ReemitFieldName          = v2.ReemitFieldName
+	StackSourceFileName      = v2.StackSourceFileName
+	StackSourceFunctionName  = v2.StackSourceFunctionName
+	StackSourceLineName      = v2.StackSourceLineName
+	StreamFieldName          = v2.StreamFieldName
+	SummaryFieldName         = v2.SummaryFieldName
+	WarningFieldName         = v2.WarningFieldName
+)
+
+// AddPostWriteHook calls [v2.AddPostWriteHook].
+func AddPostWriteHook(hook PostWriteHook) func() {
+	return v2.AddPostWriteHook(hook)
+}
+
+// C calls [v2.C].
+func C(writers ...io.Writer) Config {
+	return v2.C(writers...)
+}
+
+// CachedWith calls [v2.CachedWith].
+func CachedWith(key string, fields ...any) Logger {
+	return v2.CachedWith(key, fields...)
+}
+
+// Close calls [v2.Close].
+func Close() error {
+	return v2.Close()
+}
+
+// DPanic calls [v2.DPanic].
+func DPanic(msg string, fields ...any) {
+	v2.DPanic(msg, fields...)
+}
+
+// DPanicf calls [v2.DPanicf].
+func DPanicf(msg string, args ...any) {
+	v2.DPanicf(msg, args...)
+}
+
+// Debug calls [v2.Debug].
+func Debug(msg string, fields ...any) {
+	v2.Debug(msg, fields...)
+}
+
+// Debugf calls [v2.Debugf].
+func Debugf(msg string, args ...any) {
+	v2.Debugf(msg, args...)
+}
+
+// DebugfOnly calls [v2.DebugfOnly].
+func DebugfOnly(format string, args ...any) {
+	v2.DebugfOnly(format, args...)
+}
+
+// Debugw calls [v2.Debugw].
+func Debugw(msg string, fields ...any) {
+	v2.Debugw(msg, fields...)
+}
+
+// Default calls [v2.Default].
+func Default() Logger {
+	return v2.Default()
+}
+
+// DefaultPtr calls [v2.DefaultPtr].
+func DefaultPtr() *Logger {
+	return v2.DefaultPtr()
+}
+
+// Dict calls [v2.Dict].
+func Dict(key string, fields ...any) DictField {
+	return v2.Dict(key, fields...)
+}
+
+// DurationMetric calls [v2.DurationMetric].
+func DurationMetric(name string, d time.Duration) Metric {
+	return v2.DurationMetric(name, d)
+}
+
+// E calls [v2.E].
+func E(level string) *Event {
+	return v2.E(level)
+}
+
+// Emit calls [v2.Emit].
+func Emit(e Entry) error {
+	return v2.Emit(e)
+}
+
+// EnableSignalToggle calls [v2.EnableSignalToggle].
+func EnableSignalToggle(sig os.Signal) func() {
+	return v2.EnableSignalToggle(sig)
+}
+
+// Enabled calls [v2.Enabled].
+func Enabled(level string) bool {
+	return v2.Enabled(level)
+}
+
+// Err calls [v2.Err].
+func Err(err error, msg string, fields ...any) {
+	v2.Err(err, msg, fields...)
+}
+
+// ErrStack calls [v2.ErrStack].
+func ErrStack(err error, fields ...any) {
+	v2.ErrStack(err, fields...)
+}
+
+// Errf calls [v2.Errf].
+func Errf(err error, msg string, args ...any) {
+	v2.Errf(err, msg, args...)
+}
+
+// ErrfOnly calls [v2.ErrfOnly].
+func ErrfOnly(err error, format string, args ...any) {
+	v2.ErrfOnly(err, format, args...)
+}
+
+// Error calls [v2.Error].
+func Error(msg string, fields ...any) {
+	v2.Error(msg, fields...)
+}
+
+// Errorf calls [v2.Errorf].
+func Errorf(msg string, args ...any) {
+	v2.Errorf(msg, args...)
+}
+
+// ErrorfOnly calls [v2.ErrorfOnly].
+func ErrorfOnly(format string, args ...any) {
+	v2.ErrorfOnly(format, args...)
+}
+
+// Errorw calls [v2.Errorw].
+func Errorw(msg string, fields ...any) {
+	v2.Errorw(msg, fields...)
+}
+
+// Fatal calls [v2.Fatal].
+func Fatal(v ...any) {
+	v2.Fatal(v...)
+}
+
+// Fatalf calls [v2.Fatalf].
+func Fatalf(format string, args ...any) {
+	v2.Fatalf(format, args...)
+}
+
+// Fatalln calls [v2.Fatalln].
+func Fatalln(v ...any) {
+	v2.Fatalln(v...)
+}
+
+// Features calls [v2.Features].
+func Features() []string {
+	return v2.Features()
+}
+
+// Flush calls [v2.Flush].
+func Flush() error {
+	return v2.Flush()
+}
+
+// FromContext calls [v2.FromContext].
+func FromContext(ctx context.Context) Logger {
+	return v2.FromContext(ctx)
+}
+
+// FuncCaller calls [v2.FuncCaller].
+func FuncCaller(pc uintptr, file string, line int) string {
+	return v2.FuncCaller(pc, file, line)
+}
+
+// GetErrorCounter calls [v2.GetErrorCounter].
+func GetErrorCounter() ErrorCounter {
+	return v2.GetErrorCounter()
+}
+
+// GetLevel calls [v2.GetLevel].
+func GetLevel() string {
+	return v2.GetLevel()
+}
+
+// GetNamedLevel calls [v2.GetNamedLevel].
+func GetNamedLevel(name string) string {
+	return v2.GetNamedLevel(name)
+}
+
+// HasFeature calls [v2.HasFeature].
+func HasFeature(name string) bool {
+	return v2.HasFeature(name)
+}
+
+// IfDebug calls [v2.IfDebug].
+func IfDebug(f func(l Logger)) {
+	v2.IfDebug(f)
+}
+
+// IfTrace calls [v2.IfTrace].
+func IfTrace(f func(l Logger)) {
+	v2.IfTrace(f)
+}
+
+// Info calls [v2.Info].
+func Info(msg string, fields ...any) {
+	v2.Info(msg, fields...)
+}
+
+// Infof calls [v2.Infof].
+func Infof(msg string, args ...any) {
+	v2.Infof(msg, args...)
+}
+
+// InfofOnly calls [v2.InfofOnly].
+func InfofOnly(format string, args ...any) {
+	v2.InfofOnly(format, args...)
+}
+
+// Infow calls [v2.Infow].
+func Infow(msg string, fields ...any) {
+	v2.Infow(msg, fields...)
+}
+
+// Init calls [v2.Init].
+func Init(cfg Config, fields ...any) {
+	v2.Init(cfg, fields...)
+}
+
+// IsDebug calls [v2.IsDebug].
+func IsDebug() bool {
+	return v2.IsDebug()
+}
+
+// IsTrace calls [v2.IsTrace].
+func IsTrace() bool {
+	return v2.IsTrace()
+}
+
+// LevelHandler calls [v2.LevelHandler].
+func LevelHandler() http.Handler {
+	return v2.LevelHandler()
+}
+
+// LoadConfig calls [v2.LoadConfig].
+func LoadConfig(path string) (Config, error) {
+	return v2.LoadConfig(path)
+}
+
+// Log calls [v2.Log].
+func Log(v ...any) {
+	v2.Log(v...)
+}
+
+// LogAttrs calls [v2.LogAttrs].
+func LogAttrs(level string, msg string, fields []any) {
+	v2.LogAttrs(level, msg, fields)
+}
+
+// Metrics calls [v2.Metrics].
+func Metrics(namespace string, dimensions map[string]string, metrics ...Metric) {
+	v2.Metrics(namespace, dimensions, metrics...)
+}
+
+// Named calls [v2.Named].
+func Named(name string) Logger {
+	return v2.Named(name)
+}
+
+// NamedLevels calls [v2.NamedLevels].
+func NamedLevels() map[string]string {
+	return v2.NamedLevels()
+}
+
+// New calls [v2.New].
+func New(cfg Config, fields ...any) Logger {
+	return v2.New(cfg, fields...)
+}
+
+// NewAsyncWriter calls [v2.NewAsyncWriter].
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) *AsyncWriter {
+	return v2.NewAsyncWriter(w, opts)
+}
+
+// NewCEFWriter calls [v2.NewCEFWriter].
+func NewCEFWriter(w io.Writer, opts CEFOptions) *CEFWriter {
+	return v2.NewCEFWriter(w, opts)
+}
+
+// NewConfig calls [v2.NewConfig].
+func NewConfig(writers ...io.Writer) Config {
+	return v2.NewConfig(writers...)
+}
+
+// NewConsoleJSON calls [v2.NewConsoleJSON].
+func NewConsoleJSON(fields ...any) Logger {
+	return v2.NewConsoleJSON(fields...)
+}
+
+// NewConsoleWriter calls [v2.NewConsoleWriter].
+func NewConsoleWriter(opts ConsoleOptions) zerolog.ConsoleWriter {
+	return v2.NewConsoleWriter(opts)
+}
+
+// NewFIFOWriter calls [v2.NewFIFOWriter].
+func NewFIFOWriter(path string, opts FIFOOptions) *FIFOWriter {
+	return v2.NewFIFOWriter(path, opts)
+}
+
+// NewFailoverWriter calls [v2.NewFailoverWriter].
+func NewFailoverWriter(primary io.Writer, secondary io.Writer, opts FailoverOptions) *FailoverWriter {
+	return v2.NewFailoverWriter(primary, secondary, opts)
+}
+
+// NewFromZerolog calls [v2.NewFromZerolog].
+func NewFromZerolog(l zerolog.Logger) Logger {
+	return v2.NewFromZerolog(l)
+}
+
+// NewLambda calls [v2.NewLambda].
+func NewLambda(fields ...any) *Lambda {
+	return v2.NewLambda(fields...)
+}
+
+// NewLogfmtWriter calls [v2.NewLogfmtWriter].
+func NewLogfmtWriter(w io.Writer) *LogfmtWriter {
+	return v2.NewLogfmtWriter(w)
+}
+
+// NewLogrSink calls [v2.NewLogrSink].
+func NewLogrSink(l Logger) logr.LogSink {
+	return v2.NewLogrSink(l)
+}
+
+// NewNetWriter calls [v2.NewNetWriter].
+func NewNetWriter(network string, addr string, opts NetOptions) *NetWriter {
+	return v2.NewNetWriter(network, addr, opts)
+}
+
+// NewRetryWriter calls [v2.NewRetryWriter].
+func NewRetryWriter(w io.Writer, opts RetryOptions) *RetryWriter {
+	return v2.NewRetryWriter(w, opts)
+}
+
+// NewSyslogWriter calls [v2.NewSyslogWriter].
+func NewSyslogWriter(opts SyslogOptions) (*SyslogWriter, error) {
+	return v2.NewSyslogWriter(opts)
+}
+
+// NewTimeFormatWriter calls [v2.NewTimeFormatWriter].
+func NewTimeFormatWriter(w io.Writer, format string, loc *time.Location) io.Writer {
+	return v2.NewTimeFormatWriter(w, format, loc)
+}
+
+// NewUnixWriter calls [v2.NewUnixWriter].
+func NewUnixWriter(path string, opts NetOptions) *NetWriter {
+	return v2.NewUnixWriter(path, opts)
+}
+
+// Nop calls [v2.Nop].
+func Nop() Logger {
+	return v2.Nop()
+}
+
+// NopWithStats calls [v2.NopWithStats].
+func NopWithStats() (Logger, *NopStats) {
+	return v2.NopWithStats()
+}
+
+// NotInited calls [v2.NotInited].
+func NotInited() bool {
+	return v2.NotInited()
+}
+
+// Obj calls [v2.Obj].
+func Obj(v any) ObjField {
+	return v2.Obj(v)
+}
+
+// Panic calls [v2.Panic].
+func Panic(v ...any) {
+	v2.Panic(v...)
+}
+
+// Panicf calls [v2.Panicf].
+func Panicf(format string, args ...any) {
+	v2.Panicf(format, args...)
+}
+
+// Panicln calls [v2.Panicln].
+func Panicln(v ...any) {
+	v2.Panicln(v...)
+}
+
+// ParseConfig calls [v2.ParseConfig].
+func ParseConfig(data []byte) (Config, error) {
+	return v2.ParseConfig(data)
+}
+
+// ParseEntry calls [v2.ParseEntry].
+func ParseEntry(p []byte) (Entry, error) {
+	return v2.ParseEntry(p)
+}
+
+// Print calls [v2.Print].
+func Print(v ...any) {
+	v2.Print(v...)
+}
+
+// PrintStack calls [v2.PrintStack].
+func PrintStack(v ...any) {
+	v2.PrintStack(v...)
+}
+
+// Printf calls [v2.Printf].
+func Printf(format string, args ...any) {
+	v2.Printf(format, args...)
+}
+
+// Println calls [v2.Println].
+func Println(v ...any) {
+	v2.Println(v...)
+}
+
+// Raw calls [v2.Raw].
+func Raw() *zerolog.Logger {
+	return v2.Raw()
+}
+
+// RegisterFeature calls [v2.RegisterFeature].
+func RegisterFeature(name string) {
+	v2.RegisterFeature(name)
+}
+
+// Reload calls [v2.Reload].
+func Reload(cfg Config) error {
+	return v2.Reload(cfg)
+}
+
+// ResetLevel calls [v2.ResetLevel].
+func ResetLevel() {
+	v2.ResetLevel()
+}
+
+// ResetNamedLevel calls [v2.ResetNamedLevel].
+func ResetNamedLevel(name string) {
+	v2.ResetNamedLevel(name)
+}
+
+// SetDefault calls [v2.SetDefault].
+func SetDefault(l Logger) {
+	v2.SetDefault(l)
+}
+
+// SetLevel calls [v2.SetLevel].
+func SetLevel(level string) error {
+	return v2.SetLevel(level)
+}
+
+// SetNamedLevel calls [v2.SetNamedLevel].
+func SetNamedLevel(name string, level string) error {
+	return v2.SetNamedLevel(name, level)
+}
+
+// SetStdLogger calls [v2.SetStdLogger].
+func SetStdLogger(l Logger, fields ...any) {
+	v2.SetStdLogger(l, fields...)
+}
+
+// SetStdLoggerLevels calls [v2.SetStdLoggerLevels].
+func SetStdLoggerLevels(l Logger, prefixes map[string]string, fields ...any) {
+	v2.SetStdLoggerLevels(l, prefixes, fields...)
+}
+
+// ShortCaller calls [v2.ShortCaller].
+func ShortCaller(arg0_0 uintptr, file string, line int) string {
+	return v2.ShortCaller(arg0_0, file, line)
+}
+
+// SkipVendorFrames calls [v2.SkipVendorFrames].
+func SkipVendorFrames(frame Frame) bool {
+	return v2.SkipVendorFrames(frame)
+}
+
+// Stats calls [v2.Stats].
+func Stats() OutputStats {
+	return v2.Stats()
+}
+
+// StdLogAt calls [v2.StdLogAt].
+func StdLogAt(level string) *stdlog.Logger {
+	return v2.StdLogAt(level)
+}
+
+// StdLogLevels calls [v2.StdLogLevels].
+func StdLogLevels(defaultLevel string, prefixes map[string]string) *stdlog.Logger {
+	return v2.StdLogLevels(defaultLevel, prefixes)
+}
+
+// Trace calls [v2.Trace].
+func Trace(msg string, fields ...any) {
+	v2.Trace(msg, fields...)
+}
+
+// TraceReader calls [v2.TraceReader].
+func TraceReader(r io.Reader, lg Logger, name string) *TracedReader {
+	return v2.TraceReader(r, lg, name)
+}
+
+// TraceWriter calls [v2.TraceWriter].
+func TraceWriter(w io.Writer, lg Logger, name string) *TracedWriter {
+	return v2.TraceWriter(w, lg, name)
+}
+
+// Tracef calls [v2.Tracef].
+func Tracef(msg string, args ...any) {
+	v2.Tracef(msg, args...)
+}
+
+// TracefOnly calls [v2.TracefOnly].
+func TracefOnly(format string, args ...any) {
+	v2.TracefOnly(format, args...)
+}
+
+// Tracew calls [v2.Tracew].
+func Tracew(msg string, fields ...any) {
+	v2.Tracew(msg, fields...)
+}
+
+// TryNew calls [v2.TryNew].
+func TryNew(cfg Config, fields ...any) (Logger, error) {
+	return v2.TryNew(cfg, fields...)
+}
+
+// Update calls [v2.Update].
+func Update(cfg Config, fields ...any) {
+	v2.Update(cfg, fields...)
+}
+
+// Warn calls [v2.Warn].
+func Warn(msg string, fields ...any) {
+	v2.Warn(msg, fields...)
+}
+
+// Warnf calls [v2.Warnf].
+func Warnf(msg string, args ...any) {
+	v2.Warnf(msg, args...)
+}
+
+// WarnfOnly calls [v2.WarnfOnly].
+func WarnfOnly(format string, args ...any) {
+	v2.WarnfOnly(format, args...)
+}
+
+// Warnw calls [v2.Warnw].
+func Warnw(msg string, fields ...any) {
+	v2.Warnw(msg, fields...)
+}
+
+// WatchConfig calls [v2.WatchConfig].
+func WatchConfig(path string, opts WatchOptions) (*ConfigWatcher, error) {
+	return v2.WatchConfig(path, opts)
+}
+
+// With calls [v2.With].
+func With(fields ...any) Logger {
+	return v2.With(fields...)
+}
+
+// WithCallerSkip calls [v2.WithCallerSkip].
+func WithCallerSkip(skip int) Logger {
+	return v2.WithCallerSkip(skip)
+}
+
+// WithContext calls [v2.WithContext].
+func WithContext(ctx context.Context) context.Context {
+	return v2.WithContext(ctx)
+}
+
+// WithErrorCounter calls [v2.WithErrorCounter].
+func WithErrorCounter(ec ErrorCounter) Logger {
+	return v2.WithErrorCounter(ec)
+}
+
+// WithFields calls [v2.WithFields].
+func WithFields(fields ...any) Logger {
+	return v2.WithFields(fields...)
+}
+
+// WithGroup calls [v2.WithGroup].
+func WithGroup(name string) Logger {
+	return v2.WithGroup(name)
+}
+
+// WithLevel calls [v2.WithLevel].
+func WithLevel(level string) Logger {
+	return v2.WithLevel(level)
+}
+
+// WithSimpleErrorCounter calls [v2.WithSimpleErrorCounter].
+func WithSimpleErrorCounter() Logger {
+	return v2.WithSimpleErrorCounter()
+}
+
+// WithStack calls [v2.WithStack].
+func WithStack(stackTrace bool) Logger {
+	return v2.WithStack(stackTrace)
+}
+
+// WithStruct calls [v2.WithStruct].
+func WithStruct(v any) Logger {
+	return v2.WithStruct(v)
+}
+
+// WithToIgnore calls [v2.WithToIgnore].
+func WithToIgnore(toIgnore ...string) Logger {
+	return v2.WithToIgnore(toIgnore...)
+}
+
+// WrapLambda calls [v2.WrapLambda].
+func WrapLambda[In, Out any](l *Lambda, h func(ctx context.Context, in In) (Out, error)) func(ctx context.Context, in In) (Out, error) {
+	return v2.WrapLambda(l, h)
+}
+
+// Write calls [v2.Write].
+func Write(p []byte) (int, error) {
+	return v2.Write(p)
+}
+
+// WriterLevel calls [v2.WriterLevel].
+func WriterLevel(level string) io.WriteCloser {
+	return v2.WriterLevel(level)
+}
+
+// WriterStream calls [v2.WriterStream].
+func WriterStream(level string, stream string) io.WriteCloser {
+	return v2.WriterStream(level, stream)
+}
@@ -0,0 +1,26 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	logze "github.com/maxbolgarin/logze/compat/logzev1"
+	v2 "github.com/maxbolgarin/logze/v2"
+)
+
+func TestShim(t *testing.T) {
+	var b bytes.Buffer
+	var logger v2.Logger = logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelDebug))
+	logger.Debug("debug message", "foo", "bar")
+	if !strings.Contains(b.String(), `"message":"debug message"`) {
+		t.Errorf("expected message, got %s", b.String())
+	}
+
+	b.Reset()
+	logze.SetDefault(logger)
+	v2.Info("info message")
+	if !strings.Contains(b.String(), `"message":"info message"`) {
+		t.Errorf("expected message from global logger, got %s", b.String())
+	}
+}
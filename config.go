@@ -27,6 +27,34 @@ const (
 	LevelDisabled = "disabled"
 )
 
+// Enumerating modes of behavior when there are no writers in [Config], see [Config.WithNoWriters].
+const (
+	// NoWritersDiscard silently discards all logs, it is a default mode.
+	NoWritersDiscard = "discard"
+	// NoWritersWarn discards all logs and writes a warning to stderr once per process.
+	NoWritersWarn = "warn"
+	// NoWritersStderr writes logs to stderr in JSON format.
+	NoWritersStderr = "stderr"
+	// NoWritersError makes [TryNew] return an error and [New] panic.
+	NoWritersError = "error"
+)
+
+// Enumerating modes of handling nil errors passed to [Logger.Err] and [Logger.Errf], see [Config.WithNilErrors].
+const (
+	// NilErrorsError logs a message in error level without error field, it is a default mode.
+	NilErrorsError = "error"
+	// NilErrorsInfo logs a message in info level with "logze_warning":"nil error" field.
+	NilErrorsInfo = "info"
+	// NilErrorsSkip doesn't log a message.
+	NilErrorsSkip = "skip"
+	// NilErrorsPanic panics to catch a bug in development mode (see [Config.WithDevelopment]),
+	// otherwise it logs a message like [NilErrorsError].
+	NilErrorsPanic = "panic"
+)
+
+// WarningFieldName is a field name for warnings about misuse of [Logger], e.g. logging of a nil error.
+var WarningFieldName = "logze_warning"
+
 // Levels is a list of all supported levels in string format.
 var Levels = []string{
 	LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal, LevelDisabled,
@@ -41,9 +69,13 @@ var LevelsAny = []any{
 // a [Config] struct directly.
 type Config struct {
 	// Writers is a list of writers where logger will log its data.
-	// Default value is [io.Discard].
+	// Default value is [io.Discard], use NoWriters to change this behavior.
 	Writers []io.Writer
 
+	// NoWriters is a mode of behavior when there are no writers: discard, warn, stderr or error.
+	// Default value is discard.
+	NoWriters string
+
 	// Level is a log level in string format. Supported levels are:
 	// trace, debug, info, warn, error, fatal, disabled.
 	Level string
@@ -51,9 +83,22 @@ type Config struct {
 	// TimeFieldFormat is a format for time field. Default value is RFC3339.
 	// You can use values from zerolog like [zerolog.TimeFormatUnix], [zerolog.TimeFormatUnixMs],
 	// [zerolog.TimeFormatUnixMicro], [zerolog.TimeFormatUnixNano], [time.RFC3339], [time.RFC3339Nano] or custom.
-	// UNIX Time is faster and smaller than most timestamps
+	// UNIX Time is faster and smaller than most timestamps.
+	// Format is applied per logger, global [zerolog.TimeFieldFormat] is not changed.
 	TimeFieldFormat string
 
+	// NoTimestamp if true, will disable time field.
+	// Default value is false.
+	NoTimestamp bool
+
+	// Clock is a function that returns current time for the time field.
+	// Default value is nil, [zerolog.TimestampFunc] is used.
+	Clock func() time.Time
+
+	// HighResTimestamps if true, will enable strictly increasing timestamps with nanosecond precision
+	// derived from a monotonic clock. Default value is false.
+	HighResTimestamps bool
+
 	// Hook is a zerolog.Hook that will be used when creating logger.
 	// Default value is nil.
 	Hook zerolog.Hook
@@ -87,9 +132,110 @@ type Config struct {
 	// Default value is false.
 	NoDiode bool
 
-	// StackTrace if true, will enable stack trace for Error and Errorf methods.
+	// Async contains settings of an async writer that is used instead of diode if its policy is set,
+	// see [Config.WithAsync]. Default value is empty, diode is used.
+	Async AsyncOptions
+
+	// MaxThroughput is a max number of bytes per second written by the logger, see [Config.WithMaxThroughput].
+	// Default value is 0, output is not limited.
+	MaxThroughput int
+
+	// StackTrace if true, will enable stack trace for Error and Errorf methods
+	// and "cause" field with messages of all wrap layers of an error.
 	// Default value is false.
 	StackTrace bool
+
+	// StackTraceLevel is a minimum level of messages that will have a stack trace of a place where they are logged.
+	// Default value is empty, stack traces are added only to errors if StackTrace is enabled.
+	StackTraceLevel string
+
+	// GoroutineDump if true, will add a dump of all goroutines to messages of Fatal and Panic methods.
+	// Default value is false.
+	GoroutineDump bool
+
+	// CallerLevels is a list of levels for which information about method caller will be added.
+	// Default value is nil, which means that caller is added only to trace level.
+	CallerLevels []string
+
+	// CallerSkip is a number of additional stack frames to skip when caller is reported.
+	// Default value is 0.
+	CallerSkip int
+
+	// CallerMarshaler is a function that formats caller and error caller fields.
+	// Default value is nil, [zerolog.CallerMarshalFunc] is used.
+	CallerMarshaler func(pc uintptr, file string, line int) string
+
+	// ErrorCaller if true, will add file:line where Err, Errf, Error and Errorf methods were called
+	// as "error_caller" field. It is a lightweight alternative for StackTrace.
+	// Default value is false.
+	ErrorCaller bool
+
+	// NilErrors is a mode of handling nil errors passed to Err and Errf methods: error, info, skip or panic.
+	// Default value is error.
+	NilErrors string
+
+	// Development if true, will enable development mode: [Logger.DPanic] panics, caller is added to all levels
+	// by default and pretty console output to stderr is used if there are no writers.
+	// Default value is false.
+	Development bool
+
+	// EntryHooks is a list of hooks that are called for every entry before writing it.
+	// Default value is nil.
+	EntryHooks []EntryHook
+
+	// FieldNames is a set of names of standard fields (message, level, time, error, caller, stack)
+	// in JSON output. Default value is empty, zerolog names are used.
+	FieldNames FieldNames
+
+	// ECS if true, will write JSON output in Elastic Common Schema, see [Config.WithECS].
+	// Default value is false.
+	ECS bool
+
+	// GCP if true, will write JSON output in the format of Google Cloud Logging, see [Config.WithGCP].
+	// Default value is false.
+	GCP bool
+
+	// GCPProjectID is a project of traces in Google Cloud Logging output.
+	// Default value is empty, GOOGLE_CLOUD_PROJECT environment variable is used or trace IDs are written as is.
+	GCPProjectID string
+
+	// GCPErrorReporting if not nil, will format error entries for Google Error Reporting in Google Cloud Logging output,
+	// see [Config.WithGCPErrorReporting]. Default value is nil.
+	GCPErrorReporting *GCPServiceContext
+
+	// Datadog if not nil, will write JSON output with reserved attributes of Datadog, see [Config.WithDatadog].
+	// Default value is nil.
+	Datadog *DatadogOptions
+
+	// Summary is a template of a "summary" field that is computed from other fields of an entry,
+	// e.g. "{method} {path} → {status}". Default value is empty, summary is not added.
+	Summary string
+
+	// PostWriteHooks is a list of hooks that are called for every entry after writing it.
+	// Default value is nil.
+	PostWriteHooks []PostWriteHook
+
+	// StackFrameFilter is a filter of stack frames, only frames for which it returns true
+	// will be included in a stack trace. Default value is nil.
+	StackFrameFilter func(frame Frame) bool
+
+	// StackMarshaler is a function that marshals a stack trace of a logged error instead of a default one.
+	// Default value is nil.
+	StackMarshaler func(err error) any
+
+	// StackDepth is a maximum number of frames in a stack trace.
+	// Default value is 32.
+	StackDepth int
+
+	// StackSkip is a number of first frames of a stack trace to omit.
+	// Default value is 0.
+	StackSkip int
+
+	// StackHighlight is a list of modules of an application, their frames will be marked in a stack trace.
+	// Default value is nil.
+	StackHighlight []string
+
+	flags *flagValues
 }
 
 // NewConfig returns [Config] with provided list of [io.Writer], where [Logger] should logs its data.
@@ -110,6 +256,29 @@ func (c Config) WithLevel(level string) Config {
 	return c
 }
 
+// WithNoWriters returns [Config] with a mode of behavior when there are no writers in [Config]:
+// [NoWritersDiscard] (default), [NoWritersWarn], [NoWritersStderr] or [NoWritersError].
+func (c Config) WithNoWriters(mode string) Config {
+	c.NoWriters = mode
+	return c
+}
+
+// WithNilErrors returns [Config] with a mode of handling nil errors passed to Err and Errf methods:
+// [NilErrorsError] (default), [NilErrorsInfo], [NilErrorsSkip] or [NilErrorsPanic].
+func (c Config) WithNilErrors(mode string) Config {
+	c.NilErrors = mode
+	return c
+}
+
+// WithDevelopment returns [Config] with enabled development mode. In development mode [Logger.DPanic] panics
+// after logging instead of logging an error, caller is added to messages of all levels (unless
+// [Config.WithCallerLevels] is used) and logs are written to stderr in a pretty console format
+// if there are no writers in [Config].
+func (c Config) WithDevelopment() Config {
+	c.Development = true
+	return c
+}
+
 // WithHook returns [Config] with initialized [zerolog.Hook] provided as argument.
 func (c Config) WithHook(hook zerolog.Hook) Config {
 	c.Hook = hook
@@ -189,12 +358,31 @@ func (c Config) WithDiodeWaiter() Config {
 	return c
 }
 
+// WithAsync returns [Config] with an async writer that is used instead of diode. Unlike diode it can block
+// or drop the oldest entries when its queue is full, see [AsyncOptions]. Empty policy is drop_newest.
+func (c Config) WithAsync(opts AsyncOptions) Config {
+	if opts.Policy == "" {
+		opts.Policy = AsyncDropNewest
+	}
+	c.Async = opts
+	return c
+}
+
 // WithStackTrace returns [Config] with an enabled stack trace for Error and Errorf methods.
+// A "cause" field with messages of all wrap layers of an error is added too, so the provenance of an error
+// is visible even if only the outermost message is logged.
 func (c Config) WithStackTrace() Config {
 	c.StackTrace = true
 	return c
 }
 
+// WithErrorCaller returns [Config] with an enabled capturing of file:line where Err, Errf, Error and Errorf
+// methods were called. It gives most of the debugging value of a stack trace at a fraction of its cost.
+func (c Config) WithErrorCaller() Config {
+	c.ErrorCaller = true
+	return c
+}
+
 // WithErrorCounter returns [Config] with the provided [ErrorCounter].
 func (c Config) WithErrorCounter(ec ErrorCounter) Config {
 	c.ErrorCounter = ec
@@ -208,11 +396,10 @@ func (c Config) WithSimpleErrorCounter() Config {
 }
 
 func getConsoleWriter(w io.Writer, color bool) zerolog.ConsoleWriter {
-	return zerolog.ConsoleWriter{
-		Out:        w,
-		NoColor:    !color,
-		TimeFormat: time.DateTime,
-	}
+	return NewConsoleWriter(ConsoleOptions{
+		Out:     w,
+		NoColor: !color,
+	})
 }
 
 // ErrorCounter provides an interface to count logged errors. Use [Config.WithSimpleErrorCounter]
@@ -222,8 +409,10 @@ type ErrorCounter interface {
 }
 
 // SimpleErrorCounter is a simple implementation of [ErrorCounter] with an atomic counter.
+// It also counts entries dropped by diode or async writer, see [DropCounter].
 type SimpleErrorCounter struct {
-	Count atomic.Int64
+	Count   atomic.Int64
+	Dropped atomic.Int64
 }
 
 // Inc increments the counter by 1.
@@ -231,6 +420,11 @@ func (c *SimpleErrorCounter) Inc(error) {
 	c.Count.Add(1)
 }
 
+// IncDropped increments the counter of dropped entries by n.
+func (c *SimpleErrorCounter) IncDropped(n int) {
+	c.Dropped.Add(int64(n))
+}
+
 func newSimpleErrorCounter() *SimpleErrorCounter {
 	return &SimpleErrorCounter{}
 }
@@ -0,0 +1,262 @@
+package logze
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Writer names that can be used in a config file instead of a file path.
+const (
+	WriterStderr         = "stderr"
+	WriterStdout         = "stdout"
+	WriterConsole        = "console"
+	WriterConsoleNoColor = "console-nocolor"
+	WriterLogfmt         = "logfmt"
+)
+
+// Prefixes of writer names in a config file for local collectors: "unix:///run/collector.sock" is a unix socket
+// (see [NewUnixWriter]) and "fifo:///run/collector.fifo" is a named pipe (see [NewFIFOWriter]).
+// Paths to existing sockets and named pipes are detected without prefixes.
+const (
+	WriterUnixPrefix = "unix://"
+	WriterFIFOPrefix = "fifo://"
+)
+
+// FileConfig is a serializable representation of [Config] that can be stored in a JSON or YAML file.
+// Use [LoadConfig] or [ParseConfig] to get [Config] from it.
+type FileConfig struct {
+	// Level is a log level in string format.
+	Level string `yaml:"level" json:"level"`
+
+	// Writers is a list of outputs: "stderr", "stdout", "console", "console-nocolor", "logfmt" or a path to a file.
+	// Files are opened in append mode and created if they don't exist. Unix sockets and named pipes are written
+	// by [NetWriter] and [FIFOWriter], see [WriterUnixPrefix] and [WriterFIFOPrefix].
+	Writers []string `yaml:"writers" json:"writers"`
+
+	// NoWriters is a mode of behavior when there are no writers: discard, warn, stderr or error.
+	NoWriters string `yaml:"no_writers" json:"no_writers"`
+
+	// NilErrors is a mode of handling nil errors: error, info, skip or panic.
+	NilErrors string `yaml:"nil_errors" json:"nil_errors"`
+
+	// TimeFieldFormat is a format for time field, see [Config.TimeFieldFormat].
+	TimeFieldFormat string `yaml:"time_field_format" json:"time_field_format"`
+
+	// NoTimestamp if true, will disable time field.
+	NoTimestamp bool `yaml:"no_timestamp" json:"no_timestamp"`
+
+	// HighResTimestamps if true, will enable high resolution timestamps, see [Config.WithHighResTimestamps].
+	HighResTimestamps bool `yaml:"high_res_timestamps" json:"high_res_timestamps"`
+
+	// Summary is a template of a summary field, see [Config.WithSummary].
+	Summary string `yaml:"summary" json:"summary"`
+
+	// FieldNames is a set of names of standard fields, see [Config.WithFieldNames].
+	FieldNames FieldNames `yaml:"field_names" json:"field_names"`
+
+	// ECS if true, will write output in Elastic Common Schema, see [Config.WithECS].
+	ECS bool `yaml:"ecs" json:"ecs"`
+
+	// GCP if true, will write output in the format of Google Cloud Logging, see [Config.WithGCP].
+	GCP bool `yaml:"gcp" json:"gcp"`
+
+	// GCPProjectID is a project of traces in Google Cloud Logging output, see [Config.WithGCP].
+	GCPProjectID string `yaml:"gcp_project_id" json:"gcp_project_id"`
+
+	// GCPErrorReporting if set, will format error entries for Google Error Reporting, see [Config.WithGCPErrorReporting].
+	GCPErrorReporting *GCPServiceContext `yaml:"gcp_error_reporting" json:"gcp_error_reporting"`
+
+	// Datadog if set, will write output with reserved attributes of Datadog, see [Config.WithDatadog].
+	Datadog *DatadogOptions `yaml:"datadog" json:"datadog"`
+
+	// ToIgnore is a list of messages that will be ignored.
+	ToIgnore []string `yaml:"to_ignore" json:"to_ignore"`
+
+	// StackTrace if true, will enable stack trace for Error and Errorf methods.
+	StackTrace bool `yaml:"stack_trace" json:"stack_trace"`
+
+	// StackTraceLevel is a minimum level of messages with stack traces, see [Config.WithStackTraceLevel].
+	StackTraceLevel string `yaml:"stack_trace_level" json:"stack_trace_level"`
+
+	// GoroutineDump if true, will add a dump of all goroutines to fatal messages, see [Config.WithGoroutineDump].
+	GoroutineDump bool `yaml:"goroutine_dump" json:"goroutine_dump"`
+
+	// Development if true, will enable development mode, see [Config.WithDevelopment].
+	Development bool `yaml:"development" json:"development"`
+
+	// Diode contains settings of a diode writer.
+	Diode FileDiodeConfig `yaml:"diode" json:"diode"`
+
+	// MaxThroughput is a max number of bytes per second written by the logger, see [Config.WithMaxThroughput].
+	MaxThroughput int `yaml:"max_throughput" json:"max_throughput"`
+
+	// Async contains settings of an async writer that is used instead of diode if its policy is set.
+	Async FileAsyncConfig `yaml:"async" json:"async"`
+}
+
+// FileAsyncConfig is a serializable representation of async writer settings in [FileConfig].
+type FileAsyncConfig struct {
+	// Policy is a behavior when the queue is full: drop_newest, drop_oldest or block.
+	Policy string `yaml:"policy" json:"policy"`
+
+	// Size is a max number of entries in the queue.
+	Size int `yaml:"size" json:"size"`
+
+	// BlockTimeout is a max time to wait for space in the queue with block policy, e.g. "100ms".
+	BlockTimeout time.Duration `yaml:"block_timeout" json:"block_timeout"`
+}
+
+// FileDiodeConfig is a serializable representation of diode settings in [FileConfig].
+type FileDiodeConfig struct {
+	// Disabled if true, will disable diode writer.
+	Disabled bool `yaml:"disabled" json:"disabled"`
+
+	// Size is a size of a diode writer.
+	Size int `yaml:"size" json:"size"`
+
+	// PollingInterval is a time after which diode writer will flush its buffer, e.g. "10ms".
+	PollingInterval time.Duration `yaml:"polling_interval" json:"polling_interval"`
+
+	// UseWaiter if true, will enable diode waiter istead of poller.
+	UseWaiter bool `yaml:"use_waiter" json:"use_waiter"`
+}
+
+// LoadConfig reads a JSON or YAML file by the provided path and returns [Config] based on it.
+// Files from the writers list are opened during loading, they stay open for the lifetime of the application.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses JSON or YAML data and returns [Config] based on it.
+// Files from the writers list are opened during parsing, they stay open for the lifetime of the application.
+func ParseConfig(data []byte) (Config, error) {
+	fc, err := parseFileConfig(data)
+	if err != nil {
+		return Config{}, err
+	}
+	return fc.Config()
+}
+
+func parseFileConfig(data []byte) (FileConfig, error) {
+	var fc FileConfig
+	// JSON is a subset of YAML, so one decoder handles both formats
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return FileConfig{}, fmt.Errorf("parse config: %w", err)
+	}
+	return fc, nil
+}
+
+// Config returns [Config] based on [FileConfig], opening all provided writers.
+func (fc FileConfig) Config() (Config, error) {
+	cfg := NewConfig().
+		WithLevel(fc.Level).
+		WithNoWriters(fc.NoWriters).
+		WithNilErrors(fc.NilErrors).
+		WithTimeFieldFormat(fc.TimeFieldFormat).
+		WithToIgnore(fc.ToIgnore...).
+		WithSummary(fc.Summary).
+		WithStackTraceLevel(fc.StackTraceLevel).
+		WithFieldNames(fc.FieldNames).
+		WithMaxThroughput(fc.MaxThroughput).
+		WithDiodeSize(fc.Diode.Size).
+		WithDiodePollingInterval(fc.Diode.PollingInterval)
+
+	if fc.StackTrace {
+		cfg = cfg.WithStackTrace()
+	}
+	if fc.ECS {
+		cfg = cfg.WithECS()
+	}
+	if fc.GCP {
+		cfg = cfg.WithGCPProjectID(fc.GCPProjectID).WithGCP()
+	}
+	if fc.GCPErrorReporting != nil {
+		cfg = cfg.WithGCPProjectID(fc.GCPProjectID).WithGCPErrorReporting(*fc.GCPErrorReporting)
+	}
+	if fc.Datadog != nil {
+		cfg = cfg.WithDatadog(*fc.Datadog)
+	}
+	if fc.NoTimestamp {
+		cfg = cfg.WithNoTimestamp()
+	}
+	if fc.HighResTimestamps {
+		cfg = cfg.WithHighResTimestamps()
+	}
+	if fc.GoroutineDump {
+		cfg = cfg.WithGoroutineDump()
+	}
+	if fc.Development {
+		cfg = cfg.WithDevelopment()
+	}
+	if fc.Diode.Disabled {
+		cfg = cfg.WithNoDiode()
+	}
+	if fc.Diode.UseWaiter {
+		cfg = cfg.WithDiodeWaiter()
+	}
+	if fc.Async.Policy != "" {
+		cfg = cfg.WithAsync(AsyncOptions{
+			Policy:       fc.Async.Policy,
+			Size:         fc.Async.Size,
+			BlockTimeout: fc.Async.BlockTimeout,
+		})
+	}
+
+	for _, name := range fc.Writers {
+		w, err := openWriter(name)
+		if err != nil {
+			// Config is not returned, so writers opened before are closed
+			_ = closeWriters(trackWriters(cfg.Writers))
+			return Config{}, err
+		}
+		cfg = cfg.WithWriter(w)
+	}
+
+	return cfg, nil
+}
+
+func openWriter(name string) (io.Writer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return nil, fmt.Errorf("empty writer name")
+	case WriterStderr:
+		return os.Stderr, nil
+	case WriterStdout:
+		return os.Stdout, nil
+	case WriterConsole:
+		return getConsoleWriter(os.Stderr, true), nil
+	case WriterConsoleNoColor:
+		return getConsoleWriter(os.Stderr, false), nil
+	case WriterLogfmt:
+		return NewLogfmtWriter(os.Stderr), nil
+	}
+	if path := strings.TrimPrefix(name, WriterUnixPrefix); path != name {
+		return NewUnixWriter(path, NetOptions{}), nil
+	}
+	if path := strings.TrimPrefix(name, WriterFIFOPrefix); path != name {
+		return NewFIFOWriter(path, FIFOOptions{}), nil
+	}
+	if info, err := os.Stat(name); err == nil {
+		switch {
+		case info.Mode()&os.ModeSocket != 0:
+			return NewUnixWriter(name, NetOptions{}), nil
+		case info.Mode()&os.ModeNamedPipe != 0:
+			// Opening a named pipe blocks until there is a reader
+			return NewFIFOWriter(name, FIFOOptions{}), nil
+		}
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open writer: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,105 @@
+package logze_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestParseConfigYAML(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	data := []byte(`
+level: debug
+writers: [stderr, "` + logPath + `"]
+time_field_format: "2006-01-02"
+to_ignore: ["ignore me"]
+stack_trace: true
+diode:
+  size: 500
+  polling_interval: 20ms
+`)
+
+	cfg, err := logze.ParseConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Level != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, cfg.Level)
+	}
+	if len(cfg.Writers) != 2 || cfg.Writers[0] != os.Stderr {
+		t.Errorf("expected stderr and file writers, got %v", cfg.Writers)
+	}
+	if cfg.TimeFieldFormat != "2006-01-02" {
+		t.Errorf("expected time format 2006-01-02, got %s", cfg.TimeFieldFormat)
+	}
+	if len(cfg.ToIgnore) != 1 || cfg.ToIgnore[0] != "ignore me" {
+		t.Errorf("unexpected entries in ToIgnore: %v", cfg.ToIgnore)
+	}
+	if !cfg.StackTrace {
+		t.Errorf("expected StackTrace to be true, got false")
+	}
+	if cfg.DiodeSize != 500 || cfg.DiodePollingInterval != 20*time.Millisecond {
+		t.Errorf("unexpected diode settings: size=%d interval=%v", cfg.DiodeSize, cfg.DiodePollingInterval)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	cfgPath := filepath.Join(dir, "log.json")
+	data := `{"level": "info", "writers": ["` + logPath + `"], "diode": {"disabled": true}}`
+	if err := os.WriteFile(cfgPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := logze.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.NoDiode {
+		t.Errorf("expected NoDiode to be true, got false")
+	}
+
+	logze.New(cfg).Info("message from file config")
+
+	output, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "message from file config") {
+		t.Errorf("expected log in file, got %s", output)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	if _, err := logze.LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := logze.ParseConfig([]byte("level: [")); err == nil {
+		t.Error("expected error for invalid data")
+	}
+	if _, err := logze.ParseConfig([]byte(`writers: [""]`)); err == nil {
+		t.Error("expected error for empty writer")
+	}
+}
+
+func TestParseConfigClosesWriters(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("open files are not listed in /proc")
+	}
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	if _, err := logze.ParseConfig([]byte(`writers: ["` + logPath + `", stderr, ""]`)); err == nil {
+		t.Fatal("expected error for empty writer")
+	}
+	if isFileOpen(t, logPath) {
+		t.Errorf("expected closed %s", logPath)
+	}
+	if _, err := os.Stderr.Write(nil); err != nil {
+		t.Errorf("expected open stderr, got %v", err)
+	}
+}
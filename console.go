@@ -0,0 +1,298 @@
+package logze
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// consoleFoldedKey is a key of event map where folded multi-line fields are stored between
+// FormatPrepare and FormatExtra calls of [zerolog.ConsoleWriter].
+const consoleFoldedKey = "\x00logze_folded"
+
+const consoleIndent = "    "
+
+// mainModule is a path of the main module, its packages are not treated as the standard library
+// even if the path has no dot, e.g. a module "app".
+var mainModule = func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+}()
+
+// ConsoleOptions is using for creating a console writer with [NewConsoleWriter] or [Config.WithConsoleOptions].
+type ConsoleOptions struct {
+	// Out is a destination of console writer. Default value is [os.Stderr].
+	Out io.Writer
+
+	// NoColor if true, will disable colors in output.
+	NoColor bool
+
+	// NoFolding if true, will disable rendering of stack traces and multi-line strings
+	// as indented continuation lines under the entry. Escaped \n in one line will be used instead.
+	NoFolding bool
+
+	// TimeFormat is a format of time in output. Default value is [time.DateTime].
+	TimeFormat string
+
+	// TimeLocation is a location of time in output. Default value is [time.Local].
+	TimeLocation *time.Location
+
+	// CollapseVendor if true, will collapse stack frames from vendored, third-party and standard library
+	// packages into a single line with the number of hidden frames. Such frames are marked with "vendor":true
+	// field in stack traces captured by logger, because their sources are base names of files.
+	CollapseVendor bool
+}
+
+// NewConsoleWriter returns a pretty console writer with provided options.
+// By default stack traces and multi-line strings are rendered as indented lines under the entry:
+//
+//	12:04:05 ERR cannot handle error="some error"
+//	    stack:
+//	        main.handle (main.go:42)
+//	        main.main (main.go:12)
+func NewConsoleWriter(opts ConsoleOptions) zerolog.ConsoleWriter {
+	if opts.Out == nil {
+		opts.Out = os.Stderr
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.DateTime
+	}
+	w := zerolog.ConsoleWriter{
+		Out:          opts.Out,
+		NoColor:      opts.NoColor,
+		TimeFormat:   opts.TimeFormat,
+		TimeLocation: opts.TimeLocation,
+	}
+	if !opts.NoFolding {
+		w.FieldsExclude = []string{consoleFoldedKey}
+		w.FormatPrepare = func(evt map[string]any) error {
+			foldEvent(evt, opts.CollapseVendor)
+			return nil
+		}
+		w.FormatExtra = func(evt map[string]any, buf *bytes.Buffer) error {
+			writeFolded(evt, buf)
+			return nil
+		}
+	}
+	return w
+}
+
+// WithConsoleOptions returns [Config] with a configurated output in a pretty console format using provided options.
+// This format may significantly slow down logging in an application compared to a default JSON format.
+func (c Config) WithConsoleOptions(opts ConsoleOptions) Config {
+	return c.WithWriter(NewConsoleWriter(opts))
+}
+
+// withConsoleTimeFormat returns writers where console writers parse time of entries in provided format.
+// Console writer of zerolog parses time using global [zerolog.TimeFieldFormat], that is not changed by [Logger].
+func withConsoleTimeFormat(writers []io.Writer, format string) []io.Writer {
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		switch cw := w.(type) {
+		case zerolog.ConsoleWriter:
+			if cw.FormatTimestamp == nil {
+				cw.FormatTimestamp = consoleTimestamp(format, cw.TimeFormat, cw.TimeLocation, cw.NoColor)
+			}
+			w = cw
+		case *zerolog.ConsoleWriter:
+			if cw.FormatTimestamp == nil {
+				c := *cw
+				c.FormatTimestamp = consoleTimestamp(format, cw.TimeFormat, cw.TimeLocation, cw.NoColor)
+				w = &c
+			}
+		}
+		out[i] = w
+	}
+	return out
+}
+
+// consoleTimestamp returns a formatter of time for console writer that works like a default one of zerolog,
+// but parses time in provided format instead of [zerolog.TimeFieldFormat].
+func consoleTimestamp(format, timeFormat string, loc *time.Location, noColor bool) zerolog.Formatter {
+	if timeFormat == "" {
+		timeFormat = time.Kitchen
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return func(i any) string {
+		s := "<nil>"
+		if t, ok := parseTime(i, format, loc); ok {
+			s = t.In(loc).Format(timeFormat)
+		} else if i != nil {
+			s = fmt.Sprint(i)
+		}
+		if noColor || os.Getenv("NO_COLOR") != "" {
+			return s
+		}
+		return "\x1b[90m" + s + "\x1b[0m"
+	}
+}
+
+type foldedField struct {
+	name  string
+	lines []string
+}
+
+func foldEvent(evt map[string]any, collapseVendor bool) {
+	keys := make([]string, 0, len(evt))
+	for k := range evt {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return foldOrder(keys[i]) < foldOrder(keys[j]) || foldOrder(keys[i]) == foldOrder(keys[j]) && keys[i] < keys[j]
+	})
+
+	var folded []foldedField
+	for _, k := range keys {
+		switch v := evt[k].(type) {
+		case string:
+			if !strings.Contains(v, "\n") {
+				continue
+			}
+			lines := strings.Split(strings.TrimRight(v, "\n"), "\n")
+			evt[k] = lines[0]
+			if len(lines) > 1 {
+				folded = append(folded, foldedField{name: k, lines: foldTextStack(lines[1:], collapseVendor)})
+			}
+
+		case []any:
+			if k != zerolog.ErrorStackFieldName {
+				continue
+			}
+			delete(evt, k)
+			folded = append(folded, foldedField{name: k, lines: foldFrames(v, collapseVendor)})
+		}
+	}
+	if len(folded) > 0 {
+		evt[consoleFoldedKey] = folded
+	}
+}
+
+func writeFolded(evt map[string]any, buf *bytes.Buffer) {
+	folded, ok := evt[consoleFoldedKey].([]foldedField)
+	if !ok {
+		return
+	}
+	for _, f := range folded {
+		indent := consoleIndent
+		if f.name != zerolog.MessageFieldName {
+			buf.WriteString("\n" + consoleIndent + f.name + ":")
+			indent += consoleIndent
+		}
+		for _, line := range f.lines {
+			buf.WriteString("\n" + indent + strings.ReplaceAll(line, "\t", consoleIndent))
+		}
+	}
+}
+
+// foldOrder returns position of a folded field: message goes first, then error, other fields and stack at the end.
+func foldOrder(key string) int {
+	switch key {
+	case zerolog.MessageFieldName:
+		return 0
+	case zerolog.ErrorFieldName:
+		return 1
+	case zerolog.ErrorStackFieldName:
+		return 3
+	}
+	return 2
+}
+
+// foldFrames renders frames of a stack in a form of 'func (source:line)'.
+func foldFrames(frames []any, collapseVendor bool) []string {
+	out := make([]string, 0, len(frames))
+	collapsed := 0
+	for _, f := range frames {
+		frame, ok := f.(map[string]any)
+		if !ok {
+			out = append(out, fmt.Sprint(f))
+			continue
+		}
+		fn, _ := frame["func"].(string)
+		source, _ := frame["source"].(string)
+		vendor, _ := frame["vendor"].(bool)
+		// Function is a short name here, standard library frames are marked as vendor when stack is captured
+		if collapseVendor && (vendor || isVendorFrame("", source)) {
+			collapsed++
+			continue
+		}
+		out = appendCollapsed(out, collapsed)
+		collapsed = 0
+		line := fmt.Sprintf("%s (%s:%v)", fn, source, frame["line"])
+		if app, _ := frame["app"].(bool); app {
+			// Application frames highlighted with Config.WithStackHighlight
+			line = "> " + line
+		}
+		out = append(out, line)
+	}
+	return appendCollapsed(out, collapsed)
+}
+
+// foldTextStack collapses vendored frames in a stack printed using %+v verb of github.com/pkg/errors,
+// where every frame is a function name line followed by a tab-indented file:line line.
+func foldTextStack(lines []string, collapseVendor bool) []string {
+	if !collapseVendor {
+		return lines
+	}
+	out := make([]string, 0, len(lines))
+	collapsed := 0
+	for i := 0; i < len(lines); i++ {
+		isFrame := i+1 < len(lines) && !strings.HasPrefix(lines[i], "\t") && strings.HasPrefix(lines[i+1], "\t")
+		if isFrame && isVendorFrame(lines[i], strings.TrimSpace(lines[i+1])) {
+			collapsed++
+			i++
+			continue
+		}
+		out = appendCollapsed(out, collapsed)
+		collapsed = 0
+		out = append(out, lines[i])
+	}
+	return appendCollapsed(out, collapsed)
+}
+
+func appendCollapsed(out []string, collapsed int) []string {
+	if collapsed == 0 {
+		return out
+	}
+	return append(out, fmt.Sprintf("... %d vendor frames", collapsed))
+}
+
+// isVendorFrame returns true if a frame belongs to a vendored or third-party module or to the standard library.
+func isVendorFrame(function, file string) bool {
+	if strings.Contains(file, "/vendor/") || strings.Contains(file, "/pkg/mod/") || strings.Contains(file, "@v") {
+		return true
+	}
+	return isStdFunction(function)
+}
+
+// isStdFunction returns true if a full name of a function belongs to the standard library: the first element
+// of its package path has no dot, e.g. runtime.goexit or net/http.(*conn).serve, unlike github.com/... modules.
+func isStdFunction(function string) bool {
+	first := firstPathElement(function)
+	if first == "" || first == "main" || strings.Contains(first, ".") {
+		return false
+	}
+	return first != firstPathElement(mainModule)
+}
+
+// firstPathElement returns the first element of a package path of a function or of a module path.
+func firstPathElement(name string) string {
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		return name[:i]
+	}
+	// Package path without slashes, e.g. runtime.goexit
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
@@ -0,0 +1,88 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/pkg/errors"
+)
+
+func TestConsoleFolding(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig().WithConsoleOptions(logze.ConsoleOptions{Out: &b, NoColor: true}).
+		WithLevel(logze.LevelDebug).WithStackTrace().WithNoDiode()
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("first line\nsecond line"), "cannot handle", "key", "value")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) < 4 {
+		t.Fatalf("expected multi-line output, got %s", b.String())
+	}
+	if !strings.Contains(lines[0], "cannot handle") || !strings.Contains(lines[0], "first line") || strings.Contains(lines[0], "second line") {
+		t.Errorf("expected first line of an error in entry, got %s", lines[0])
+	}
+	if strings.Contains(lines[0], `\n`) {
+		t.Errorf("expected no escaped new lines, got %s", lines[0])
+	}
+	if strings.TrimSpace(lines[1]) != "error:" || strings.TrimSpace(lines[2]) != "second line" {
+		t.Errorf("expected folded error, got %q", lines[1:3])
+	}
+	if !strings.Contains(b.String(), "    stack:\n") || !strings.Contains(b.String(), "TestConsoleFolding") {
+		t.Errorf("expected folded stack, got %s", b.String())
+	}
+}
+
+func TestConsoleFoldingCollapseVendor(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig().WithConsoleOptions(logze.ConsoleOptions{Out: &b, NoColor: true, CollapseVendor: true}).
+		WithNoDiode()
+	logger := logze.New(cfg)
+
+	logger.ErrStack(errors.New("some error"))
+
+	output := b.String()
+	if !strings.Contains(output, "TestConsoleFoldingCollapseVendor") {
+		t.Errorf("expected application frame, got %s", output)
+	}
+	if strings.Contains(output, "runtime.goexit") || !strings.Contains(output, "vendor frames") {
+		t.Errorf("expected collapsed standard library frames, got %s", output)
+	}
+}
+
+func TestConsoleFoldingCollapseVendorJSONStack(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig().WithConsoleOptions(logze.ConsoleOptions{Out: &b, NoColor: true, CollapseVendor: true}).
+		WithNoDiode().WithStackTrace()
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("some error"), "message")
+
+	output := b.String()
+	if !strings.Contains(output, "TestConsoleFoldingCollapseVendorJSONStack") {
+		t.Errorf("expected application frame, got %s", output)
+	}
+	for _, frame := range []string{"tRunner", "goexit", "asm_", "testing.go"} {
+		if strings.Contains(output, frame) {
+			t.Errorf("expected collapsed frame %s, got %s", frame, output)
+		}
+	}
+	if !strings.Contains(output, "vendor frames") {
+		t.Errorf("expected collapsed standard library frames, got %s", output)
+	}
+}
+
+func TestConsoleNoFolding(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig().WithConsoleOptions(logze.ConsoleOptions{Out: &b, NoColor: true, NoFolding: true}).
+		WithNoDiode()
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("first line\nsecond line"), "message")
+
+	if strings.Count(strings.TrimSpace(b.String()), "\n") != 0 {
+		t.Errorf("expected one line output, got %s", b.String())
+	}
+}
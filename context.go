@@ -0,0 +1,19 @@
+package logze
+
+import "context"
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx with the logger, so request handlers can get a request-scoped logger
+// with [FromContext], e.g. the one with a request ID added by a middleware.
+func (l Logger) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns [Logger] stored in ctx with [Logger.WithContext] or a global logger if there is no one.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(ctxKey{}).(Logger); ok {
+		return l
+	}
+	return log
+}
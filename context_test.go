@@ -0,0 +1,25 @@
+package logze_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestContext(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode()).WithFields("request_id", "req-1")
+
+	ctx := logger.WithContext(context.Background())
+	logze.FromContext(ctx).Info("message")
+	if !strings.Contains(b.String(), `"request_id":"req-1"`) {
+		t.Errorf("expected logger from context, got %s", b.String())
+	}
+
+	if logze.FromContext(context.Background()).NotInited() {
+		t.Error("expected global logger if there is no logger in context")
+	}
+}
@@ -0,0 +1,181 @@
+package logze
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Names of fields of entries with trace and span IDs, they become dd.trace_id and dd.span_id in [Config.WithDatadog].
+var (
+	DatadogTraceIDSourceName = "trace_id"
+	DatadogSpanIDSourceName  = "span_id"
+)
+
+// DatadogOptions is using for [Config.WithDatadog].
+type DatadogOptions struct {
+	// Service is a name of a service in service and dd.service fields. Default value is DD_SERVICE environment variable.
+	Service string `yaml:"service" json:"service"`
+
+	// Env is an environment in dd.env field. Default value is DD_ENV environment variable.
+	Env string `yaml:"env" json:"env"`
+
+	// Version is a version of a service in dd.version field. Default value is DD_VERSION environment variable.
+	Version string `yaml:"version" json:"version"`
+}
+
+// WithDatadog returns [Config] with JSON output that uses reserved attributes of Datadog, so its pipeline
+// recognizes entries without custom remappers: level becomes status, time becomes timestamp, error becomes
+// error.message and a stack trace becomes error.stack rendered as text. Service, env and version from options
+// are added as service, dd.service, dd.env and dd.version, trace_id and span_id fields become dd.trace_id
+// and dd.span_id in the decimal form used by Datadog to correlate logs with traces.
+//
+// It replaces names set by [Config.WithFieldNames] and disables [Config.WithECS] and [Config.WithGCP].
+func (c Config) WithDatadog(opts DatadogOptions) Config {
+	if opts.Service == "" {
+		opts.Service = os.Getenv("DD_SERVICE")
+	}
+	if opts.Env == "" {
+		opts.Env = os.Getenv("DD_ENV")
+	}
+	if opts.Version == "" {
+		opts.Version = os.Getenv("DD_VERSION")
+	}
+	c.Datadog = &opts
+	c.ECS, c.GCP = false, false
+	c.FieldNames = FieldNames{
+		Level: "status",
+		Time:  "timestamp",
+		Error: "error.message",
+		Stack: "error.stack",
+	}
+	return c
+}
+
+// withDatadog returns writers where all JSON writers get entries with reserved attributes of Datadog.
+// Fields are renamed by inner writers, so entries are changed before renaming.
+func withDatadog(writers []io.Writer, opts DatadogOptions) []io.Writer {
+	var static []byte
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(value)
+		static = append(static, k...)
+		static = append(static, ':')
+		static = append(static, v...)
+		static = append(static, ',')
+	}
+	add("dd.service", opts.Service)
+	add("dd.env", opts.Env)
+	add("dd.version", opts.Version)
+
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		if hasOwnFormat(w) {
+			out[i] = w
+			continue
+		}
+		out[i] = convertWriter{w: w, convert: datadogConverter{service: opts.Service, static: static}.convert}
+	}
+	return out
+}
+
+// datadogConverter converts standard fields of every JSON entry to reserved attributes of Datadog.
+type datadogConverter struct {
+	service string
+	static  []byte
+}
+
+func (c datadogConverter) convert(p []byte) []byte {
+	fields, ok := splitFields(p)
+	if !ok {
+		return p
+	}
+	out := make([]byte, 0, len(p)+len(c.static)+32)
+	out = append(out, '{')
+	out = append(out, c.static...)
+	add := func(key string, value []byte) {
+		name, _ := json.Marshal(key)
+		out = append(out, name...)
+		out = append(out, ':')
+		out = append(out, value...)
+		out = append(out, ',')
+	}
+
+	hasService := false
+	for _, f := range fields {
+		switch f.key {
+		case zerolog.LevelFieldName:
+			var level string
+			_ = json.Unmarshal(f.value, &level)
+			status, _ := json.Marshal(datadogStatus(level))
+			add(f.key, status)
+
+		case zerolog.ErrorStackFieldName:
+			var frames []map[string]any
+			if err := json.Unmarshal(f.value, &frames); err != nil {
+				add(f.key, f.value)
+				continue
+			}
+			text, _ := json.Marshal(stackText(frames))
+			add(f.key, text)
+
+		case DatadogTraceIDSourceName:
+			add("dd.trace_id", datadogID(f.value))
+
+		case DatadogSpanIDSourceName:
+			add("dd.span_id", datadogID(f.value))
+
+		default:
+			hasService = hasService || f.key == "service"
+			add(f.key, f.value)
+		}
+	}
+	if !hasService && c.service != "" {
+		value, _ := json.Marshal(c.service)
+		add("service", value)
+	}
+	if out[len(out)-1] == ',' {
+		out = out[:len(out)-1]
+	}
+	out = append(out, '}')
+	return append(out, '\n')
+}
+
+// datadogStatus returns a status of Datadog for a level.
+func datadogStatus(level string) string {
+	switch level {
+	case LevelTrace:
+		return LevelDebug
+	case LevelFatal:
+		return "critical"
+	case zerolog.LevelPanicValue:
+		return "emergency"
+	}
+	return level
+}
+
+// datadogID converts a hex trace or span ID of OpenTelemetry (W3C) to a decimal string of its lower 64 bits,
+// that is a form of IDs in Datadog. Other values are returned as is.
+func datadogID(value json.RawMessage) json.RawMessage {
+	var id string
+	if err := json.Unmarshal(value, &id); err != nil || (len(id) != 16 && len(id) != 32) {
+		return value
+	}
+	if strings.Trim(id, "0123456789") == "" {
+		// Decimal ID of Datadog tracer
+		return value
+	}
+	n, err := strconv.ParseUint(id[len(id)-16:], 16, 64)
+	if err != nil {
+		return value
+	}
+	out, _ := json.Marshal(strconv.FormatUint(n, 10))
+	return out
+}
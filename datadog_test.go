@@ -0,0 +1,65 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestDatadog(t *testing.T) {
+	t.Setenv("DD_ENV", "prod")
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithStackTrace().
+		WithDatadog(logze.DatadogOptions{Service: "billing", Version: "1.2.3"}))
+
+	logger.Info("started", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	expected := map[string]any{
+		"status":      "info",
+		"message":     "started",
+		"service":     "billing",
+		"dd.service":  "billing",
+		"dd.env":      "prod",
+		"dd.version":  "1.2.3",
+		"dd.trace_id": "11803532876627986230",
+		"dd.span_id":  "67667974448284343",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if entry["timestamp"] == nil || entry["time"] != nil || entry["level"] != nil {
+		t.Errorf("expected timestamp and status instead of time and level, got %v", entry)
+	}
+
+	b.Reset()
+	logger.Err(errors.New("connection refused"), "cannot connect", "service", "payments")
+	entry = nil
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry["status"] != "error" || entry["error.message"] != "connection refused" || entry["service"] != "payments" {
+		t.Errorf("expected error attributes and service of the entry, got %v", entry)
+	}
+	if stack, _ := entry["error.stack"].(string); !strings.Contains(stack, "TestDatadog\n\tdatadog_test.go:") {
+		t.Errorf("expected error.stack as text, got %v", entry["error.stack"])
+	}
+}
+
+func TestDatadogConfigFile(t *testing.T) {
+	cfg, err := logze.ParseConfig([]byte("gcp: true\ndatadog:\n  service: billing\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Datadog == nil || cfg.Datadog.Service != "billing" || cfg.GCP {
+		t.Errorf("expected Datadog mode, got %+v %v", cfg.Datadog, cfg.GCP)
+	}
+}
@@ -0,0 +1,101 @@
+package logze
+
+import (
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+// DictField is a field with a nested object, use [Dict] to create it.
+type DictField struct {
+	key    string
+	fields []any
+}
+
+// Dict returns a field with provided key and a nested object of fields, provided as (key, value) pairs.
+// It should be used in place of a (key, value) pair in logging methods and [Logger.WithFields]:
+//
+//	logger.Info("request", logze.Dict("req", "method", "GET", "path", "/x"), "status", 200)
+//	// {"level":"info","req":{"method":"GET","path":"/x"},"status":200,"message":"request"}
+//
+// Dicts can be nested. Values of map[string]any type are also encoded as nested objects.
+// Map of map[string]any type in place of a (key, value) pair is expanded into separate fields:
+//
+//	logger.Info("request", map[string]any{"method": "GET", "status": 200})
+//	// {"level":"info","method":"GET","status":200,"message":"request"}
+func Dict(key string, fields ...any) DictField {
+	return DictField{key: key, fields: fields}
+}
+
+// MarshalZerologObject implements [zerolog.LogObjectMarshaler].
+func (d DictField) MarshalZerologObject(e *zerolog.Event) {
+	e.Fields(resolveValues(expandFields(d.fields)))
+}
+
+// mapObject encodes map[string]any as a nested object with sorted keys.
+type mapObject map[string]any
+
+// MarshalZerologObject implements [zerolog.LogObjectMarshaler].
+func (m mapObject) MarshalZerologObject(e *zerolog.Event) {
+	e.Fields(resolveValues(appendMap(make([]any, 0, 2*len(m)), m)))
+}
+
+// expandFields replaces special fields (e.g. [DictField] or [ObjField]) with (key, value) pairs that zerolog can encode.
+// It returns provided slice without allocations if there are no special fields.
+func expandFields(fields []any) []any {
+	if !needExpand(fields) {
+		return fields
+	}
+	out := make([]any, 0, len(fields)+2)
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i].(type) {
+		case DictField:
+			out = append(out, f.key, f)
+			continue
+		case map[string]any:
+			out = appendMap(out, f)
+			continue
+		case ObjField:
+			out = appendStruct(out, f.v)
+			continue
+		}
+		out = append(out, fields[i])
+		if i+1 < len(fields) {
+			out = append(out, expandValue(fields[i+1]))
+			i++
+		}
+	}
+	return out
+}
+
+func needExpand(fields []any) bool {
+	for i := 0; i < len(fields); i++ {
+		switch fields[i].(type) {
+		case DictField:
+			return true
+		case map[string]any, ObjField:
+			return true
+		}
+	}
+	return false
+}
+
+// appendMap appends pairs of the map sorted by keys.
+func appendMap(out []any, m map[string]any) []any {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out = append(out, k, expandValue(m[k]))
+	}
+	return out
+}
+
+func expandValue(v any) any {
+	if m, ok := v.(map[string]any); ok {
+		return mapObject(m)
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestDict(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	logger.Info("request", logze.Dict("req", "method", "GET", "path", "/x"), "status", 200)
+	if !strings.Contains(b.String(), `"req":{"method":"GET","path":"/x"},"status":200`) {
+		t.Errorf("expected nested object, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Infof("request %d", 1, logze.Dict("req", logze.Dict("h", "accept", "*/*")))
+	if !strings.Contains(b.String(), `"req":{"h":{"accept":"*/*"}}`) {
+		t.Errorf("expected nested dicts, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.WithFields(logze.Dict("service", "name", "api")).Info("message", "meta", map[string]any{"b": 2, "a": logze.Dict("c", "d", 1)})
+	if !strings.Contains(b.String(), `"service":{"name":"api"}`) || !strings.Contains(b.String(), `"meta":{"a":{"d":1},"b":2}`) {
+		t.Errorf("expected nested objects, got %s", b.String())
+	}
+}
+
+func TestMapFields(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	logger.Info("request", map[string]any{"method": "GET", "status": 200, "meta": map[string]any{"a": 1}})
+	if !strings.Contains(b.String(), `"meta":{"a":1},"method":"GET","status":200`) {
+		t.Errorf("expected expanded map, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.WithFields(map[string]any{"service": "api"}, "version", 2).Infof("started %d", 1, map[string]any{"port": 80})
+	if !strings.Contains(b.String(), `"service":"api","version":2`) || !strings.Contains(b.String(), `"port":80`) {
+		t.Errorf("expected expanded maps, got %s", b.String())
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !logze_nodiode
+
+package logze
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/diode"
+)
+
+func init() {
+	RegisterFeature(FeatureDiode)
+}
+
+// newDiodeWriter returns w wrapped in a diode writer using settings from [Config].
+// Number of dropped entries is added to the counter.
+// Build with logze_nodiode tag to exclude diode from a binary, in that case writes are synchronous.
+func newDiodeWriter(w io.Writer, cfg Config, dropped *dropCounter) io.Writer {
+	if cfg.DiodeSize == 0 {
+		cfg.DiodeSize = DefaultDiodeSize
+	}
+	if cfg.DiodePollingInterval == 0 {
+		cfg.DiodePollingInterval = DefaultDiodePollingInterval
+	}
+	if cfg.UseDiodeWaiter {
+		cfg.DiodePollingInterval = 0
+	}
+	alert := cfg.DiodeAlertFunc
+	if alert == nil {
+		alert = func(missed int) {
+			fmt.Fprintf(os.Stderr, "WRN: logger dropped %d messages\n", missed)
+		}
+	}
+	cfg.DiodeAlertFunc = func(missed int) {
+		dropped.add(missed)
+		alert(missed)
+	}
+	// To fix problem of blocking goroutine when writing in Stderr
+	// https://github.com/cloudfoundry/go-diodes
+	return diode.NewWriter(noCloseWriter{w}, cfg.DiodeSize, cfg.DiodePollingInterval, cfg.DiodeAlertFunc)
+}
+
+// closeDiode flushes and stops a poller of a diode writer, underlying writers are not closed.
+func closeDiode(w io.Writer) {
+	if c, ok := w.(diode.Writer); ok {
+		c.Close()
+	}
+}
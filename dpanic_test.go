@@ -0,0 +1,68 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestDPanicProduction(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	logger.DPanic("should not happen", "key", "value")
+	if !strings.Contains(b.String(), `"level":"error"`) || !strings.Contains(b.String(), `"message":"should not happen"`) {
+		t.Errorf("expected error message, got %s", b.String())
+	}
+}
+
+func TestDPanicDevelopment(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithDevelopment())
+
+	defer func() {
+		r := recover()
+		if r != "count is 5" {
+			t.Errorf("expected panic with message, got %v", r)
+		}
+		if !strings.Contains(b.String(), `"message":"count is 5"`) {
+			t.Errorf("expected message to be logged before panic, got %s", b.String())
+		}
+	}()
+	logger.DPanicf("count is %d", 5, "key", "value")
+}
+
+func TestDPanicfDevelopmentFormat(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithDevelopment())
+
+	defer func() {
+		r := recover()
+		if r != "100% done,    42" {
+			t.Errorf("expected panic with formatted message, got %v", r)
+		}
+		if !strings.Contains(b.String(), `"message":"100% done,    42"`) {
+			t.Errorf("expected message to be logged before panic, got %s", b.String())
+		}
+	}()
+	logger.DPanicf("%d%% done, %*d", 100, 5, 42, "key", "value")
+}
+
+func TestDevelopmentCaller(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithDevelopment())
+
+	logger.Info("message")
+	if !strings.Contains(b.String(), "dpanic_test.go:") {
+		t.Errorf("expected caller in development mode, got %s", b.String())
+	}
+
+	b.Reset()
+	logger = logze.New(logze.NewConfig(&b).WithNoDiode().WithDevelopment().WithCallerLevels())
+	logger.Info("message")
+	if strings.Contains(b.String(), `"caller"`) {
+		t.Errorf("expected no caller if caller levels are set explicitly, got %s", b.String())
+	}
+}
@@ -0,0 +1,156 @@
+package logze
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// levelVar is a log level that can be changed at runtime, it is shared between copies of [Logger].
+// Level of a child follows the level of its parent until it is set explicitly (pinned)
+// or it is set by name for named loggers.
+type levelVar struct {
+	parent *levelVar
+	named  *namedLevel
+	v      atomic.Int32
+	pinned atomic.Bool
+}
+
+func newLevelVar(level zerolog.Level) *levelVar {
+	v := &levelVar{}
+	v.set(level)
+	return v
+}
+
+// child returns a new levelVar that follows the level of v.
+func (v *levelVar) child() *levelVar {
+	if v == nil {
+		return nil
+	}
+	return &levelVar{parent: v}
+}
+
+// namedChild returns a new levelVar that follows the level of v until the named level is set.
+func (v *levelVar) namedChild(named *namedLevel) *levelVar {
+	if v == nil {
+		return nil
+	}
+	return &levelVar{parent: v, named: named}
+}
+
+// pinnedChild returns a new levelVar with its own level that is not changed with the level of v.
+func (v *levelVar) pinnedChild(level zerolog.Level) *levelVar {
+	if v == nil {
+		return newLevelVar(level)
+	}
+	c := v.child()
+	c.set(level)
+	return c
+}
+
+func (v *levelVar) get() zerolog.Level {
+	for v.parent != nil && !v.pinned.Load() {
+		if v.named != nil {
+			if level, ok := v.named.get(); ok {
+				return level
+			}
+		}
+		v = v.parent
+	}
+	return zerolog.Level(v.v.Load())
+}
+
+// set changes the level and pins it, so it won't follow the level of a parent.
+func (v *levelVar) set(level zerolog.Level) {
+	v.v.Store(int32(level))
+	v.pinned.Store(true)
+}
+
+// unpin makes the level follow the level of a parent again.
+func (v *levelVar) unpin() {
+	if v.parent != nil {
+		v.pinned.Store(false)
+	}
+}
+
+// enabled returns true if messages of provided level should be logged.
+// Nil levelVar doesn't filter anything, in that case level of underlying [zerolog.Logger] is used.
+func (v *levelVar) enabled(level zerolog.Level) bool {
+	return v == nil || level >= v.get()
+}
+
+// ignoreVar is a list of messages to ignore that can be changed at runtime, it is shared between copies of [Logger].
+// The list is compiled to a matcher when it is set, so it is not rebuilt on every log call.
+type ignoreVar struct {
+	v atomic.Pointer[ignoreMatcher]
+}
+
+func newIgnoreVar(toIgnore []string) *ignoreVar {
+	v := &ignoreVar{}
+	v.set(toIgnore)
+	return v
+}
+
+// match returns true if the message contains any of messages to ignore.
+func (v *ignoreVar) match(msg string) bool {
+	if v == nil {
+		return false
+	}
+	m := v.v.Load()
+	return m != nil && m.match(msg)
+}
+
+func (v *ignoreVar) set(toIgnore []string) {
+	v.v.Store(newIgnoreMatcher(toIgnore))
+}
+
+// swapWriter is an [io.Writer] which underlying output can be replaced at runtime.
+type swapWriter struct {
+	v atomic.Pointer[output]
+}
+
+// output is a combined writer of [Logger] with its writers and counter of entries dropped by diode.
+type output struct {
+	w       io.Writer
+	writers []*trackedWriter
+	dropped *dropCounter
+	// buffer is a diode or async writer, nil if it is disabled
+	buffer io.Writer
+	// throttle limits output, nil if it is disabled
+	throttle *throttleWriter
+}
+
+func newSwapWriter(out *output) *swapWriter {
+	sw := &swapWriter{}
+	sw.swap(out)
+	return sw
+}
+
+func (sw *swapWriter) Write(p []byte) (int, error) {
+	return sw.v.Load().w.Write(p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter] to keep level-aware writers working after wrapping.
+func (sw *swapWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w := sw.v.Load().w
+	if lw, ok := w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
+
+func (sw *swapWriter) load() *output {
+	return sw.v.Load()
+}
+
+// swap replaces underlying output and returns the previous one.
+func (sw *swapWriter) swap(out *output) *output {
+	return sw.v.Swap(out)
+}
+
+// noCloseWriter hides Close method of a writer, so diode writer can be closed
+// without closing its destination (e.g. [os.Stderr]).
+type noCloseWriter struct {
+	io.Writer
+}
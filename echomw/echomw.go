@@ -0,0 +1,165 @@
+// Package echomw provides an Echo middleware that writes access logs using [logze.Logger],
+// so Echo users don't need to use zerolog's hlog separately.
+//
+// Every entry has a method, route, path, status, number of written bytes, latency, remote IP and request ID.
+// Request ID is taken from a request header or from a response header set by Echo's RequestID middleware.
+// A request-scoped logger with the request ID is passed to handlers, use [logze.FromContext]
+// with a context of a request to get it.
+//
+// It is a separate module, so the core logze package doesn't depend on Echo.
+package echomw
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureEchoMiddleware)
+}
+
+// DefaultMessage is a default message of access log entries.
+const DefaultMessage = "request"
+
+// Enumerating names of fields of an access log entry.
+var (
+	MethodFieldName    = "method"
+	RouteFieldName     = "route"
+	PathFieldName      = "path"
+	StatusFieldName    = "status"
+	BytesFieldName     = "bytes"
+	LatencyFieldName   = "latency"
+	RemoteIPFieldName  = "remote_ip"
+	RequestIDFieldName = "request_id"
+)
+
+// Option changes a behaviour of [Middleware].
+type Option func(o *options)
+
+// WithLevel sets a level of access log entries, default value is [logze.LevelInfo].
+// Requests that end with an error returned by a handler are logged in error level.
+func WithLevel(level string) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithRouteLevel sets a level of access log entries of requests with a path that starts with provided prefix,
+// e.g. [logze.LevelDisabled] for "/healthz" to skip probes. The longest matching prefix is used.
+func WithRouteLevel(prefix, level string) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, route{prefix: prefix, level: level})
+	}
+}
+
+// WithMessage sets a message of access log entries, default value is [DefaultMessage].
+func WithMessage(msg string) Option {
+	return func(o *options) {
+		o.message = msg
+	}
+}
+
+// WithSkipper sets a function that skips the middleware for some requests, like Skipper in configs of Echo middlewares.
+func WithSkipper(skipper func(c echo.Context) bool) Option {
+	return func(o *options) {
+		o.skipper = skipper
+	}
+}
+
+// Middleware returns [echo.MiddlewareFunc] that writes an access log entry for every request.
+// An error returned by a handler is passed to [echo.Context.Error] before logging to get a final status
+// of a response, as Echo's Logger middleware does.
+func Middleware(l logze.Logger, opts ...Option) echo.MiddlewareFunc {
+	o := options{
+		level:   logze.LevelInfo,
+		message: DefaultMessage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Longest prefixes go first, so the first match is the most specific one
+	sort.SliceStable(o.routes, func(i, j int) bool {
+		return len(o.routes[i].prefix) > len(o.routes[j].prefix)
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if o.skipper != nil && o.skipper(c) {
+				return next(c)
+			}
+			start := time.Now()
+			req, res := c.Request(), c.Response()
+
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if id == "" {
+				id = res.Header().Get(echo.HeaderXRequestID)
+			}
+			lg := l
+			if id != "" {
+				lg = l.WithFields(RequestIDFieldName, id)
+			}
+			c.SetRequest(req.WithContext(lg.WithContext(req.Context())))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			level := o.levelFor(req.URL.Path)
+			if level == logze.LevelDisabled {
+				return err
+			}
+			if err != nil {
+				level = logze.LevelError
+			}
+			if !lg.Enabled(level) {
+				return err
+			}
+			fields := []any{
+				MethodFieldName, req.Method,
+				RouteFieldName, c.Path(),
+				PathFieldName, req.URL.Path,
+				StatusFieldName, res.Status,
+				BytesFieldName, res.Size,
+				LatencyFieldName, time.Since(start),
+				RemoteIPFieldName, c.RealIP(),
+			}
+			if id == "" {
+				// Request ID can be set by a next middleware
+				if id = res.Header().Get(echo.HeaderXRequestID); id != "" {
+					fields = append(fields, RequestIDFieldName, id)
+				}
+			}
+			if err != nil {
+				fields = append(fields, "error", err)
+			}
+			lg.LogAttrs(level, o.message, fields)
+			return err
+		}
+	}
+}
+
+type route struct {
+	prefix string
+	level  string
+}
+
+type options struct {
+	level   string
+	message string
+	routes  []route
+	skipper func(c echo.Context) bool
+}
+
+func (o options) levelFor(path string) string {
+	for _, r := range o.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.level
+		}
+	}
+	return o.level
+}
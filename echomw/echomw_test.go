@@ -0,0 +1,90 @@
+package echomw_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/echomw"
+)
+
+func TestMiddleware(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	e := echo.New()
+	e.Use(middleware.RequestID(), echomw.Middleware(logger, echomw.WithRouteLevel("/healthz", logze.LevelDisabled)))
+	e.GET("/users/:id", func(c echo.Context) error {
+		logze.FromContext(c.Request().Context()).Info("handler")
+		return c.String(http.StatusOK, "hello")
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "not found")
+	})
+	e.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	entries := decode(t, &b)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	id, _ := entries[1]["request_id"].(string)
+	if id == "" || entries[0]["request_id"] != id {
+		t.Errorf("expected request ID from RequestID middleware in both entries, got %v", entries)
+	}
+	expected := map[string]any{
+		"level":   "info",
+		"message": echomw.DefaultMessage,
+		"method":  "GET",
+		"route":   "/users/:id",
+		"path":    "/users/42",
+		"status":  float64(http.StatusOK),
+		"bytes":   float64(5),
+	}
+	for k, v := range expected {
+		if entries[1][k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entries[1][k])
+		}
+	}
+	if _, ok := entries[1]["latency"]; !ok {
+		t.Errorf("expected latency, got %v", entries[1])
+	}
+
+	b.Reset()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	entries = decode(t, &b)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+	if len(entries) != 1 || entries[0]["level"] != "error" || entries[0]["status"] != float64(http.StatusNotFound) {
+		t.Errorf("expected error entry with final status, got %v", entries)
+	}
+
+	b.Reset()
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if b.Len() != 0 {
+		t.Errorf("expected no entry for disabled route, got %s", b.String())
+	}
+}
+
+func decode(t *testing.T, b *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
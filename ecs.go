@@ -0,0 +1,117 @@
+package logze
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ECSVersion is a version of Elastic Common Schema that is added to entries in ecs.version field by [Config.WithECS].
+var ECSVersion = "8.11.0"
+
+// WithECS returns [Config] with JSON output in Elastic Common Schema, so entries land in Elastic
+// without an ingest pipeline: standard fields are named @timestamp, log.level, message, error.message,
+// log.origin.file.name and error.stack_trace, a stack trace is rendered as text and ecs.version field is added.
+// Dotted names are expanded to objects by Elasticsearch. It replaces names set by [Config.WithFieldNames]
+// and disables [Config.WithGCP] and [Config.WithDatadog].
+func (c Config) WithECS() Config {
+	c.ECS = true
+	c.GCP = false
+	c.Datadog = nil
+	c.FieldNames = FieldNames{
+		Time:    "@timestamp",
+		Level:   "log.level",
+		Message: "message",
+		Error:   "error.message",
+		Caller:  "log.origin.file.name",
+		Stack:   "error.stack_trace",
+	}
+	return c
+}
+
+// withECS returns writers where all JSON writers get entries in Elastic Common Schema.
+// Fields are renamed by inner writers, so entries are changed before renaming.
+func withECS(writers []io.Writer) []io.Writer {
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		if hasOwnFormat(w) {
+			out[i] = w
+			continue
+		}
+		out[i] = convertWriter{w: w, convert: convertECS}
+	}
+	return out
+}
+
+// convertECS adds ecs.version field to a JSON entry and renders its stack trace as text.
+func convertECS(p []byte) []byte {
+	if len(p) < 2 || p[0] != '{' {
+		// Not a JSON entry, e.g. raw bytes written with Logger.Write
+		return p
+	}
+	out := make([]byte, 0, len(p)+32)
+	out = append(out, `{"ecs.version":`...)
+	out = strconv.AppendQuote(out, ECSVersion)
+	if p[1] != '}' {
+		out = append(out, ',')
+	}
+
+	start, end, ok := findStack(p)
+	if !ok {
+		return append(out, p[1:]...)
+	}
+	var frames []map[string]any
+	if err := json.Unmarshal(p[start:end], &frames); err != nil {
+		return append(out, p[1:]...)
+	}
+	text, _ := json.Marshal(stackText(frames))
+	out = append(out, p[1:start]...)
+	out = append(out, text...)
+	return append(out, p[end:]...)
+}
+
+// findStack returns bounds of an array value of the top level stack field of a JSON entry.
+func findStack(p []byte) (int, int, bool) {
+	if !bytes.Contains(p, []byte(`"`+zerolog.ErrorStackFieldName+`":[`)) {
+		return 0, 0, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if _, err := dec.Token(); err != nil {
+		return 0, 0, false
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+		start := int(dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return 0, 0, false
+		}
+		if tok != zerolog.ErrorStackFieldName || len(value) == 0 || value[0] != '[' {
+			continue
+		}
+		// Offset is after the key, the value starts after a colon and optional spaces
+		start += bytes.IndexByte(p[start:], '[')
+		return start, int(dec.InputOffset()), true
+	}
+	return 0, 0, false
+}
+
+// stackText renders frames of a stack trace like %+v verb of github.com/pkg/errors does.
+func stackText(frames []map[string]any) string {
+	var b strings.Builder
+	for i, f := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%v\n\t%v:%v", f[StackSourceFunctionName], f[StackSourceFileName], f[StackSourceLineName])
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestECS(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithStackTrace().WithECS())
+
+	logger.Info("started", "port", 8080)
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry["ecs.version"] != logze.ECSVersion {
+		t.Errorf("expected ecs.version %s, got %v", logze.ECSVersion, entry["ecs.version"])
+	}
+	if entry["log.level"] != "info" || entry["message"] != "started" || entry["@timestamp"] == nil {
+		t.Errorf("expected ECS names of standard fields, got %v", entry)
+	}
+	if entry["port"] != float64(8080) {
+		t.Errorf("expected port field, got %v", entry["port"])
+	}
+
+	b.Reset()
+	logger.Err(errors.New("connection refused"), "cannot connect")
+	entry = nil
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry["error.message"] != "connection refused" {
+		t.Errorf("expected error.message, got %v", entry["error.message"])
+	}
+	stack, ok := entry["error.stack_trace"].(string)
+	if !ok {
+		t.Fatalf("expected error.stack_trace as a string, got %v", entry["error.stack_trace"])
+	}
+	if !strings.Contains(stack, "TestECS\n\tecs_test.go:") {
+		t.Errorf("expected frame of the test in a stack trace, got %q", stack)
+	}
+}
+
+func TestECSConfigFile(t *testing.T) {
+	cfg, err := logze.ParseConfig([]byte("ecs: true\nwriters: [stdout]\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ECS || cfg.FieldNames.Level != "log.level" {
+		t.Errorf("expected ECS mode, got %v %+v", cfg.ECS, cfg.FieldNames)
+	}
+}
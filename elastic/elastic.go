@@ -0,0 +1,376 @@
+// Package elastic provides an [io.Writer] that indexes log entries to Elasticsearch or OpenSearch
+// using the _bulk API:
+//
+//	fallback, _ := os.OpenFile("failed.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+//	w := elastic.New("http://elasticsearch:9200", elastic.WithIndex("logs-%Y.%m.%d"), elastic.WithFallback(fallback))
+//	logger := logze.New(logze.NewConfig(w).WithFieldNames(logze.FieldNames{Time: "@timestamp"}))
+//	defer logger.Close()
+//
+// Entries are indexed in background in batches. Failed requests are retried with exponential backoff, entries that
+// can't be indexed are written to a fallback writer, so they are not lost when the cluster is unavailable.
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/internal/batcher"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureElastic)
+}
+
+const (
+	// DefaultIndex is a default template of an index name.
+	DefaultIndex = "logs-%Y.%m.%d"
+
+	// DefaultBatchSize is a default max number of entries in one bulk request.
+	DefaultBatchSize = 1000
+	// DefaultBatchWait is a default max time an entry waits in a batch before it is indexed.
+	DefaultBatchWait = time.Second
+	// DefaultQueueSize is a default max number of entries waiting to be indexed.
+	DefaultQueueSize = 10000
+	// DefaultMinBackoff is a default delay before the first retry of a failed request.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is a default max delay between retries of a failed request.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultMaxRetries is a default max number of retries of a failed request.
+	DefaultMaxRetries = 10
+)
+
+// ErrQueueFull is passed to the drop callback when entries are dropped because the queue is full.
+var ErrQueueFull = errors.New("elastic: queue is full")
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithIndex sets a template of an index name, default value is [DefaultIndex]. The template can contain
+// %Y (year), %m (month), %d (day) and %H (hour) of the time in UTC when an entry is written, and %% for '%'.
+// Entries are added with "create" operation, so the name of a data stream can be used as well.
+func WithIndex(template string) Option {
+	return func(o *options) {
+		o.index = template
+	}
+}
+
+// WithFallback sets a writer for entries that can't be indexed, e.g. a local file. Default value is nil,
+// such entries are dropped.
+func WithFallback(w io.Writer) Option {
+	return func(o *options) {
+		o.fallback = w
+	}
+}
+
+// WithBatch sets a max number of entries in one bulk request and a max time an entry waits in a batch,
+// default values are [DefaultBatchSize] and [DefaultBatchWait].
+func WithBatch(size int, wait time.Duration) Option {
+	return func(o *options) {
+		o.batchSize = size
+		o.batchWait = wait
+	}
+}
+
+// WithQueueSize sets a max number of entries waiting to be indexed, default value is [DefaultQueueSize].
+// New entries are dropped when the queue is full.
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithRetry sets a delay before the first retry of a failed request, a max delay between retries and a max number
+// of retries, default values are [DefaultMinBackoff], [DefaultMaxBackoff] and [DefaultMaxRetries].
+// Network errors and 429 and 5xx codes of requests and entries are retried.
+func WithRetry(minBackoff, maxBackoff time.Duration, maxRetries int) Option {
+	return func(o *options) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithBasicAuth sets a user and a password of requests.
+func WithBasicAuth(user, password string) Option {
+	return func(o *options) {
+		o.user = user
+		o.password = password
+	}
+}
+
+// WithAPIKey sets an API key of requests, it is a base64 encoded "id:key" pair.
+func WithAPIKey(key string) Option {
+	return WithHeader("Authorization", "ApiKey "+key)
+}
+
+// WithHeader adds a header to requests.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithHTTPClient sets a client of requests, default value is a client with 10s timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithOnDrop sets a function that is called with a number of entries that are dropped or written to the fallback
+// writer and the reason, default function prints a warning to stderr.
+func WithOnDrop(onDrop func(n int, err error)) Option {
+	return func(o *options) {
+		o.onDrop = onDrop
+	}
+}
+
+type options struct {
+	index      string
+	fallback   io.Writer
+	batchSize  int
+	batchWait  time.Duration
+	queueSize  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	maxRetries int
+	user       string
+	password   string
+	headers    http.Header
+	client     *http.Client
+	onDrop     func(n int, err error)
+}
+
+type entry struct {
+	index string
+	line  []byte
+}
+
+// Writer indexes entries in background, it is safe for concurrent use.
+// [Writer.Close] must be called to index the rest of entries.
+type Writer struct {
+	url string
+	o   options
+	b   *batcher.Batcher[entry]
+}
+
+// New returns [Writer] indexing entries to a cluster with provided URL (e.g. "http://elasticsearch:9200")
+// and starts its background goroutine.
+func New(url string, opts ...Option) *Writer {
+	o := options{
+		index:      DefaultIndex,
+		batchSize:  DefaultBatchSize,
+		batchWait:  DefaultBatchWait,
+		queueSize:  DefaultQueueSize,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		maxRetries: DefaultMaxRetries,
+		headers:    make(http.Header),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		onDrop: func(n int, err error) {
+			fmt.Fprintf(os.Stderr, "WRN: elastic failed to index %d entries: %v\n", n, err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = DefaultBatchSize
+	}
+	if o.batchWait <= 0 {
+		o.batchWait = DefaultBatchWait
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = DefaultQueueSize
+	}
+
+	w := &Writer{url: strings.TrimSuffix(url, "/") + "/_bulk", o: o}
+	w.b = batcher.New(batcher.Config[entry]{
+		Size:         o.batchSize,
+		Wait:         o.batchWait,
+		QueueSize:    o.queueSize,
+		MinBackoff:   o.minBackoff,
+		MaxBackoff:   o.maxBackoff,
+		MaxRetries:   o.maxRetries,
+		ErrQueueFull: ErrQueueFull,
+		OnDrop:       w.fail,
+	}, w.send)
+	return w
+}
+
+// Write adds a copy of an entry to the queue, it never blocks. If the queue is full, the entry is written
+// to the fallback writer or dropped. It returns [os.ErrClosed] after [Writer.Close].
+func (w *Writer) Write(p []byte) (int, error) {
+	e := entry{
+		index: IndexName(w.o.index, time.Now()),
+		line:  bytes.TrimRight(append([]byte(nil), p...), "\n"),
+	}
+	if err := w.b.Add(e); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush indexes entries that are written before the call and returns an error of the request.
+func (w *Writer) Flush() error {
+	return w.b.Flush()
+}
+
+// Dropped returns a number of entries that are dropped or written to the fallback writer since the writer is created.
+func (w *Writer) Dropped() int64 {
+	return w.b.Dropped()
+}
+
+// Close stops retrying, indexes the rest of entries once and stops the background goroutine.
+// The fallback writer is not closed.
+func (w *Writer) Close() error {
+	if n := w.b.Close(); n > 0 {
+		return fmt.Errorf("elastic: %d entries are not indexed on close", n)
+	}
+	return nil
+}
+
+// IndexName returns a name of an index from the template for provided time in UTC, see [WithIndex].
+func IndexName(template string, t time.Time) string {
+	if !strings.Contains(template, "%") {
+		return template
+	}
+	t = t.UTC()
+	out := make([]byte, 0, len(template)+8)
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' || i+1 == len(template) {
+			out = append(out, template[i])
+			continue
+		}
+		i++
+		switch template[i] {
+		case 'Y':
+			out = strconv.AppendInt(out, int64(t.Year()), 10)
+		case 'm':
+			out = appendTwoDigits(out, int(t.Month()))
+		case 'd':
+			out = appendTwoDigits(out, t.Day())
+		case 'H':
+			out = appendTwoDigits(out, t.Hour())
+		case '%':
+			out = append(out, '%')
+		default:
+			out = append(out, '%', template[i])
+		}
+	}
+	return string(out)
+}
+
+func appendTwoDigits(dst []byte, n int) []byte {
+	return append(dst, byte('0'+n/10), byte('0'+n%10))
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// send makes a bulk request, it returns entries that should be retried and an error if some entries
+// are not indexed. Rejected entries are written to the fallback writer or dropped.
+func (w *Writer) send(batch []entry) (retryable []entry, err error) {
+	var body bytes.Buffer
+	for _, e := range batch {
+		body.WriteString(`{"create":{"_index":`)
+		index, _ := json.Marshal(e.index)
+		body.Write(index)
+		body.WriteString("}}\n")
+		body.Write(e.line)
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.url, &body)
+	if err != nil {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	req.Header = w.o.headers.Clone()
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if w.o.user != "" {
+		req.SetBasicAuth(w.o.user, w.o.password)
+	}
+
+	resp, err := w.o.client.Do(req)
+	if err != nil {
+		return batch, fmt.Errorf("elastic: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err = fmt.Errorf("elastic: %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if isRetryable(resp.StatusCode) {
+			return batch, err
+		}
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+
+	var br bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		// Request is accepted, so entries are not resent to avoid duplicates
+		return nil, fmt.Errorf("elastic: decode response: %w", err)
+	}
+	if !br.Errors {
+		return nil, nil
+	}
+	var failed []entry
+	for i, item := range br.Items {
+		if i >= len(batch) {
+			break
+		}
+		for _, res := range item {
+			switch {
+			case res.Status/100 == 2:
+			case isRetryable(res.Status):
+				retryable = append(retryable, batch[i])
+				err = fmt.Errorf("elastic: %s: %s", res.Error.Type, res.Error.Reason)
+			default:
+				failed = append(failed, batch[i])
+				err = fmt.Errorf("elastic: %s: %s", res.Error.Type, res.Error.Reason)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		w.b.Drop(failed, fmt.Errorf("elastic: entries are rejected"))
+	}
+	return retryable, err
+}
+
+func isRetryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// fail reports entries that are not indexed and writes them to the fallback writer.
+func (w *Writer) fail(entries []entry, err error) {
+	if w.o.onDrop != nil {
+		w.o.onDrop(len(entries), err)
+	}
+	if w.o.fallback == nil {
+		return
+	}
+	for _, e := range entries {
+		// Fallback writer gets entries as they are written by the logger
+		_, _ = w.o.fallback.Write(append(e.line, '\n'))
+	}
+}
@@ -0,0 +1,140 @@
+package elastic_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/elastic"
+)
+
+// fakeElastic accepts bulk requests, it rejects entries which contain "reject" and "busy" for the first time.
+type fakeElastic struct {
+	mu      sync.Mutex
+	indexed map[string][]string
+	busy    int
+	auth    string
+}
+
+func (f *fakeElastic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.indexed == nil {
+		f.indexed = make(map[string][]string)
+	}
+	f.auth = r.Header.Get("Authorization")
+
+	var items []string
+	hasErrors := false
+	sc := bufio.NewScanner(r.Body)
+	for sc.Scan() {
+		var action struct {
+			Create struct {
+				Index string `json:"_index"`
+			} `json:"create"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &action); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		sc.Scan()
+		doc := sc.Text()
+		switch {
+		case strings.Contains(doc, "reject"):
+			hasErrors = true
+			items = append(items, `{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad"}}}`)
+		case strings.Contains(doc, "busy") && f.busy == 0:
+			f.busy++
+			hasErrors = true
+			items = append(items, `{"create":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"busy"}}}`)
+		default:
+			f.indexed[action.Create.Index] = append(f.indexed[action.Create.Index], doc)
+			items = append(items, `{"create":{"status":201}}`)
+		}
+	}
+	fmt.Fprintf(w, `{"errors":%t,"items":[%s]}`, hasErrors, strings.Join(items, ","))
+}
+
+func TestWriter(t *testing.T) {
+	f := &fakeElastic{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	var fallback bytes.Buffer
+	w := elastic.New(srv.URL, elastic.WithIndex("logs-test"), elastic.WithFallback(&fallback),
+		elastic.WithRetry(time.Millisecond, time.Millisecond, 3), elastic.WithBasicAuth("user", "pass"),
+		elastic.WithOnDrop(nil))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Info("first")
+	logger.Info("reject")
+	logger.Info("busy")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	docs := f.indexed["logs-test"]
+	if len(docs) != 2 || !strings.Contains(docs[0], `"message":"first"`) || !strings.Contains(docs[1], `"message":"busy"`) {
+		t.Errorf("expected first and busy entries to be indexed, got %v", docs)
+	}
+	if !strings.Contains(fallback.String(), `"message":"reject"`) || strings.Count(fallback.String(), "\n") != 1 {
+		t.Errorf("expected rejected entry in fallback, got %s", fallback.String())
+	}
+	if !strings.HasPrefix(f.auth, "Basic ") {
+		t.Errorf("expected basic auth, got %q", f.auth)
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+}
+
+func TestWriterUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var fallback bytes.Buffer
+	w := elastic.New(srv.URL, elastic.WithFallback(&fallback), elastic.WithRetry(time.Millisecond, time.Millisecond, 2),
+		elastic.WithOnDrop(nil))
+	_, _ = w.Write([]byte(`{"message":"message"}` + "\n"))
+	if err := w.Flush(); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected 503 error, got %v", err)
+	}
+	if fallback.String() != `{"message":"message"}`+"\n" {
+		t.Errorf("expected entry in fallback, got %q", fallback.String())
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestIndexName(t *testing.T) {
+	ts := time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)
+	for template, expected := range map[string]string{
+		"logs-%Y.%m.%d":    "logs-2024.03.07",
+		"logs-%Y.%m.%d-%H": "logs-2024.03.07-09",
+		"logs":             "logs",
+		"100%%-%x-%":       "100%-%x-%",
+	} {
+		if name := elastic.IndexName(template, ts); name != expected {
+			t.Errorf("expected %s, got %s", expected, name)
+		}
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureElastic) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureElastic, logze.Features())
+	}
+}
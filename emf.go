@@ -0,0 +1,108 @@
+package logze
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// EMFFieldName is a name of a field with metadata of CloudWatch Embedded Metric Format.
+const EMFFieldName = "_aws"
+
+// Units of CloudWatch metrics, see [Metric].
+const (
+	UnitNone         = "None"
+	UnitCount        = "Count"
+	UnitPercent      = "Percent"
+	UnitSeconds      = "Seconds"
+	UnitMilliseconds = "Milliseconds"
+	UnitMicroseconds = "Microseconds"
+	UnitBytes        = "Bytes"
+	UnitCountSecond  = "Count/Second"
+)
+
+// Metric is a metric of CloudWatch Embedded Metric Format, see [Logger.Metrics].
+type Metric struct {
+	// Name is a name of a metric, it is a field of an entry with the value.
+	Name string
+
+	// Unit is a unit of a metric, e.g. [UnitCount] or [UnitMilliseconds]. Default value is [UnitNone].
+	Unit string
+
+	// Value is a value of a metric.
+	Value float64
+}
+
+// DurationMetric returns [Metric] with a duration in milliseconds.
+func DurationMetric(name string, d time.Duration) Metric {
+	return Metric{Name: name, Unit: UnitMilliseconds, Value: float64(d) / float64(time.Millisecond)}
+}
+
+// Metrics logs an entry in CloudWatch Embedded Metric Format, so Lambda and Fargate services get metrics
+// from logs without an agent. Metrics and dimensions become fields of the entry and [EMFFieldName] field
+// describes them to CloudWatch:
+//
+//	logger.Metrics("billing", map[string]string{"service": "api"},
+//		logze.Metric{Name: "orders", Unit: logze.UnitCount, Value: float64(n)},
+//		logze.DurationMetric("latency", time.Since(start)),
+//	)
+//
+// The entry is written without level, so it is not filtered by the level of the logger. Metrics without names
+// are skipped, the entry is not written if there are no metrics.
+func (l Logger) Metrics(namespace string, dimensions map[string]string, metrics ...Metric) {
+	ev := l.newEvent(zerolog.NoLevel)
+	if ev == nil {
+		return
+	}
+
+	type metricDefinition struct {
+		Name string `json:"Name"`
+		Unit string `json:"Unit"`
+	}
+	type metricDirective struct {
+		Namespace  string             `json:"Namespace"`
+		Dimensions [][]string         `json:"Dimensions"`
+		Metrics    []metricDefinition `json:"Metrics"`
+	}
+
+	directive := metricDirective{Namespace: namespace, Dimensions: [][]string{{}}}
+	for _, m := range metrics {
+		if m.Name == "" {
+			continue
+		}
+		if m.Unit == "" {
+			m.Unit = UnitNone
+		}
+		directive.Metrics = append(directive.Metrics, metricDefinition{Name: m.Name, Unit: m.Unit})
+		ev = ev.Float64(m.Name, m.Value)
+	}
+	if len(directive.Metrics) == 0 {
+		ev.Discard()
+		return
+	}
+
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		directive.Dimensions[0] = append(directive.Dimensions[0], k)
+		ev = ev.Str(k, dimensions[k])
+	}
+
+	aws, err := json.Marshal(struct {
+		Timestamp         int64             `json:"Timestamp"`
+		CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+	}{
+		Timestamp:         time.Now().UnixMilli(),
+		CloudWatchMetrics: []metricDirective{directive},
+	})
+	if err != nil {
+		ev.Discard()
+		return
+	}
+	ev.RawJSON(EMFFieldName, aws).Send()
+}
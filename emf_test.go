@@ -0,0 +1,61 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestMetrics(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelError))
+
+	logger.Metrics("billing", map[string]string{"service": "api", "env": "prod"},
+		logze.Metric{Name: "orders", Unit: logze.UnitCount, Value: 5},
+		logze.DurationMetric("latency", 1500*time.Microsecond),
+		logze.Metric{Value: 1},
+	)
+
+	var entry struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		Orders  float64 `json:"orders"`
+		Latency float64 `json:"latency"`
+		Service string  `json:"service"`
+		Env     string  `json:"env"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry.AWS.Timestamp == 0 || len(entry.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("expected EMF metadata, got %s", b.String())
+	}
+	directive := entry.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "billing" {
+		t.Errorf("expected namespace billing, got %q", directive.Namespace)
+	}
+	if len(directive.Dimensions) != 1 || len(directive.Dimensions[0]) != 2 || directive.Dimensions[0][0] != "env" {
+		t.Errorf("expected sorted dimensions [[env service]], got %v", directive.Dimensions)
+	}
+	if len(directive.Metrics) != 2 || directive.Metrics[0].Unit != logze.UnitCount || directive.Metrics[1].Unit != logze.UnitMilliseconds {
+		t.Errorf("expected 2 metric definitions, got %v", directive.Metrics)
+	}
+	if entry.Orders != 5 || entry.Latency != 1.5 || entry.Service != "api" || entry.Env != "prod" {
+		t.Errorf("expected values of metrics and dimensions, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Metrics("billing", nil, logze.Metric{Value: 1})
+	if b.Len() != 0 {
+		t.Errorf("expected no entry without metrics, got %s", b.String())
+	}
+}
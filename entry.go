@@ -0,0 +1,369 @@
+package logze
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// ReemitFieldName is a field name for a number of times an entry was re-emitted with [Logger.Emit].
+var ReemitFieldName = "logze_reemit"
+
+// MaxReemitDepth is a maximum number of times an entry can be re-emitted with [Logger.Emit].
+// It protects from infinite loops when entry hooks of loggers re-emit entries to each other.
+var MaxReemitDepth = 3
+
+// ErrReemitLoop is returned by [Logger.Emit] when an entry was re-emitted more than [MaxReemitDepth] times.
+var ErrReemitLoop = errors.New("entry is re-emitted too many times")
+
+// Entry is a parsed log entry. It is passed to entry hooks (see [Config.WithEntryHooks]),
+// that can clone it and re-emit at a different level or to a different logger with [Logger.Emit].
+type Entry struct {
+	// Level is a level of an entry, it is empty for messages without level.
+	Level string
+
+	// Message is a message of an entry.
+	Message string
+
+	// Fields are all other fields of an entry including time, error and logger fields.
+	// Numbers are represented as [json.Number].
+	Fields map[string]any
+
+	reemit int
+}
+
+// ParseEntry parses an entry from a JSON line written by [Logger].
+func ParseEntry(p []byte) (Entry, error) {
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return Entry{}, fmt.Errorf("parse entry: %w", err)
+	}
+
+	e := Entry{Fields: fields}
+	if level, ok := fields[zerolog.LevelFieldName].(string); ok {
+		e.Level = level
+		delete(fields, zerolog.LevelFieldName)
+	}
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		e.Message = msg
+		delete(fields, zerolog.MessageFieldName)
+	}
+	if n, ok := fields[ReemitFieldName].(json.Number); ok {
+		depth, _ := strconv.Atoi(n.String())
+		e.reemit = depth
+		delete(fields, ReemitFieldName)
+	}
+	return e, nil
+}
+
+// Clone returns a deep copy of the entry, so it can be changed without affecting the original one.
+func (e Entry) Clone() Entry {
+	e.Fields = cloneValue(e.Fields).(map[string]any)
+	return e
+}
+
+// WithLevel returns a copy of the entry with provided level.
+func (e Entry) WithLevel(level string) Entry {
+	e = e.Clone()
+	e.Level = level
+	return e
+}
+
+// WithFields returns a copy of the entry with added fields, provided as (key, value) pairs.
+func (e Entry) WithFields(fields ...any) Entry {
+	e = e.Clone()
+	fields = expandFields(fields)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := fields[i].(string); ok {
+			e.Fields[key] = fields[i+1]
+		}
+	}
+	return e
+}
+
+// Bytes returns the entry encoded as a JSON line.
+func (e Entry) Bytes() []byte {
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	ev := l.Log()
+	if e.Level != "" {
+		ev = ev.Str(zerolog.LevelFieldName, e.Level)
+	}
+	if e.reemit > 0 {
+		ev = ev.Int(ReemitFieldName, e.reemit)
+	}
+	ev.Fields(e.Fields).Msg(e.Message)
+	return buf.Bytes()
+}
+
+// FieldString returns a value of a field as a string, e.g. to use it as a label or a header in a sink.
+// Strings are returned as is and other values are encoded in JSON, level and message are returned by their
+// field names as well. It returns false if there is no such field or its value is null.
+func (e Entry) FieldString(name string) (string, bool) {
+	switch name {
+	case "":
+		return "", false
+	case zerolog.LevelFieldName:
+		return e.Level, e.Level != ""
+	case zerolog.MessageFieldName:
+		return e.Message, e.Message != ""
+	}
+	switch v := e.Fields[name].(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+}
+
+// Stack returns frames of a stack trace of an error logged with the entry, from the newest one to the oldest one.
+// Files are base names and functions are short names, as they are logged.
+func (e Entry) Stack() []Frame {
+	items, ok := e.Fields[zerolog.ErrorStackFieldName].([]any)
+	if !ok {
+		return nil
+	}
+	frames := make([]Frame, 0, len(items))
+	for _, item := range items {
+		item, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		frame := Entry{Fields: item}
+		f := Frame{}
+		f.Function, _ = frame.FieldString(StackSourceFunctionName)
+		f.File, _ = frame.FieldString(StackSourceFileName)
+		if line, ok := frame.FieldString(StackSourceLineName); ok {
+			f.Line, _ = strconv.Atoi(line)
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// Emit writes the entry to writers of the logger if its level is enabled. Context fields of the logger
+// are not added, use [Entry.WithFields] to add fields. Every re-emitted entry is marked with "logze_reemit" field
+// and [ErrReemitLoop] is returned if the entry was re-emitted more than [MaxReemitDepth] times.
+func (l Logger) Emit(e Entry) error {
+	if e.reemit >= MaxReemitDepth {
+		return ErrReemitLoop
+	}
+	level := zerolog.NoLevel
+	if e.Level != "" {
+		lvl, err := zerolog.ParseLevel(e.Level)
+		if err != nil {
+			return fmt.Errorf("invalid level %q", e.Level)
+		}
+		level = lvl
+	}
+	if !l.level.enabled(level) {
+		return nil
+	}
+	e.reemit++
+
+	if l.out == nil {
+		_, err := l.l.Write(e.Bytes())
+		return err
+	}
+	_, err := l.out.WriteLevel(level, e.Bytes())
+	return err
+}
+
+// EntryHook is a function that is called for every entry before writing it, see [Config.WithEntryHooks].
+type EntryHook func(e Entry)
+
+// WithEntryHooks returns [Config] with hooks that are called for every entry before writing it.
+// Hooks are called in a diode goroutine if diode is enabled. Entry is shared between hooks,
+// so it should be cloned before changing. Example of duplicating errors to a security logger:
+//
+//	cfg := logze.NewConfig(w).WithEntryHooks(func(e logze.Entry) {
+//		if e.Level == logze.LevelError {
+//			security.Emit(e.WithFields("channel", "security"))
+//		}
+//	})
+func (c Config) WithEntryHooks(hooks ...EntryHook) Config {
+	c.EntryHooks = append(c.EntryHooks, hooks...)
+	return c
+}
+
+// entryHookWriter calls entry hooks before writing an entry to the underlying writer.
+type entryHookWriter struct {
+	w     io.Writer
+	hooks []EntryHook
+}
+
+func (w entryHookWriter) Write(p []byte) (int, error) {
+	w.callHooks(p)
+	return w.w.Write(p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w entryHookWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.callHooks(p)
+	if lw, ok := w.w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.w.Write(p)
+}
+
+func (w entryHookWriter) callHooks(p []byte) {
+	e, err := ParseEntry(p)
+	if err != nil {
+		// Not a JSON entry, e.g. raw bytes written with Logger.Write
+		return
+	}
+	for _, hook := range w.hooks {
+		hook(e)
+	}
+}
+
+// PostWriteHook is a function that is called after an entry is written to writers, see [Config.WithPostWrite].
+// It gets the number of written bytes and an error of writing.
+type PostWriteHook func(e Entry, n int, err error)
+
+// WithPostWrite returns [Config] with hooks that are called after every entry is written to writers.
+// Unlike entry hooks, they are called when the entry hits its destination, so they can be used for exactly-once
+// side effects like acknowledgement to a queue or synchronization in tests. Hooks are called in a diode goroutine
+// if diode is enabled. Entry is shared between hooks, so it should be cloned before changing.
+func (c Config) WithPostWrite(hooks ...PostWriteHook) Config {
+	c.PostWriteHooks = append(c.PostWriteHooks, hooks...)
+	return c
+}
+
+// AddPostWriteHook adds a hook that is called after every entry is written by the logger and all its copies,
+// see [Config.WithPostWrite]. It returns a function that removes the hook. It is safe for concurrent use
+// and it is intended for tools like logzetest.WaitFor that subscribe to an existing logger.
+func (l Logger) AddPostWriteHook(hook PostWriteHook) (remove func()) {
+	if l.postWrite == nil {
+		return func() {}
+	}
+	return l.postWrite.add(hook)
+}
+
+// postWriteHooks is a list of post-write hooks that can be changed at runtime,
+// it is shared between copies of [Logger] and survives replacing of writers with [Logger.Reload].
+type postWriteHooks struct {
+	mu     sync.Mutex
+	nextID uint64
+	v      atomic.Pointer[[]postWriteHookEntry]
+}
+
+type postWriteHookEntry struct {
+	id   uint64
+	hook PostWriteHook
+}
+
+func newPostWriteHooks(hooks []PostWriteHook) *postWriteHooks {
+	h := &postWriteHooks{}
+	for _, hook := range hooks {
+		h.add(hook)
+	}
+	return h
+}
+
+func (h *postWriteHooks) add(hook PostWriteHook) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	id := h.nextID
+	h.store(append(h.load(), postWriteHookEntry{id: id, hook: hook}))
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		old := h.load()
+		hooks := make([]postWriteHookEntry, 0, len(old))
+		for _, e := range old {
+			if e.id != id {
+				hooks = append(hooks, e)
+			}
+		}
+		h.store(hooks)
+	}
+}
+
+func (h *postWriteHooks) load() []postWriteHookEntry {
+	if p := h.v.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// store saves a copy of hooks, so a slice that is used by writers is never changed.
+func (h *postWriteHooks) store(hooks []postWriteHookEntry) {
+	hooks = append([]postWriteHookEntry(nil), hooks...)
+	h.v.Store(&hooks)
+}
+
+// postWriteWriter calls post-write hooks after writing an entry to the underlying writer.
+type postWriteWriter struct {
+	w     io.Writer
+	hooks *postWriteHooks
+}
+
+func (w postWriteWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.callHooks(p, n, err)
+	return n, err
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w postWriteWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	n, err := lw.WriteLevel(level, p)
+	w.callHooks(p, n, err)
+	return n, err
+}
+
+func (w postWriteWriter) callHooks(p []byte, n int, err error) {
+	hooks := w.hooks.load()
+	if len(hooks) == 0 {
+		return
+	}
+	e, parseErr := ParseEntry(p)
+	if parseErr != nil {
+		// Not a JSON entry, e.g. raw bytes written with Logger.Write
+		return
+	}
+	for _, h := range hooks {
+		h.hook(e, n, err)
+	}
+}
+
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = cloneValue(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = cloneValue(val)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,184 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestParseEntry(t *testing.T) {
+	e, err := logze.ParseEntry([]byte(`{"level":"error","error":"some error","n":1,"time":"2024-01-01T00:00:00Z","message":"failed"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != logze.LevelError || e.Message != "failed" || e.Fields["error"] != "some error" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if _, ok := e.Fields["level"]; ok {
+		t.Errorf("expected no level in fields, got %+v", e.Fields)
+	}
+
+	c := e.WithLevel(logze.LevelWarn).WithFields("channel", "security")
+	if e.Level != logze.LevelError || e.Fields["channel"] != nil {
+		t.Errorf("expected original entry to be unchanged, got %+v", e)
+	}
+	out := string(c.Bytes())
+	for _, expected := range []string{`"level":"warn"`, `"channel":"security"`, `"n":1`, `"message":"failed"`, `"time":"2024-01-01T00:00:00Z"`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %s, got %s", expected, out)
+		}
+	}
+
+	if _, err := logze.ParseEntry([]byte("not json")); err == nil {
+		t.Error("expected error for not a JSON")
+	}
+}
+
+func TestEntryFieldString(t *testing.T) {
+	e, err := logze.ParseEntry([]byte(`{"level":"info","n":12345678901,"ok":true,"obj":{"a":1},"null":null,"message":"done"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range map[string]string{"level": "info", "message": "done", "n": "12345678901", "ok": "true", "obj": `{"a":1}`} {
+		if v, ok := e.FieldString(name); !ok || v != expected {
+			t.Errorf("expected %s=%s, got %q, %v", name, expected, v, ok)
+		}
+	}
+	for _, name := range []string{"", "null", "missing"} {
+		if v, ok := e.FieldString(name); ok {
+			t.Errorf("expected no %q field, got %q", name, v)
+		}
+	}
+}
+
+func TestEntryStack(t *testing.T) {
+	e, err := logze.ParseEntry([]byte(`{"level":"error","stack":[{"func":"inner","source":"a.go","line":"10"},{"func":"outer","source":"b.go","line":"20"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := e.Stack()
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(frames))
+	}
+	if f := frames[0]; f.Function != "inner" || f.File != "a.go" || f.Line != 10 {
+		t.Errorf("expected inner frame, got %+v", f)
+	}
+	if f := frames[1]; f.Function != "outer" || f.File != "b.go" || f.Line != 20 {
+		t.Errorf("expected outer frame, got %+v", f)
+	}
+	if frames := (logze.Entry{}).Stack(); frames != nil {
+		t.Errorf("expected no frames, got %+v", frames)
+	}
+}
+
+func TestEntryHooksReemit(t *testing.T) {
+	var main, security bytes.Buffer
+	securityLogger := logze.New(logze.NewConfig(&security).WithNoDiode())
+
+	logger := logze.New(logze.NewConfig(&main).WithNoDiode().WithEntryHooks(func(e logze.Entry) {
+		if e.Level == logze.LevelError {
+			if err := securityLogger.Emit(e.WithFields("channel", "security")); err != nil {
+				t.Error(err)
+			}
+		}
+	}))
+
+	logger.Info("info message")
+	logger.Err(errors.New("some error"), "error message")
+
+	if strings.Count(main.String(), "\n") != 2 {
+		t.Errorf("expected 2 messages in main logger, got %s", main.String())
+	}
+	out := security.String()
+	if strings.Count(out, "\n") != 1 || !strings.Contains(out, `"message":"error message"`) ||
+		!strings.Contains(out, `"channel":"security"`) || !strings.Contains(out, `"logze_reemit":1`) {
+		t.Errorf("expected re-emitted error in security logger, got %s", out)
+	}
+}
+
+func TestEntryReemitLoop(t *testing.T) {
+	var b bytes.Buffer
+	var logger logze.Logger
+	var loopErr error
+	logger = logze.New(logze.NewConfig(&b).WithNoDiode().WithEntryHooks(func(e logze.Entry) {
+		if err := logger.Emit(e); err != nil {
+			loopErr = err
+		}
+	}))
+
+	logger.Info("message")
+	if !errors.Is(loopErr, logze.ErrReemitLoop) {
+		t.Errorf("expected loop error, got %v", loopErr)
+	}
+	if n := strings.Count(b.String(), "\n"); n != logze.MaxReemitDepth+1 {
+		t.Errorf("expected %d messages, got %d", logze.MaxReemitDepth+1, n)
+	}
+}
+
+func TestPostWrite(t *testing.T) {
+	var (
+		b       bytes.Buffer
+		written []logze.Entry
+		total   int
+	)
+	failing := errors.New("write failed")
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithPostWrite(func(e logze.Entry, n int, err error) {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if b.Len() == 0 {
+			t.Errorf("expected entry to be written before post-write hook")
+		}
+		written = append(written, e)
+		total += n
+	}))
+
+	logger.Info("first", "key", "value")
+	logger.Debug("skipped")
+	logger.Warn("second")
+
+	if len(written) != 2 || written[0].Message != "first" || written[1].Level != logze.LevelWarn {
+		t.Fatalf("expected two entries, got %+v", written)
+	}
+	if total != b.Len() {
+		t.Errorf("expected %d written bytes, got %d", b.Len(), total)
+	}
+
+	var gotErr error
+	logger = logze.New(logze.NewConfig(errorWriter{err: failing}).WithNoDiode().WithPostWrite(func(_ logze.Entry, _ int, err error) {
+		gotErr = err
+	}))
+	logger.Info("message")
+	if !errors.Is(gotErr, failing) {
+		t.Errorf("expected write error, got %v", gotErr)
+	}
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (w errorWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestAddPostWriteHook(t *testing.T) {
+	var messages []string
+	logger := logze.New(logze.NewConfig(&bytes.Buffer{}).WithNoDiode())
+
+	remove := logger.WithFields("key", "value").AddPostWriteHook(func(e logze.Entry, _ int, _ error) {
+		messages = append(messages, e.Message)
+	})
+	logger.Info("first")
+	remove()
+	logger.Info("second")
+
+	if len(messages) != 1 || messages[0] != "first" {
+		t.Errorf("expected only first message, got %v", messages)
+	}
+
+	logze.Nop().AddPostWriteHook(func(logze.Entry, int, error) {})()
+}
@@ -0,0 +1,54 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/pkg/errors"
+)
+
+func TestErrorCaller(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithErrorCaller().WithNoDiode())
+
+	logger.Err(errors.New("some error"), "err message")
+	logger.Errf(errors.New("some error"), "errf message %d", 1)
+	logger.Error("error message")
+	logger.Errorf("errorf message %d", 1)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %s", b.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"error_caller":"`) || !strings.Contains(line, "error_caller_test.go:") {
+			t.Errorf("expected error_caller field with test file, got %s", line)
+		}
+		if strings.Contains(line, `"stack"`) {
+			t.Errorf("expected no stack, got %s", line)
+		}
+	}
+
+	b.Reset()
+	logger.Info("info message")
+	if strings.Contains(b.String(), "error_caller") {
+		t.Errorf("expected no error_caller in info level, got %s", b.String())
+	}
+}
+
+func TestGlobalErrorCaller(t *testing.T) {
+	var b bytes.Buffer
+	logze.Init(logze.NewConfig(&b).WithErrorCaller().WithNoDiode())
+
+	logze.Err(errors.New("some error"), "err message")
+	logze.Error("error message")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	for _, line := range lines {
+		if !strings.Contains(line, "error_caller_test.go:") {
+			t.Errorf("expected error_caller field with test file, got %s", line)
+		}
+	}
+}
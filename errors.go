@@ -0,0 +1,33 @@
+package logze
+
+import "strings"
+
+// multiError is an error that contains several errors, it is used instead of errors.Join to support Go 1.19.
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns a list of joined errors, it is used by [errors.Is] and [errors.As] since Go 1.20.
+func (e multiError) Unwrap() []error {
+	return e
+}
+
+// joinErrors returns an error that wraps provided non-nil errors or nil if there are no errors.
+func joinErrors(errs ...error) error {
+	var out multiError
+	for _, err := range errs {
+		if err != nil {
+			out = append(out, err)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
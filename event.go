@@ -0,0 +1,222 @@
+package logze
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Event is a message that is built with typed fields, it is returned by [Logger.E]. It is meant
+// for performance critical code: typed methods don't box values to interfaces, so the whole chain
+// doesn't allocate. Event is pooled and must not be used after [Event.Msg], [Event.Msgf] or [Event.Send].
+// All methods are no-op for nil Event, it is returned if the level is disabled.
+type Event struct {
+	l  Logger
+	ev *zerolog.Event
+	// fields is a dict of fields nested in the group of the logger, it is nil if there is no group
+	fields  *zerolog.Event
+	grouped bool
+}
+
+var eventPool = sync.Pool{
+	New: func() any {
+		return &Event{}
+	},
+}
+
+// E returns [Event] of provided level, fields are added with typed methods and the message is logged with [Event.Msg]:
+//
+//	logger.E(logze.LevelInfo).Str("key", "value").Int("n", 1).Msg("message")
+//
+// It honors level, list of messages to ignore, caller, groups, error counter and stack trace of an error,
+// but unlike other methods it doesn't call os.Exit or panic for fatal level. Unknown level is logged without level.
+// It returns nil if the level is disabled.
+func (l Logger) E(level string) *Event {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		lvl = zerolog.NoLevel
+	}
+	ev := l.newEvent(lvl)
+	if ev == nil {
+		return nil
+	}
+	if lvl == zerolog.ErrorLevel {
+		ev = l.setErrorCaller(ev)
+	}
+	e := eventPool.Get().(*Event)
+	e.l = l
+	e.ev = ev
+	if l.group != nil {
+		e.fields = zerolog.Dict().Fields(l.group.fields)
+		e.grouped = !l.group.empty()
+	}
+	return e
+}
+
+// Str adds the field with a string value.
+func (e *Event) Str(key, val string) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Str(key, val)
+	return e
+}
+
+// Strs adds the field with a slice of strings.
+func (e *Event) Strs(key string, vals []string) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Strs(key, vals)
+	return e
+}
+
+// Bytes adds the field with a value of bytes as a string.
+func (e *Event) Bytes(key string, val []byte) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Bytes(key, val)
+	return e
+}
+
+// Int adds the field with an int value.
+func (e *Event) Int(key string, val int) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Int(key, val)
+	return e
+}
+
+// Int64 adds the field with an int64 value.
+func (e *Event) Int64(key string, val int64) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Int64(key, val)
+	return e
+}
+
+// Uint64 adds the field with an uint64 value.
+func (e *Event) Uint64(key string, val uint64) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Uint64(key, val)
+	return e
+}
+
+// Float64 adds the field with a float64 value.
+func (e *Event) Float64(key string, val float64) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Float64(key, val)
+	return e
+}
+
+// Bool adds the field with a bool value.
+func (e *Event) Bool(key string, val bool) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Bool(key, val)
+	return e
+}
+
+// Dur adds the field with a duration, it is formatted as other durations of the logger.
+func (e *Event) Dur(key string, val time.Duration) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Dur(key, val)
+	return e
+}
+
+// Time adds the field with a time, it is formatted as other times of the logger.
+func (e *Event) Time(key string, val time.Time) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Time(key, val)
+	return e
+}
+
+// Any adds the field with a value of any type, it is handled as a field passed to [Logger.Info].
+// Unlike typed methods it may allocate.
+func (e *Event) Any(key string, val any) *Event {
+	if e == nil {
+		return e
+	}
+	e.target().Fields(resolveValues(expandFields([]any{key, val})))
+	return e
+}
+
+// Err adds the error to the event, it is not nested in the group of the logger. It also adds a stack trace
+// and increments the error counter as an error passed to other methods. Nil error is ignored.
+func (e *Event) Err(err error) *Event {
+	if e == nil || err == nil {
+		return e
+	}
+	e.ev = e.l.setErrorWithStack(e.ev, err)
+	return e
+}
+
+// Msg logs the event with provided message and returns the event to the pool.
+func (e *Event) Msg(msg string) {
+	if e == nil {
+		return
+	}
+	if ev := e.done(msg); ev != nil {
+		ev.Msg(msg)
+	}
+	e.release()
+}
+
+// Msgf logs the event with a message formatted using all args and returns the event to the pool.
+func (e *Event) Msgf(format string, args ...any) {
+	if e == nil {
+		return
+	}
+	if ev := e.done(format); ev != nil {
+		if _, hasWrap := formatArgs(format); hasWrap {
+			format = replaceWrapVerbs(format)
+		}
+		ev.Msgf(format, args...)
+	}
+	e.release()
+}
+
+// Send logs the event without a message and returns the event to the pool.
+func (e *Event) Send() {
+	e.Msg("")
+}
+
+// target returns an event where fields should be added: a dict of the group or the event itself.
+func (e *Event) target() *zerolog.Event {
+	if e.fields != nil {
+		e.grouped = true
+		return e.fields
+	}
+	return e.ev
+}
+
+// done returns the event with the group of the logger or nil if the message should be ignored.
+func (e *Event) done(msg string) *zerolog.Event {
+	if e.l.ignore.match(msg) {
+		return nil
+	}
+	if e.fields != nil && e.grouped {
+		ev := e.l.group.appendDict(e.ev, e.fields)
+		e.fields = nil
+		return ev
+	}
+	return e.ev
+}
+
+func (e *Event) release() {
+	*e = Event{}
+	eventPool.Put(e)
+}
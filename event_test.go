@@ -0,0 +1,90 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestEvent(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().WithNoTimestamp())
+
+	logger.E(logze.LevelInfo).Str("k", "v").Int("n", 1).Bool("ok", true).Dur("d", time.Second).Msg("message")
+	logger.E(logze.LevelWarn).Strs("s", []string{"a", "b"}).Float64("f", 1.5).Msgf("formatted %d", 2)
+	logger.E(logze.LevelDebug).Uint64("u", 3).Any("m", map[string]any{"x": 1}).Send()
+	logger.E(logze.LevelTrace).Str("k", "v").Msg("disabled")
+
+	expected := []string{
+		`{"level":"info","k":"v","n":1,"ok":true,"d":1000,"message":"message"}`,
+		`{"level":"warn","s":["a","b"],"f":1.5,"message":"formatted 2"}`,
+		`{"level":"debug","u":3,"m":{"x":1}}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestEventErrorAndIgnore(t *testing.T) {
+	var b bytes.Buffer
+	var ec logze.SimpleErrorCounter
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().WithNoTimestamp().
+		WithErrorCounter(&ec).WithToIgnore("skip me"))
+
+	logger.E(logze.LevelError).Err(errors.New("boom")).Str("k", "v").Msg("failed")
+	logger.E(logze.LevelError).Err(nil).Msg("skip me please")
+
+	expected := `{"level":"error","error":"boom","k":"v","message":"failed"}` + "\n"
+	if b.String() != expected {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+	if ec.Count.Load() != 1 {
+		t.Errorf("expected 1, got %d", ec.Count.Load())
+	}
+}
+
+func TestEventGroup(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().WithNoTimestamp())
+
+	grouped := logger.WithGroup("req").With("id", 1).WithGroup("inner")
+	grouped.E(logze.LevelInfo).Str("k", "v").Msg("nested")
+	logger.WithGroup("empty").E(logze.LevelInfo).Msg("no group")
+
+	expected := []string{
+		`{"level":"info","req":{"id":1,"inner":{"k":"v"}},"message":"nested"}`,
+		`{"level":"info","message":"no group"}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestEventNoAllocs(t *testing.T) {
+	logger := logze.New(logze.NewConfig(io.Discard).WithLevel(logze.LevelInfo).WithNoDiode())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.E(logze.LevelInfo).Str("k", "v").Int("n", 1).Dur("d", time.Second).Msg("message")
+		logger.E(logze.LevelDebug).Str("k", "v").Msg("disabled")
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs, got %v", allocs)
+	}
+}
@@ -0,0 +1,84 @@
+package logze
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Tracew logs a message in trace level adding provided key-value fields. Message is never formatted.
+func (l Logger) Tracew(msg string, fields ...any) {
+	l.log(l.newEvent(zerolog.TraceLevel), msg, fields)
+}
+
+// TracefOnly logs a formatted message in trace level, all args are used for formatting and never become fields.
+func (l Logger) TracefOnly(format string, args ...any) {
+	l.logfOnly(l.newEvent(zerolog.TraceLevel), format, args)
+}
+
+// Debugw logs a message in debug level adding provided key-value fields. Message is never formatted.
+func (l Logger) Debugw(msg string, fields ...any) {
+	l.log(l.newEvent(zerolog.DebugLevel), msg, fields)
+}
+
+// DebugfOnly logs a formatted message in debug level, all args are used for formatting and never become fields.
+func (l Logger) DebugfOnly(format string, args ...any) {
+	l.logfOnly(l.newEvent(zerolog.DebugLevel), format, args)
+}
+
+// Infow logs a message in info level adding provided key-value fields. Message is never formatted.
+func (l Logger) Infow(msg string, fields ...any) {
+	l.log(l.newEvent(zerolog.InfoLevel), msg, fields)
+}
+
+// InfofOnly logs a formatted message in info level, all args are used for formatting and never become fields.
+func (l Logger) InfofOnly(format string, args ...any) {
+	l.logfOnly(l.newEvent(zerolog.InfoLevel), format, args)
+}
+
+// Warnw logs a message in warn level adding provided key-value fields. Message is never formatted.
+func (l Logger) Warnw(msg string, fields ...any) {
+	l.log(l.newEvent(zerolog.WarnLevel), msg, fields)
+}
+
+// WarnfOnly logs a formatted message in warn level, all args are used for formatting and never become fields.
+func (l Logger) WarnfOnly(format string, args ...any) {
+	l.logfOnly(l.newEvent(zerolog.WarnLevel), format, args)
+}
+
+// Errorw logs a message in error level adding provided key-value fields. Message is never formatted.
+func (l Logger) Errorw(msg string, fields ...any) {
+	l.log(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), msg, fields)
+}
+
+// ErrorfOnly logs a formatted message in error level, all args are used for formatting and never become fields.
+func (l Logger) ErrorfOnly(format string, args ...any) {
+	l.logfOnly(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), format, args)
+}
+
+// ErrfOnly logs a formatted message with provided error in error level, all args are used for formatting.
+// Nil error is handled according to [Config.NilErrors].
+func (l Logger) ErrfOnly(err error, format string, args ...any) {
+	l.logfOnly(l.setError(l.setErrorCaller(l.newEvent(l.errLevel(err))), err), format, args)
+}
+
+// logfOnly formats a message using all args, unlike logf it doesn't guess which args are fields.
+func (l Logger) logfOnly(ev *zerolog.Event, format string, args []any) {
+	if ev == nil {
+		// Level is disabled, there is nothing to format
+		return
+	}
+	if l.ignore.match(format) {
+		return
+	}
+	// Fields of the logger group are still added
+	ev = l.addFields(ev, nil)
+	if strings.IndexByte(format, '%') < 0 {
+		ev.Msg(format)
+		return
+	}
+	if _, hasWrap := formatArgs(format); hasWrap {
+		format = replaceWrapVerbs(format)
+	}
+	ev.Msgf(format, args...)
+}
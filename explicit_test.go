@@ -0,0 +1,63 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestExplicitFields(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+
+	logger.Infow("done %d", "count", 5)
+
+	output := b.String()
+	if !strings.Contains(output, `"message":"done %d"`) {
+		t.Errorf("expected message not to be formatted, got %s", output)
+	}
+	if !strings.Contains(output, `"count":5`) {
+		t.Errorf("expected %s, got %s", `"count":5`, output)
+	}
+}
+
+func TestFormatOnly(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+
+	logger.InfofOnly("user %s", "bob", "extra", 1)
+
+	output := b.String()
+	if !strings.Contains(output, `"message":"user bob%!(EXTRA string=extra, int=1)"`) {
+		t.Errorf("expected all args to be formatted, got %s", output)
+	}
+	if strings.Contains(output, `"extra":1`) {
+		t.Errorf("expected no fields, got %s", output)
+	}
+
+	b.Reset()
+	logger.WarnfOnly("failed: %w", errors.New("boom"))
+	if output := b.String(); !strings.Contains(output, `"message":"failed: boom"`) {
+		t.Errorf("expected %s, got %s", `"message":"failed: boom"`, output)
+	}
+	if output := b.String(); strings.Contains(output, `"error"`) {
+		t.Errorf("expected no error field, got %s", output)
+	}
+
+	b.Reset()
+	logger.ErrfOnly(errors.New("boom"), "attempt %d", 3)
+	output = b.String()
+	if !strings.Contains(output, `"message":"attempt 3"`) || !strings.Contains(output, `"error":"boom"`) {
+		t.Errorf("expected formatted message with error, got %s", output)
+	}
+
+	b.Reset()
+	logger = logger.WithLevel(logze.LevelError)
+	logger.DebugfOnly("skipped %d", 1)
+	if b.Len() != 0 {
+		t.Errorf("expected empty output, got %s", b.String())
+	}
+}
@@ -0,0 +1,144 @@
+package logze
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultFailoverProbeInterval is a default min time between attempts to write to a failed primary writer.
+const DefaultFailoverProbeInterval = 5 * time.Second
+
+// FailoverOptions is using for configuring [NewFailoverWriter].
+type FailoverOptions struct {
+	// ProbeInterval is a min time between attempts to return to a failed primary writer.
+	// Default value is 5s.
+	ProbeInterval time.Duration
+
+	// Probe checks that a failed primary writer is healthy again (e.g. dials a collector). If it returns nil,
+	// the next entry is written to the primary writer. Default value is nil, in that case
+	// the next entry is written to the primary writer as a probe.
+	Probe func() error
+
+	// OnFailover is called when the writer switches to the secondary writer with an error of the primary one
+	// and when it switches back to the primary writer with nil error.
+	// Default value is a function that writes a notice entry to the writer that is used after switching.
+	OnFailover func(err error)
+}
+
+// FailoverWriter is an [io.Writer] that writes to a primary writer (e.g. TCP collector) and transparently switches
+// to a secondary writer (e.g. local file) when the primary one fails. It switches back after a healthy probe,
+// see [FailoverOptions]. It is safe for concurrent use. Use [NewFailoverWriter] to create it.
+type FailoverWriter struct {
+	primary   io.Writer
+	secondary io.Writer
+	opts      FailoverOptions
+
+	mu        sync.Mutex
+	failed    bool
+	lastProbe time.Time
+}
+
+// NewFailoverWriter returns [FailoverWriter] with provided primary and secondary writers.
+func NewFailoverWriter(primary, secondary io.Writer, opts FailoverOptions) *FailoverWriter {
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = DefaultFailoverProbeInterval
+	}
+	w := &FailoverWriter{
+		primary:   primary,
+		secondary: secondary,
+		opts:      opts,
+	}
+	if w.opts.OnFailover == nil {
+		w.opts.OnFailover = w.notice
+	}
+	return w
+}
+
+// Write writes p to the primary writer or to the secondary one if the primary writer has failed.
+func (w *FailoverWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed {
+		if time.Since(w.lastProbe) < w.opts.ProbeInterval {
+			return w.secondary.Write(p)
+		}
+		w.lastProbe = time.Now()
+		if w.opts.Probe != nil {
+			if err := w.opts.Probe(); err != nil {
+				return w.secondary.Write(p)
+			}
+		}
+		if _, err := w.primary.Write(p); err != nil {
+			return w.secondary.Write(p)
+		}
+		w.failed = false
+		w.opts.OnFailover(nil)
+		return len(p), nil
+	}
+
+	n, err := w.primary.Write(p)
+	if err == nil {
+		return n, nil
+	}
+	w.failed = true
+	w.lastProbe = time.Now()
+	w.opts.OnFailover(err)
+	return w.secondary.Write(p)
+}
+
+// Failed returns true if the primary writer has failed and entries are written to the secondary one.
+func (w *FailoverWriter) Failed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.failed
+}
+
+// Flush flushes both writers if they implement Flush() error.
+func (w *FailoverWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	for _, dst := range []io.Writer{w.primary, w.secondary} {
+		if f, ok := dst.(interface{ Flush() error }); ok {
+			errs = append(errs, f.Flush())
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// Close closes both writers if they implement [io.Closer], except [os.Stdout] and [os.Stderr].
+func (w *FailoverWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return joinErrors(closeWriter("primary", w.primary), closeWriter("secondary", w.secondary))
+}
+
+func closeWriter(name string, w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("%s writer: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// notice writes an entry about switching between writers, it is called with the lock held.
+func (w *FailoverWriter) notice(err error) {
+	if err != nil {
+		l := zerolog.New(w.secondary).With().Timestamp().Logger()
+		l.Warn().Err(err).Msg("logze: primary writer failed, switched to secondary")
+		return
+	}
+	l := zerolog.New(w.primary).With().Timestamp().Logger()
+	l.Info().Msg("logze: primary writer recovered, switched back from secondary")
+}
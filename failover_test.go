@@ -0,0 +1,95 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+type flakyWriter struct {
+	buf  bytes.Buffer
+	fail atomic.Bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail.Load() {
+		return 0, errors.New("connection refused")
+	}
+	return w.buf.Write(p)
+}
+
+func TestFailoverWriter(t *testing.T) {
+	var primary flakyWriter
+	var secondary bytes.Buffer
+	w := logze.NewFailoverWriter(&primary, &secondary, logze.FailoverOptions{ProbeInterval: time.Millisecond})
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Info("first")
+	primary.fail.Store(true)
+	logger.Info("second")
+	if !w.Failed() {
+		t.Errorf("expected writer to fail over")
+	}
+	logger.Info("third")
+
+	if out := primary.buf.String(); !strings.Contains(out, "first") || strings.Contains(out, "second") {
+		t.Errorf("expected only first message in primary, got %s", out)
+	}
+	out := secondary.String()
+	if !strings.Contains(out, "primary writer failed") || !strings.Contains(out, "connection refused") {
+		t.Errorf("expected failover notice, got %s", out)
+	}
+	if !strings.Contains(out, "second") || !strings.Contains(out, "third") {
+		t.Errorf("expected second and third messages in secondary, got %s", out)
+	}
+
+	primary.fail.Store(false)
+	time.Sleep(2 * time.Millisecond)
+	logger.Info("fourth")
+	if w.Failed() {
+		t.Errorf("expected writer to switch back")
+	}
+	out = primary.buf.String()
+	if !strings.Contains(out, "fourth") || !strings.Contains(out, "primary writer recovered") {
+		t.Errorf("expected fourth message and recovery notice in primary, got %s", out)
+	}
+}
+
+func TestFailoverWriterProbe(t *testing.T) {
+	var primary flakyWriter
+	var secondary bytes.Buffer
+	var events []error
+	healthy := false
+	w := logze.NewFailoverWriter(&primary, &secondary, logze.FailoverOptions{
+		ProbeInterval: time.Nanosecond,
+		Probe: func() error {
+			if !healthy {
+				return errors.New("unhealthy")
+			}
+			return nil
+		},
+		OnFailover: func(err error) { events = append(events, err) },
+	})
+
+	primary.fail.Store(true)
+	w.Write([]byte("a\n"))
+	primary.fail.Store(false)
+	w.Write([]byte("b\n"))
+	healthy = true
+	w.Write([]byte("c\n"))
+
+	if secondary.String() != "a\nb\n" {
+		t.Errorf("expected %q, got %q", "a\nb\n", secondary.String())
+	}
+	if primary.buf.String() != "c\n" {
+		t.Errorf("expected %q, got %q", "c\n", primary.buf.String())
+	}
+	if len(events) != 2 || events[0] == nil || events[1] != nil {
+		t.Errorf("expected failover and recovery events, got %v", events)
+	}
+}
@@ -0,0 +1,84 @@
+package logze
+
+import (
+	"sort"
+	"sync"
+)
+
+// Version is a version of logze.
+const Version = "2.1.0"
+
+// Enumerating names of features that can be reported by [Features].
+const (
+	FeatureDiode          = "diode"
+	FeatureConsole        = "console"
+	FeatureConfigFile     = "config-file"
+	FeatureConfigWatch    = "config-watch"
+	FeatureLevelHandler   = "level-handler"
+	FeatureNamed          = "named-loggers"
+	FeatureEntryHooks     = "entry-hooks"
+	FeatureZstd           = "zstd"
+	FeatureGzip           = "gzip"
+	FeatureJournald       = "journald"
+	FeatureLoki           = "loki"
+	FeatureElastic        = "elastic"
+	FeatureKafka          = "kafka"
+	FeatureOTel           = "otel"
+	FeatureSentry         = "sentry"
+	FeatureAlert          = "alert"
+	FeatureWebhook        = "webhook"
+	FeatureAppInsights    = "appinsights"
+	FeatureNATS           = "nats"
+	FeatureRedis          = "redis"
+	FeatureHTTPMiddleware = "http-middleware"
+	FeatureGRPCMiddleware = "grpc-middleware"
+	FeatureGinMiddleware  = "gin-middleware"
+	FeatureEchoMiddleware = "echo-middleware"
+	FeatureGORMLogger     = "gorm-logger"
+	FeatureZapCompat      = "zap-compat"
+	FeatureLogrusHook     = "logrus-hook"
+)
+
+var features = struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+}{
+	names: map[string]struct{}{
+		FeatureConsole:      {},
+		FeatureConfigFile:   {},
+		FeatureConfigWatch:  {},
+		FeatureLevelHandler: {},
+		FeatureNamed:        {},
+		FeatureEntryHooks:   {},
+	},
+}
+
+// Features returns a sorted list of features that are compiled in a binary. It includes features of the core
+// package and features of optional subpackages (e.g. [FeatureZstd] for zstdlog) that are imported,
+// so frameworks embedding logze can adapt at runtime and diagnostics can report the exact capability set.
+func Features() []string {
+	features.mu.RLock()
+	defer features.mu.RUnlock()
+	out := make([]string, 0, len(features.names))
+	for name := range features.names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// HasFeature returns true if a feature with provided name is compiled in a binary.
+func HasFeature(name string) bool {
+	features.mu.RLock()
+	defer features.mu.RUnlock()
+	_, ok := features.names[name]
+	return ok
+}
+
+// RegisterFeature adds a feature to the list returned by [Features].
+// It should be called in init function of an optional subpackage.
+func RegisterFeature(name string) {
+	features.mu.Lock()
+	defer features.mu.Unlock()
+	features.names[name] = struct{}{}
+}
@@ -0,0 +1,26 @@
+package logze_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestFeatures(t *testing.T) {
+	features := logze.Features()
+	if !sort.StringsAreSorted(features) {
+		t.Errorf("expected sorted features, got %v", features)
+	}
+	if !logze.HasFeature(logze.FeatureDiode) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureDiode, features)
+	}
+	if logze.HasFeature("test-feature") {
+		t.Error("expected no test-feature")
+	}
+
+	logze.RegisterFeature("test-feature")
+	if !logze.HasFeature("test-feature") {
+		t.Errorf("expected test-feature, got %v", logze.Features())
+	}
+}
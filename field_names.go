@@ -0,0 +1,160 @@
+package logze
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// FieldNames is a set of names of standard fields of an entry, see [Config.WithFieldNames].
+// Empty name means that a default name of zerolog is used.
+type FieldNames struct {
+	Message string `yaml:"message" json:"message"`
+	Level   string `yaml:"level" json:"level"`
+	Time    string `yaml:"time" json:"time"`
+	Error   string `yaml:"error" json:"error"`
+	Caller  string `yaml:"caller" json:"caller"`
+	Stack   string `yaml:"stack" json:"stack"`
+}
+
+// WithFieldNames returns [Config] with names of standard fields, e.g. "msg", "ts" and "severity"
+// to match an existing ingestion schema. Names are applied only to JSON writers of the logger
+// without changing global variables of zerolog, so loggers with different names can be used together.
+// Console writers keep default names, because they render these fields in their own way,
+// and so do syslog writers, because they map levels to severities, logfmt and CEF writers and [EntryParser] writers.
+func (c Config) WithFieldNames(names FieldNames) Config {
+	c.FieldNames = names
+	return c
+}
+
+// renames returns a map of default zerolog names to custom ones, it is nil if there is nothing to rename.
+func (n FieldNames) renames() fieldRenames {
+	var out fieldRenames
+	add := func(from, to string) {
+		if to == "" || to == from {
+			return
+		}
+		if out == nil {
+			out = make(map[string]string, 6)
+		}
+		out[from] = to
+	}
+	add(zerolog.MessageFieldName, n.Message)
+	add(zerolog.LevelFieldName, n.Level)
+	add(zerolog.TimestampFieldName, n.Time)
+	add(zerolog.ErrorFieldName, n.Error)
+	add(zerolog.CallerFieldName, n.Caller)
+	add(zerolog.ErrorStackFieldName, n.Stack)
+	return out
+}
+
+// withFieldNames returns writers where all JSON writers rename standard fields of entries.
+func withFieldNames(writers []io.Writer, names FieldNames) []io.Writer {
+	renames := names.renames()
+	if len(renames) == 0 {
+		return writers
+	}
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		if hasOwnFormat(w) {
+			out[i] = w
+			continue
+		}
+		if cw, ok := w.(convertWriter); ok && cw.rewritesTime {
+			// Time is rewritten before renaming, because it is found by its default name
+			cw.w = convertWriter{w: cw.w, convert: renames.rename}
+			out[i] = cw
+			continue
+		}
+		out[i] = convertWriter{w: w, convert: renames.rename}
+	}
+	return out
+}
+
+// EntryParser is implemented by writers that parse entries of the logger (e.g. with [ParseEntry]) to convert them
+// to another representation, such writers get entries with standard field names regardless of
+// [Config.WithFieldNames], [Config.WithECS], [Config.WithGCP] and [Config.WithDatadog].
+type EntryParser interface {
+	io.Writer
+	// ParsesEntries is a marker method, it is never called.
+	ParsesEntries()
+}
+
+// hasOwnFormat returns true for writers that render entries in their own format instead of JSON,
+// so standard fields of entries are not renamed or converted for them.
+func hasOwnFormat(w io.Writer) bool {
+	switch w.(type) {
+	case zerolog.ConsoleWriter, *zerolog.ConsoleWriter, *SyslogWriter, *LogfmtWriter, *CEFWriter, EntryParser:
+		return true
+	}
+	return false
+}
+
+// convertWriter converts every entry of the logger before writing it to the underlying writer,
+// it is used for field names, time formats and output modes.
+type convertWriter struct {
+	w       io.Writer
+	convert func(p []byte) []byte
+	// rewritesTime is true for writers that rewrite time field, see [NewTimeFormatWriter].
+	rewritesTime bool
+}
+
+func (w convertWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.convert(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w convertWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	if _, err := lw.WriteLevel(level, w.convert(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// fieldRenames maps default names of standard fields to new ones.
+type fieldRenames map[string]string
+
+// rename renames top level fields of a JSON entry.
+func (r fieldRenames) rename(p []byte) []byte {
+	type replacement struct {
+		start, end int
+		name       string
+	}
+	var replacements []replacement
+	ok := scanTopLevel(p, func(f topLevelField) bool {
+		// Default names don't need escaping, so raw keys are compared
+		if name, ok := r[string(f.key)]; ok {
+			replacements = append(replacements, replacement{start: f.keyStart, end: f.keyEnd, name: name})
+		}
+		return true
+	})
+	if !ok {
+		// Not a JSON entry, e.g. raw bytes written with Logger.Write
+		return p
+	}
+	if len(replacements) == 0 {
+		return p
+	}
+
+	out := make([]byte, 0, len(p)+16)
+	prev := 0
+	for _, r := range replacements {
+		out = append(out, p[prev:r.start]...)
+		out = appendQuoted(out, r.name)
+		prev = r.end
+	}
+	return append(out, p[prev:]...)
+}
+
+func appendQuoted(dst []byte, s string) []byte {
+	data, _ := json.Marshal(s)
+	return append(dst, data...)
+}
@@ -0,0 +1,55 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestFieldNames(t *testing.T) {
+	var b, other bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithFieldNames(logze.FieldNames{
+		Message: "msg",
+		Level:   "severity",
+		Time:    "ts",
+		Error:   "err",
+	}))
+	otherLogger := logze.New(logze.NewConfig(&other).WithNoDiode())
+
+	logger.Err(errors.New("some error"), "cannot handle", "nested", map[string]any{"message": "inner"},
+		"tricky", `a "quoted" } ] { "level":`, "list", []any{map[string]any{"level": "inner"}, 1.5, true, nil})
+	otherLogger.Info("default names")
+
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for key, expected := range map[string]any{"msg": "cannot handle", "severity": "error", "err": "some error"} {
+		if entry[key] != expected {
+			t.Errorf("expected %s=%v, got %v", key, expected, entry[key])
+		}
+	}
+	if _, ok := entry["ts"]; !ok {
+		t.Errorf("expected ts field, got %s", b.String())
+	}
+	for _, key := range []string{"message", "level", "time", "error"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("expected no %s field, got %s", key, b.String())
+		}
+	}
+	if nested, _ := entry["nested"].(map[string]any); nested["message"] != "inner" {
+		t.Errorf("expected nested fields not to be renamed, got %s", b.String())
+	}
+
+	if entry["tricky"] != `a "quoted" } ] { "level":` || !strings.Contains(b.String(), `"list":[{"level":"inner"},1.5,true,null]`) {
+		t.Errorf("expected values not to be changed, got %s", b.String())
+	}
+
+	if !strings.Contains(other.String(), `"message":"default names"`) {
+		t.Errorf("expected default names in other logger, got %s", other.String())
+	}
+}
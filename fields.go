@@ -0,0 +1,60 @@
+package logze
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxSimpleFields is a max length of fields of a message (keys and values) that are added with a fast path.
+const maxSimpleFields = 8
+
+// appendSimpleFields adds fields to the event using typed methods of [zerolog.Event] if all keys are strings
+// and all values have simple types. It is a fast path for the common case of a few fields: it skips expanding,
+// resolving of lazy values and a generic type switch of [zerolog.Event.Fields]. It returns false without
+// changing the event if the fields need generic processing (e.g. an error, a dict, a struct or a lazy value).
+func appendSimpleFields(ev *zerolog.Event, fields []any) (*zerolog.Event, bool) {
+	if len(fields) > maxSimpleFields || len(fields)%2 != 0 {
+		return ev, false
+	}
+	for i := 0; i < len(fields); i += 2 {
+		if _, ok := fields[i].(string); !ok {
+			return ev, false
+		}
+		switch fields[i+1].(type) {
+		case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64, time.Duration, time.Time:
+		default:
+			return ev, false
+		}
+	}
+	for i := 0; i < len(fields); i += 2 {
+		key := fields[i].(string)
+		switch v := fields[i+1].(type) {
+		case string:
+			ev = ev.Str(key, v)
+		case bool:
+			ev = ev.Bool(key, v)
+		case int:
+			ev = ev.Int(key, v)
+		case int32:
+			ev = ev.Int32(key, v)
+		case int64:
+			ev = ev.Int64(key, v)
+		case uint:
+			ev = ev.Uint(key, v)
+		case uint32:
+			ev = ev.Uint32(key, v)
+		case uint64:
+			ev = ev.Uint64(key, v)
+		case float32:
+			ev = ev.Float32(key, v)
+		case float64:
+			ev = ev.Float64(key, v)
+		case time.Duration:
+			ev = ev.Dur(key, v)
+		case time.Time:
+			ev = ev.Time(key, v)
+		}
+	}
+	return ev, true
+}
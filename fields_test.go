@@ -0,0 +1,62 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestSimpleFields(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().WithNoTimestamp())
+
+	logger.Info("message", "str", "value", "int", 123, "dur", 1500*time.Millisecond, "ok", true)
+	logger.Info("message", "u", uint64(7), "f", 1.5, "i32", int32(-3), "f32", float32(2.5))
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.Infof("%s message", "formatted", "time", ts)
+
+	expected := []string{
+		`{"level":"info","str":"value","int":123,"dur":1500,"ok":true,"message":"message"}`,
+		`{"level":"info","u":7,"f":1.5,"i32":-3,"f32":2.5,"message":"message"}`,
+		`{"level":"info","time":"2024-01-02T03:04:05Z","message":"formatted message"}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
+
+func TestSimpleFieldsFallback(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().WithNoTimestamp())
+
+	// Odd number of fields, non-string key and too many fields are handled in a generic way
+	logger.Info("message", "a", 1, "b")
+	logger.Info("message", 1, 2, "c", 3)
+	logger.Info("message", "k1", 1, "k2", 2, "k3", 3, "k4", 4, "k5", 5)
+	logger.WithGroup("g").Info("message", "k", "v")
+
+	expected := []string{
+		`{"level":"info","a":1,"message":"message"}`,
+		`{"level":"info","c":3,"message":"message"}`,
+		`{"level":"info","k1":1,"k2":2,"k3":3,"k4":4,"k5":5,"message":"message"}`,
+		`{"level":"info","g":{"k":"v"},"message":"message"}`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %s", len(expected), len(lines), b.String())
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], line)
+		}
+	}
+}
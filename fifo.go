@@ -0,0 +1,158 @@
+package logze
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultFIFOWriteTimeout is a default timeout of writing an entry by [FIFOWriter].
+	DefaultFIFOWriteTimeout = 5 * time.Second
+	// DefaultFIFOReopenInterval is a default min time between attempts to open a named pipe by [FIFOWriter].
+	DefaultFIFOReopenInterval = time.Second
+)
+
+// FIFOOptions is using for configuring [NewFIFOWriter].
+type FIFOOptions struct {
+	// WriteTimeout is a timeout of writing an entry, so a stuck reader doesn't block logging calls
+	// when a pipe is full. Default value is 5s.
+	WriteTimeout time.Duration
+
+	// ReopenInterval is a min time between attempts to open a pipe when there is no reader,
+	// entries are dropped without waiting until the next attempt. Default value is 1s.
+	ReopenInterval time.Duration
+
+	// OnDrop is called with an entry that is dropped and the reason, e.g. to write it to a local file.
+	// The entry must not be retained after the call. Default value is nil.
+	OnDrop func(p []byte, err error)
+}
+
+// FIFOWriter is an [io.Writer] that writes entries to a named pipe (FIFO) read by a local collector.
+// It opens the pipe with the first entry without blocking, entries are dropped while there is no reader.
+// When the reader goes away, writing fails with EPIPE and the pipe is opened again, so a restarted collector
+// gets entries without restarting an application. Dropped entries are handled like in [NetWriter].
+// Named pipes are supported only on unix systems. It is safe for concurrent use. Use [NewFIFOWriter] to create it.
+type FIFOWriter struct {
+	path string
+	opts FIFOOptions
+
+	mu       sync.Mutex
+	f        *os.File
+	lastOpen time.Time
+	openErr  error
+	closed   bool
+
+	dropped atomic.Int64
+}
+
+// NewFIFOWriter returns [FIFOWriter] writing entries to a named pipe at provided path, e.g. created by mkfifo.
+// The pipe is opened with the first entry.
+func NewFIFOWriter(path string, opts FIFOOptions) *FIFOWriter {
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DefaultFIFOWriteTimeout
+	}
+	if opts.ReopenInterval <= 0 {
+		opts.ReopenInterval = DefaultFIFOReopenInterval
+	}
+	return &FIFOWriter{
+		path: path,
+		opts: opts,
+	}
+}
+
+// Write writes an entry to the pipe. It returns [os.ErrClosed] after [FIFOWriter.Close].
+func (w *FIFOWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	n, err := w.write(p)
+	if err != nil {
+		w.dropped.Add(1)
+		if w.opts.OnDrop != nil {
+			w.opts.OnDrop(p, err)
+		}
+		return 0, err
+	}
+	return n, nil
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *FIFOWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Name returns a name of the writer for errors of [Logger.Close].
+func (w *FIFOWriter) Name() string {
+	return "fifo://" + w.path
+}
+
+// Close closes the pipe.
+func (w *FIFOWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// write writes an entry opening the pipe if it is not opened, it is called with the lock held.
+func (w *FIFOWriter) write(p []byte) (int, error) {
+	if w.f != nil {
+		n, err := w.writeFile(p)
+		if err == nil {
+			return n, nil
+		}
+		// Reader may be restarted (EPIPE), so the entry is written again to the pipe opened by a new reader
+		_ = w.f.Close()
+		w.f = nil
+		w.lastOpen = time.Time{}
+	}
+	if err := w.open(); err != nil {
+		return 0, err
+	}
+	n, err := w.writeFile(p)
+	if err != nil {
+		_ = w.f.Close()
+		w.f = nil
+		// Entries are dropped until the next attempt to open
+		w.openErr = err
+		return 0, err
+	}
+	return n, nil
+}
+
+func (w *FIFOWriter) writeFile(p []byte) (int, error) {
+	if err := w.f.SetWriteDeadline(time.Now().Add(w.opts.WriteTimeout)); err != nil && !errors.Is(err, os.ErrNoDeadline) {
+		return 0, err
+	}
+	return w.f.Write(p)
+}
+
+// open opens the pipe if the previous attempt is older than the reopen interval,
+// otherwise it returns the error of the previous attempt.
+func (w *FIFOWriter) open() error {
+	if time.Since(w.lastOpen) < w.opts.ReopenInterval {
+		return w.openErr
+	}
+	w.lastOpen = time.Now()
+
+	f, err := openFIFO(w.path)
+	if err != nil {
+		w.openErr = err
+		return err
+	}
+	w.f = f
+	w.openErr = nil
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package logze
+
+import (
+	"errors"
+	"os"
+)
+
+func openFIFO(string) (*os.File, error) {
+	return nil, errors.New("open fifo: named pipes are not supported")
+}
@@ -0,0 +1,124 @@
+//go:build unix
+
+package logze_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func openReader(t *testing.T, path string) *os.File {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return f
+}
+
+func read(t *testing.T, f *os.File) string {
+	t.Helper()
+	if err := f.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := f.Read(buf)
+	if err != nil {
+		t.Fatalf("expected data, got %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestFIFOWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.fifo")
+	if err := syscall.Mkfifo(path, 0o600); err != nil {
+		t.Skipf("named pipes are not supported: %v", err)
+	}
+
+	var dropped []string
+	w := logze.NewFIFOWriter(path, logze.FIFOOptions{
+		ReopenInterval: time.Millisecond,
+		OnDrop: func(p []byte, err error) {
+			dropped = append(dropped, string(p))
+		},
+	})
+	if _, err := w.Write([]byte("no reader\n")); err == nil || !strings.Contains(err.Error(), "no reader") {
+		t.Errorf("expected error without reader, got %v", err)
+	}
+
+	r := openReader(t, path)
+	time.Sleep(5 * time.Millisecond)
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := read(t, r); got != "first\n" {
+		t.Errorf("expected first, got %q", got)
+	}
+
+	// Reader is restarted, writing to the old pipe fails with EPIPE and the pipe is opened again
+	r.Close()
+	r = openReader(t, path)
+	defer r.Close()
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("expected entry written after reopening, got %v", err)
+	}
+	if got := read(t, r); got != "second\n" {
+		t.Errorf("expected second, got %q", got)
+	}
+
+	if len(dropped) != 1 || w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %q", dropped)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("closed\n")); err != os.ErrClosed {
+		t.Errorf("expected os.ErrClosed, got %v", err)
+	}
+}
+
+func TestFIFOWriterNotPipe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w := logze.NewFIFOWriter(path, logze.FIFOOptions{})
+	if _, err := w.Write([]byte("entry\n")); err == nil || !strings.Contains(err.Error(), "not a named pipe") {
+		t.Errorf("expected error for a regular file, got %v", err)
+	}
+}
+
+func TestConfigFileLocalCollectors(t *testing.T) {
+	dir := t.TempDir()
+	fifo := filepath.Join(dir, "collector.fifo")
+	if err := syscall.Mkfifo(fifo, 0o600); err != nil {
+		t.Skipf("named pipes are not supported: %v", err)
+	}
+	sock := filepath.Join(dir, "collector.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+	defer ln.Close()
+
+	cfg, err := logze.ParseConfig([]byte("writers: [" + fifo + ", " + sock + ", unix:///run/missing.sock, fifo:///run/missing.fifo]\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Writers) != 4 {
+		t.Fatalf("expected 4 writers, got %d", len(cfg.Writers))
+	}
+	for i, want := range []string{"fifo://" + fifo, "unix://" + sock, "unix:///run/missing.sock", "fifo:///run/missing.fifo"} {
+		named, ok := cfg.Writers[i].(interface{ Name() string })
+		if !ok || named.Name() != want {
+			t.Errorf("expected writer %s, got %T", want, cfg.Writers[i])
+		}
+	}
+}
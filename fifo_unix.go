@@ -0,0 +1,29 @@
+//go:build unix
+
+package logze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// openFIFO opens a named pipe for writing without blocking until a reader opens it.
+func openFIFO(path string) (*os.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("open fifo: %w", err)
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return nil, fmt.Errorf("open fifo: %s is not a named pipe", path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			return nil, fmt.Errorf("open fifo: no reader of %s", path)
+		}
+		return nil, fmt.Errorf("open fifo: %w", err)
+	}
+	return f, nil
+}
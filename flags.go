@@ -0,0 +1,94 @@
+package logze
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Enumerating supported output formats for -log-format flag.
+const (
+	FormatJSON           = "json"
+	FormatConsole        = "console"
+	FormatConsoleNoColor = "console-nocolor"
+	FormatLogfmt         = "logfmt"
+)
+
+// Formats is a list of all supported output formats.
+var Formats = []string{
+	FormatJSON, FormatConsole, FormatConsoleNoColor, FormatLogfmt,
+}
+
+type flagValues struct {
+	format string
+	file   string
+}
+
+// RegisterFlags registers -log-level, -log-format, -log-file and -log-stack flags in the provided [flag.FlagSet]
+// ([flag.CommandLine] is used if it is nil). Current values of [Config] are used as defaults.
+// Call [Config.Build] after parsing flags to get a [Logger]:
+//
+//	cfg := logze.NewConfig()
+//	cfg.RegisterFlags(flag.CommandLine)
+//	flag.Parse()
+//	logger, err := cfg.Build()
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	if c.Level == "" {
+		c.Level = LevelInfo
+	}
+	c.flags = &flagValues{}
+
+	fs.StringVar(&c.Level, "log-level", c.Level, "log level, one of: "+strings.Join(Levels, ", "))
+	fs.StringVar(&c.flags.format, "log-format", FormatJSON, "log format, one of: "+strings.Join(Formats, ", "))
+	fs.StringVar(&c.flags.file, "log-file", "", "path to a log file, logs are written to stderr if empty")
+	fs.BoolVar(&c.StackTrace, "log-stack", c.StackTrace, "enable stack trace for errors")
+}
+
+// Build returns a new [Logger] with provided fields using values of flags registered with [Config.RegisterFlags].
+// Flags output is added to the list of writers of [Config]. It returns an error if flags have invalid values
+// or [Config.Validate] fails.
+func (c Config) Build(fields ...any) (Logger, error) {
+	if c.flags == nil {
+		if err := c.Validate(); err != nil {
+			return Logger{}, err
+		}
+		return New(c, fields...), nil
+	}
+
+	var out io.Writer = os.Stderr
+	if c.flags.file != "" {
+		w, err := openWriter(c.flags.file)
+		if err != nil {
+			return Logger{}, err
+		}
+		out = w
+	}
+	fail := func(err error) (Logger, error) {
+		// Only the file of flags is opened here, other writers of the config belong to the caller
+		_ = closeWriters(trackWriters([]io.Writer{out}))
+		return Logger{}, err
+	}
+
+	switch strings.ToLower(c.flags.format) {
+	case FormatJSON, "":
+		c = c.WithWriter(out)
+	case FormatConsole:
+		c = c.WithWriter(getConsoleWriter(out, true))
+	case FormatConsoleNoColor:
+		c = c.WithWriter(getConsoleWriter(out, false))
+	case FormatLogfmt:
+		c = c.WithWriter(NewLogfmtWriter(out))
+	default:
+		return fail(fmt.Errorf("invalid log format %q", c.flags.format))
+	}
+
+	if err := c.Validate(); err != nil {
+		return fail(err)
+	}
+	return New(c, fields...), nil
+}
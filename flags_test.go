@@ -0,0 +1,106 @@
+package logze_test
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg := logze.NewConfig().WithNoDiode()
+	cfg.RegisterFlags(fs)
+
+	err := fs.Parse([]string{"-log-level", "debug", "-log-file", logPath, "-log-stack"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Level != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, cfg.Level)
+	}
+	if !cfg.StackTrace {
+		t.Errorf("expected StackTrace to be true, got false")
+	}
+
+	logger, err := cfg.Build("foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Debug("debug message")
+
+	output, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "debug message") || !strings.Contains(string(output), "foo\":\"bar") {
+		t.Errorf("expected log in file, got %s", output)
+	}
+}
+
+func TestRegisterFlagsDefaults(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg := logze.NewConfig().WithLevel(logze.LevelWarn)
+	cfg.RegisterFlags(fs)
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Level != logze.LevelWarn {
+		t.Errorf("expected %s, got %s", logze.LevelWarn, cfg.Level)
+	}
+	if _, err := cfg.Build(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRegisterFlagsInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-log-level", "verbose"},
+		{"-log-format", "xml"},
+		{"-log-file", filepath.Join(t.TempDir(), "missing", "app.log")},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		cfg := logze.NewConfig()
+		cfg.RegisterFlags(fs)
+
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := cfg.Build(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestRegisterFlagsInvalidClosesFile(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("open files are not listed in /proc")
+	}
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	for _, args := range [][]string{
+		{"-log-file", logPath, "-log-format", "xml"},
+		{"-log-file", logPath, "-log-level", "verbose"},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		cfg := logze.NewConfig()
+		cfg.RegisterFlags(fs)
+
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := cfg.Build(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+		if isFileOpen(t, logPath) {
+			t.Errorf("expected closed log file for %v", args)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package logze
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formatMessage formats a message like [Logger.Infof] does: args consumed by verbs of the format are used
+// for formatting, other args are fields and are skipped.
+func formatMessage(format string, args []any) string {
+	if strings.IndexByte(format, '%') < 0 {
+		return format
+	}
+	n, hasWrap := formatArgs(format)
+	if n < len(args) {
+		args = args[:n]
+	}
+	if hasWrap {
+		format = replaceWrapVerbs(format)
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// formatArgs scans a format string like fmt does and returns a number of arguments consumed by its verbs,
+// including arguments of '*' width and precision and explicit indexes like %[2]d. Escaped %% doesn't
+// consume an argument. It also returns true if there is a %w verb, that is not supported by [fmt.Sprintf].
+func formatArgs(format string) (n int, hasWrap bool) {
+	argNum := 0
+	for i := 0; i < len(format); i++ {
+		next := strings.IndexByte(format[i:], '%')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+		// Flags
+		for i < len(format) && isFlag(format[i]) {
+			i++
+		}
+		argNum, i = formatIndex(format, i, argNum)
+		// Width
+		if i < len(format) && format[i] == '*' {
+			argNum++
+			i++
+		} else {
+			for i < len(format) && isDigit(format[i]) {
+				i++
+			}
+		}
+		// Precision
+		if i < len(format) && format[i] == '.' {
+			i++
+			argNum, i = formatIndex(format, i, argNum)
+			if i < len(format) && format[i] == '*' {
+				argNum++
+				i++
+			} else {
+				for i < len(format) && isDigit(format[i]) {
+					i++
+				}
+			}
+		}
+		argNum, i = formatIndex(format, i, argNum)
+		if i >= len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+		if format[i] == 'w' {
+			hasWrap = true
+		}
+		argNum++
+		if argNum > n {
+			n = argNum
+		}
+	}
+	return n, hasWrap
+}
+
+// formatIndex parses an explicit argument index like [2] at position i and returns an index of the next argument
+// (zero-based) and a position after the index. Invalid index is skipped.
+func formatIndex(format string, i, argNum int) (int, int) {
+	if i >= len(format) || format[i] != '[' {
+		return argNum, i
+	}
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return argNum, i
+	}
+	index := 0
+	for _, c := range []byte(format[i+1 : i+end]) {
+		if !isDigit(c) {
+			return argNum, i + end + 1
+		}
+		index = index*10 + int(c-'0')
+	}
+	if index > 0 {
+		argNum = index - 1
+	}
+	return argNum, i + end + 1
+}
+
+// replaceWrapVerbs replaces %w verbs with %v, so errors are formatted by [fmt.Sprintf] like [fmt.Errorf] does.
+func replaceWrapVerbs(format string) string {
+	out := []byte(format)
+	for i := 0; i < len(out); i++ {
+		if out[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(out) && !isVerb(out[j]) {
+			j++
+		}
+		if j < len(out) && out[j] == 'w' {
+			out[j] = 'v'
+		}
+		i = j
+	}
+	return string(out)
+}
+
+// isVerb returns true for letters and '%' that finish a directive of a format string.
+func isVerb(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '%'
+}
+
+func isFlag(c byte) bool {
+	return c == '+' || c == '-' || c == '#' || c == ' ' || c == '0'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,49 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	tests := []struct {
+		name   string
+		format string
+		args   []any
+		msg    string
+		fields map[string]any
+	}{
+		{"escaped percent", "%d%% done", []any{50, "key", "value"}, "50% done", map[string]any{"key": "value"}},
+		{"only escaped percent", "100%% done", []any{"key", "value"}, "100% done", map[string]any{"key": "value"}},
+		{"width and precision", "%*.*f", []any{6, 2, 3.14159, "key", "value"}, "  3.14", map[string]any{"key": "value"}},
+		{"explicit index", "%[2]s %[1]s", []any{"world", "hello", "key", "value"}, "hello world", map[string]any{"key": "value"}},
+		{"wrap verb", "cannot open: %w", []any{errors.New("not found")}, "cannot open: not found", map[string]any{"error": "not found"}},
+		{"flags", "%+d %-4s|", []any{5, "ab", "key", "value"}, "+5 ab  |", map[string]any{"key": "value"}},
+		{"missing args", "%s %s", []any{"one"}, "one %!s(MISSING)", nil},
+		{"no verbs", "message", []any{"key", "value"}, "message", map[string]any{"key": "value"}},
+	}
+	for _, tt := range tests {
+		b.Reset()
+		logger.Infof(tt.format, tt.args...)
+
+		var entry map[string]any
+		if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if entry["message"] != tt.msg {
+			t.Errorf("%s: expected message %q, got %q", tt.name, tt.msg, entry["message"])
+		}
+		for k, v := range tt.fields {
+			if entry[k] != v {
+				t.Errorf("%s: expected %s=%v, got %v", tt.name, k, v, entry[k])
+			}
+		}
+	}
+}
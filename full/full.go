@@ -0,0 +1,25 @@
+// Package full provides the batteries-included experience of logze: it imports all optional subpackages,
+// so their features are registered and listed by [logze.Features].
+//
+// Core logze package doesn't depend on optional integrations to keep binaries small,
+// import this package for side effects if you need all of them:
+//
+//	import _ "github.com/maxbolgarin/logze/v2/full"
+package full
+
+import (
+	// Optional subpackages register their features in init functions
+	_ "github.com/maxbolgarin/logze/v2/alert"
+	_ "github.com/maxbolgarin/logze/v2/appinsights"
+	_ "github.com/maxbolgarin/logze/v2/elastic"
+	_ "github.com/maxbolgarin/logze/v2/gziplog"
+	_ "github.com/maxbolgarin/logze/v2/httpmw"
+	_ "github.com/maxbolgarin/logze/v2/journald"
+	_ "github.com/maxbolgarin/logze/v2/kafka"
+	_ "github.com/maxbolgarin/logze/v2/loki"
+	_ "github.com/maxbolgarin/logze/v2/natslog"
+	_ "github.com/maxbolgarin/logze/v2/redislog"
+	_ "github.com/maxbolgarin/logze/v2/sentrylog"
+	_ "github.com/maxbolgarin/logze/v2/webhook"
+	_ "github.com/maxbolgarin/logze/v2/zstdlog"
+)
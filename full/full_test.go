@@ -0,0 +1,16 @@
+package full_test
+
+import (
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	_ "github.com/maxbolgarin/logze/v2/full"
+)
+
+func TestFeatures(t *testing.T) {
+	for _, name := range []string{logze.FeatureZstd, logze.FeatureGzip, logze.FeatureJournald, logze.FeatureLoki, logze.FeatureElastic, logze.FeatureKafka, logze.FeatureSentry, logze.FeatureAlert, logze.FeatureWebhook, logze.FeatureAppInsights, logze.FeatureNATS, logze.FeatureRedis, logze.FeatureHTTPMiddleware, logze.FeatureDiode, logze.FeatureConsole} {
+		if !logze.HasFeature(name) {
+			t.Errorf("expected %s feature, got %v", name, logze.Features())
+		}
+	}
+}
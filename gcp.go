@@ -0,0 +1,312 @@
+package logze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Names of fields of Google Cloud Logging, see https://cloud.google.com/logging/docs/structured-logging.
+const (
+	GCPSeverityFieldName       = "severity"
+	GCPSourceLocationFieldName = "logging.googleapis.com/sourceLocation"
+	GCPTraceFieldName          = "logging.googleapis.com/trace"
+	GCPSpanIDFieldName         = "logging.googleapis.com/spanId"
+)
+
+// GCPErrorReportingType is a type of entries that are recognized by Google Error Reporting, see [Config.WithGCPErrorReporting].
+const GCPErrorReportingType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// GCPServiceContext is a service of errors in Google Error Reporting, see [Config.WithGCPErrorReporting].
+type GCPServiceContext struct {
+	// Service is a name of a service. Default value is K_SERVICE environment variable of Cloud Run.
+	Service string `yaml:"service" json:"service"`
+
+	// Version is a version of a service. Default value is K_REVISION environment variable of Cloud Run.
+	Version string `yaml:"version" json:"version"`
+}
+
+// Names of fields of entries with trace and span IDs, they become trace fields of Cloud Logging in [Config.WithGCP].
+var (
+	GCPTraceIDSourceName = "trace_id"
+	GCPSpanIDSourceName  = "span_id"
+)
+
+// WithGCP returns [Config] with JSON output in the format of Google Cloud Logging, so entries from GKE
+// and Cloud Run are parsed with correct severity and trace correlation: level becomes severity (WARNING, CRITICAL),
+// caller becomes logging.googleapis.com/sourceLocation, time is written in RFC3339 with nanoseconds
+// and trace_id with span_id fields become logging.googleapis.com/trace and logging.googleapis.com/spanId.
+//
+// Trace is written as projects/PROJECT_ID/traces/TRACE_ID using a project from GOOGLE_CLOUD_PROJECT
+// environment variable, use [Config.WithGCPProjectID] to set it explicitly. It replaces names set by
+// [Config.WithFieldNames] and disables [Config.WithECS] and [Config.WithDatadog].
+func (c Config) WithGCP() Config {
+	c.GCP = true
+	if c.GCPProjectID == "" {
+		c.GCPProjectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	c.ECS = false
+	c.Datadog = nil
+	c.FieldNames = FieldNames{}
+	c.TimeFieldFormat = time.RFC3339Nano
+	return c
+}
+
+// WithGCPProjectID returns [Config] with a project of traces in Google Cloud Logging output, see [Config.WithGCP].
+func (c Config) WithGCPProjectID(projectID string) Config {
+	c.GCPProjectID = projectID
+	return c
+}
+
+// WithGCPErrorReporting returns [Config] with output in the format of Google Cloud Logging (see [Config.WithGCP]),
+// where error, fatal and panic entries are recognized by Google Error Reporting and grouped into tracked errors:
+// they get @type field with [GCPErrorReportingType], context.reportLocation from a caller or the first frame
+// of a stack trace, serviceContext and stack_trace field with a stack trace in the format of Go panics.
+//
+// Caller is added to error, fatal and panic levels, because Error Reporting ignores entries without a location.
+// Use [Config.WithStackTrace] to group errors by their stack traces.
+func (c Config) WithGCPErrorReporting(service GCPServiceContext) Config {
+	if service.Service == "" {
+		service.Service = os.Getenv("K_SERVICE")
+	}
+	if service.Version == "" {
+		service.Version = os.Getenv("K_REVISION")
+	}
+	if !c.GCP {
+		c = c.WithGCP()
+	}
+	c.GCPErrorReporting = &service
+
+	levels := []string{LevelTrace}
+	if c.CallerLevels != nil {
+		levels = append([]string{}, c.CallerLevels...)
+	}
+	for _, level := range []string{LevelError, LevelFatal, zerolog.LevelPanicValue} {
+		found := false
+		for _, l := range levels {
+			found = found || l == level
+		}
+		if !found {
+			levels = append(levels, level)
+		}
+	}
+	c.CallerLevels = levels
+	return c
+}
+
+// withGCP returns writers where all JSON writers get entries in the format of Google Cloud Logging.
+// Error entries are formatted for Error Reporting if service is not nil.
+func withGCP(writers []io.Writer, projectID string, service *GCPServiceContext) []io.Writer {
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		if hasOwnFormat(w) {
+			out[i] = w
+			continue
+		}
+		out[i] = convertWriter{w: w, convert: gcpConverter{projectID: projectID, service: service}.convert}
+	}
+	return out
+}
+
+// gcpConverter converts standard fields of every JSON entry to fields of Google Cloud Logging.
+type gcpConverter struct {
+	projectID string
+	service   *GCPServiceContext
+}
+
+func (c gcpConverter) convert(p []byte) []byte {
+	fields, ok := splitFields(p)
+	if !ok {
+		return p
+	}
+	out := make([]byte, 0, len(p)+64)
+	out = append(out, '{')
+	add := func(key string, value []byte) {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		name, _ := json.Marshal(key)
+		out = append(out, name...)
+		out = append(out, ':')
+		out = append(out, value...)
+	}
+
+	var report *gcpErrorReport
+	for _, f := range fields {
+		switch f.key {
+		case zerolog.LevelFieldName:
+			var level string
+			_ = json.Unmarshal(f.value, &level)
+			severity, _ := json.Marshal(gcpSeverity(level))
+			add(GCPSeverityFieldName, severity)
+			if c.service != nil && (level == LevelError || level == LevelFatal || level == zerolog.LevelPanicValue) {
+				report = &gcpErrorReport{}
+			}
+
+		case zerolog.CallerFieldName:
+			var caller string
+			if err := json.Unmarshal(f.value, &caller); err != nil {
+				add(f.key, f.value)
+				continue
+			}
+			loc := map[string]string{"file": caller}
+			if i := strings.LastIndexByte(caller, ':'); i > 0 {
+				loc["file"], loc["line"] = caller[:i], caller[i+1:]
+			}
+			value, _ := json.Marshal(loc)
+			add(GCPSourceLocationFieldName, value)
+
+		case GCPTraceIDSourceName:
+			var trace string
+			if err := json.Unmarshal(f.value, &trace); err != nil || trace == "" {
+				add(f.key, f.value)
+				continue
+			}
+			if c.projectID != "" && !strings.HasPrefix(trace, "projects/") {
+				trace = "projects/" + c.projectID + "/traces/" + trace
+			}
+			value, _ := json.Marshal(trace)
+			add(GCPTraceFieldName, value)
+
+		case GCPSpanIDSourceName:
+			add(GCPSpanIDFieldName, f.value)
+
+		default:
+			add(f.key, f.value)
+		}
+	}
+	if report != nil {
+		report.collect(fields)
+		for _, f := range report.fields(c.service) {
+			add(f.key, f.value)
+		}
+	}
+	out = append(out, '}')
+	return append(out, '\n')
+}
+
+// gcpErrorReport is a location and a stack trace of an error entry for Error Reporting.
+type gcpErrorReport struct {
+	message  string
+	file     string
+	line     int
+	function string
+	frames   []map[string]any
+}
+
+// collect takes a message, a caller and a stack trace from fields of an entry.
+func (r *gcpErrorReport) collect(fields []jsonField) {
+	var message, errText string
+	for _, f := range fields {
+		switch f.key {
+		case zerolog.MessageFieldName:
+			_ = json.Unmarshal(f.value, &message)
+		case zerolog.ErrorFieldName:
+			_ = json.Unmarshal(f.value, &errText)
+		case zerolog.CallerFieldName:
+			var caller string
+			if err := json.Unmarshal(f.value, &caller); err != nil {
+				continue
+			}
+			r.file = caller
+			if i := strings.LastIndexByte(caller, ':'); i > 0 {
+				r.file = caller[:i]
+				r.line, _ = strconv.Atoi(caller[i+1:])
+			}
+		case zerolog.ErrorStackFieldName:
+			_ = json.Unmarshal(f.value, &r.frames)
+		}
+	}
+	r.message = message
+	if errText != "" {
+		if r.message != "" {
+			r.message += ": "
+		}
+		r.message += errText
+	}
+
+	// Caller has a full path to a file, frames of a stack trace are used for a function name
+	// or as a location if there is no caller
+	for i, f := range r.frames {
+		file, _ := f[StackSourceFileName].(string)
+		line := 0
+		switch v := f[StackSourceLineName].(type) {
+		case float64:
+			line = int(v)
+		case string:
+			line, _ = strconv.Atoi(v)
+		}
+		if r.file == "" && i == 0 {
+			r.file, r.line = file, line
+		}
+		if file != "" && line == r.line && strings.HasSuffix(r.file, file) {
+			r.function, _ = f[StackSourceFunctionName].(string)
+			break
+		}
+	}
+}
+
+// fields returns fields of Error Reporting, stack_trace is added only if there is a stack trace.
+func (r *gcpErrorReport) fields(service *GCPServiceContext) []jsonField {
+	out := make([]jsonField, 0, 4)
+	marshal := func(key string, value any) {
+		data, _ := json.Marshal(value)
+		out = append(out, jsonField{key: key, value: data})
+	}
+	marshal("@type", GCPErrorReportingType)
+
+	if r.file != "" {
+		loc := map[string]any{"filePath": r.file, "lineNumber": r.line}
+		if r.function != "" {
+			loc["functionName"] = r.function
+		}
+		marshal("context", map[string]any{"reportLocation": loc})
+	}
+	if service.Service != "" {
+		ctx := map[string]string{"service": service.Service}
+		if service.Version != "" {
+			ctx["version"] = service.Version
+		}
+		marshal("serviceContext", ctx)
+	}
+
+	if len(r.frames) > 0 {
+		// Error Reporting parses stack traces in the format of Go panics
+		var b strings.Builder
+		b.WriteString(r.message)
+		b.WriteString("\n\ngoroutine 1 [running]:\n")
+		for i, f := range r.frames {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%v()\n\t%v:%v", f[StackSourceFunctionName], f[StackSourceFileName], f[StackSourceLineName])
+		}
+		marshal("stack_trace", b.String())
+	}
+	return out
+}
+
+// gcpSeverity returns a severity of Cloud Logging for a level.
+func gcpSeverity(level string) string {
+	switch level {
+	case LevelTrace, LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "CRITICAL"
+	case zerolog.LevelPanicValue:
+		return "ALERT"
+	}
+	return "DEFAULT"
+}
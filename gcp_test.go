@@ -0,0 +1,145 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestGCP(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelDebug).
+		WithCallerLevels(logze.LevelWarn).WithGCPProjectID("billing-prod").WithGCP())
+
+	logger.Warn("slow request", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7", "path", "/users")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry[logze.GCPSeverityFieldName] != "WARNING" {
+		t.Errorf("expected severity WARNING, got %v", entry[logze.GCPSeverityFieldName])
+	}
+	if _, ok := entry["level"]; ok {
+		t.Errorf("expected no level field, got %v", entry)
+	}
+	if entry["message"] != "slow request" || entry["path"] != "/users" {
+		t.Errorf("expected message and fields, got %v", entry)
+	}
+	if ts, _ := entry["time"].(string); !strings.Contains(ts, "T") {
+		t.Errorf("expected RFC3339 time, got %v", entry["time"])
+	}
+	if trace := entry[logze.GCPTraceFieldName]; trace != "projects/billing-prod/traces/4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace with project, got %v", trace)
+	}
+	if span := entry[logze.GCPSpanIDFieldName]; span != "00f067aa0ba902b7" {
+		t.Errorf("expected span ID, got %v", span)
+	}
+	loc, ok := entry[logze.GCPSourceLocationFieldName].(map[string]any)
+	if !ok {
+		t.Fatalf("expected source location, got %v", entry)
+	}
+	if file, _ := loc["file"].(string); !strings.HasSuffix(file, "gcp_test.go") || loc["line"] == "" {
+		t.Errorf("expected file and line of the test, got %v", loc)
+	}
+
+	b.Reset()
+	logger.Debug("debug")
+	if !strings.Contains(b.String(), `"severity":"DEBUG"`) {
+		t.Errorf("expected DEBUG severity, got %s", b.String())
+	}
+}
+
+func TestGCPConfigFile(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "from-env")
+	cfg, err := logze.ParseConfig([]byte("gcp: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.GCP || cfg.GCPProjectID != "from-env" {
+		t.Errorf("expected GCP mode with project from env, got %v %q", cfg.GCP, cfg.GCPProjectID)
+	}
+
+	cfg, err = logze.ParseConfig([]byte("gcp: true\ngcp_project_id: billing\necs: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.GCP || cfg.ECS || cfg.GCPProjectID != "billing" {
+		t.Errorf("expected GCP mode with project billing, got %v %v %q", cfg.GCP, cfg.ECS, cfg.GCPProjectID)
+	}
+}
+
+func TestGCPErrorReporting(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithStackTrace().
+		WithGCPErrorReporting(logze.GCPServiceContext{Service: "billing", Version: "v1.2.0"}))
+
+	logger.Err(errors.New("connection refused"), "cannot charge")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	if entry["@type"] != logze.GCPErrorReportingType {
+		t.Errorf("expected type of Error Reporting, got %v", entry["@type"])
+	}
+	if entry[logze.GCPSeverityFieldName] != "ERROR" {
+		t.Errorf("expected severity ERROR, got %v", entry[logze.GCPSeverityFieldName])
+	}
+	service, _ := entry["serviceContext"].(map[string]any)
+	if service["service"] != "billing" || service["version"] != "v1.2.0" {
+		t.Errorf("expected service context, got %v", entry["serviceContext"])
+	}
+	ctx, _ := entry["context"].(map[string]any)
+	loc, _ := ctx["reportLocation"].(map[string]any)
+	if file, _ := loc["filePath"].(string); !strings.HasSuffix(file, "gcp_test.go") || loc["lineNumber"] == float64(0) {
+		t.Errorf("expected report location in the test, got %v", entry["context"])
+	}
+	if loc["functionName"] != "TestGCPErrorReporting" {
+		t.Errorf("expected function of the test from stack trace, got %v", loc["functionName"])
+	}
+	stack, _ := entry["stack_trace"].(string)
+	if !strings.HasPrefix(stack, "cannot charge: connection refused\n\ngoroutine 1 [running]:\n") ||
+		!strings.Contains(stack, "TestGCPErrorReporting()\n\t") {
+		t.Errorf("expected stack trace in format of Go panics, got %q", stack)
+	}
+
+	b.Reset()
+	logger.Warn("slow request")
+	if strings.Contains(b.String(), "@type") {
+		t.Errorf("expected no Error Reporting fields for warn level, got %s", b.String())
+	}
+}
+
+func TestGCPErrorReportingCaller(t *testing.T) {
+	t.Setenv("K_SERVICE", "api")
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithGCPErrorReporting(logze.GCPServiceContext{}))
+
+	logger.Error("cannot start")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v, got %s", err, b.String())
+	}
+	ctx, _ := entry["context"].(map[string]any)
+	loc, _ := ctx["reportLocation"].(map[string]any)
+	if file, _ := loc["filePath"].(string); !strings.HasSuffix(file, "gcp_test.go") {
+		t.Errorf("expected report location from caller, got %v", entry)
+	}
+	if service, _ := entry["serviceContext"].(map[string]any); service["service"] != "api" {
+		t.Errorf("expected service from K_SERVICE, got %v", entry["serviceContext"])
+	}
+	if _, ok := entry["stack_trace"]; ok {
+		t.Errorf("expected no stack trace without stack, got %v", entry)
+	}
+
+	cfg, err := logze.ParseConfig([]byte("gcp_error_reporting:\n  service: worker\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.GCP || cfg.GCPErrorReporting == nil || cfg.GCPErrorReporting.Service != "worker" {
+		t.Errorf("expected Error Reporting with service worker, got %v %v", cfg.GCP, cfg.GCPErrorReporting)
+	}
+}
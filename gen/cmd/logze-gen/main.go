@@ -0,0 +1,52 @@
+// Command logze-gen generates a typed logging facade from an event schema in YAML or JSON format.
+//
+// Usage:
+//
+//	logze-gen -schema events.yaml -out events_gen.go [-package events]
+//
+// It can be used with go:generate directive:
+//
+//	//go:generate go run github.com/maxbolgarin/logze/v2/gen/cmd/logze-gen -schema events.yaml -out events_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/maxbolgarin/logze/v2/gen"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path to a YAML or JSON schema of events")
+	out := flag.String("out", "", "path to an output file, stdout is used if empty")
+	pkg := flag.String("package", "", "name of a package of a generated file, overrides schema")
+	flag.Parse()
+
+	if err := run(*schemaPath, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "logze-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(schemaPath, out, pkg string) error {
+	if schemaPath == "" {
+		return fmt.Errorf("schema is not provided")
+	}
+	s, err := gen.LoadSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+	if pkg != "" {
+		s.Package = pkg
+	}
+	src, err := gen.Generate(s)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,465 @@
+// Package gen generates typed logging facades on top of logze from an event schema,
+// so messages and field names stay consistent across services:
+//
+//	events:
+//	  - name: UserLoggedIn
+//	    message: user logged in
+//	    fields:
+//	      - {name: userID, key: user_id, type: string}
+//	      - {name: ip, type: string}
+//
+// is turned into
+//
+//	// UserLoggedIn logs "user logged in" event in info level.
+//	func UserLoggedIn(lg logze.Logger, userID string, ip string) {
+//		lg.WithCallerSkip(1).Info("user logged in", "event", "user_logged_in", "user_id", userID, "ip", ip)
+//	}
+//
+// Schema can be loaded from a YAML or JSON file with [LoadSchema] or built from Go structs with [SchemaFromStructs].
+// Use [Generate] as a library entry point (e.g. from go:generate program) or logze-gen command.
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEventKey is a default name of a field with an event name.
+const DefaultEventKey = "event"
+
+// Levels of events.
+const (
+	LevelTrace = "trace"
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Schema describes a set of events to generate a facade for.
+type Schema struct {
+	// Package is a name of a package of generated file. Default value is "events".
+	Package string `yaml:"package" json:"package"`
+
+	// EventKey is a name of a field with an event name. Default value is "event".
+	// Use "-" to omit event name field.
+	EventKey string `yaml:"event_key" json:"event_key"`
+
+	// Events is a list of events, a function will be generated for every event.
+	Events []Event `yaml:"events" json:"events"`
+}
+
+// Event describes a single event.
+type Event struct {
+	// Name is a name of a generated function in CamelCase, e.g. UserLoggedIn.
+	Name string `yaml:"name" json:"name"`
+
+	// Message is a log message. Default value is a name split to lowercase words, e.g. "user logged in".
+	Message string `yaml:"message" json:"message"`
+
+	// Level is a level of an event. Default value is info.
+	Level string `yaml:"level" json:"level"`
+
+	// Error if true, generated function will accept error as the second argument and log it with [logze.Logger.Err].
+	// Level is ignored in that case.
+	Error bool `yaml:"error" json:"error"`
+
+	// Fields is a list of fields of an event, every field is an argument of a generated function.
+	Fields []Field `yaml:"fields" json:"fields"`
+}
+
+// Field describes a field of an event.
+type Field struct {
+	// Name is a name of an argument, e.g. userID.
+	Name string `yaml:"name" json:"name"`
+
+	// Key is a key of a field in a log entry. Default value is a name in snake_case, e.g. user_id.
+	Key string `yaml:"key" json:"key"`
+
+	// Type is a Go type of an argument. Default value is "any".
+	Type string `yaml:"type" json:"type"`
+
+	// Imports are import paths of packages used in a type, e.g. net/url for *url.URL.
+	// Standard library packages with a path equal to a name (e.g. time for time.Duration) are imported without it.
+	Imports []string `yaml:"imports" json:"imports"`
+}
+
+// LoadSchema reads a schema from a YAML or JSON file.
+func LoadSchema(path string) (Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Schema{}, fmt.Errorf("read schema: %w", err)
+	}
+	return ParseSchema(data)
+}
+
+// ParseSchema parses a schema in YAML or JSON format.
+func ParseSchema(data []byte) (Schema, error) {
+	var s Schema
+	// JSON is a subset of YAML
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Schema{}, fmt.Errorf("parse schema: %w", err)
+	}
+	return s, nil
+}
+
+// SchemaFromStructs builds a schema from Go structs: every struct is an event named after its type
+// and every exported field is a field of an event. Key of a field can be set with logze tag,
+// message and level of an event are set with logze tag of a blank field:
+//
+//	type UserLoggedIn struct {
+//		_      struct{} `logze:"message=user logged in,level=info"`
+//		UserID string   `logze:"user_id"`
+//		IP     string
+//	}
+//
+// Fields with `logze:"-"` tag are skipped. Events should be structs or pointers to structs.
+func SchemaFromStructs(pkg string, events ...any) (Schema, error) {
+	s := Schema{Package: pkg}
+	for _, ev := range events {
+		t := reflect.TypeOf(ev)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return Schema{}, fmt.Errorf("event %T is not a struct", ev)
+		}
+
+		e := Event{Name: t.Name()}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("logze")
+			if f.Name == "_" {
+				if err := parseEventTag(&e, tag); err != nil {
+					return Schema{}, fmt.Errorf("event %s: %w", e.Name, err)
+				}
+				continue
+			}
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			e.Fields = append(e.Fields, Field{
+				Name:    lowerFirst(f.Name),
+				Key:     tag,
+				Type:    f.Type.String(),
+				Imports: typeImports(f.Type, nil),
+			})
+		}
+		s.Events = append(s.Events, e)
+	}
+	return s, nil
+}
+
+// Generate returns a formatted Go source of a typed facade for events from the schema.
+func Generate(s Schema) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	pkg := s.Package
+	if pkg == "" {
+		pkg = "events"
+	}
+	eventKey := s.EventKey
+	if eventKey == "" {
+		eventKey = DefaultEventKey
+	}
+
+	imports := map[string]bool{logzePath: true}
+	for _, e := range s.Events {
+		for _, f := range e.Fields {
+			paths, err := f.imports()
+			if err != nil {
+				return nil, fmt.Errorf("event %s: %w", e.Name, err)
+			}
+			for _, path := range paths {
+				imports[path] = true
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by logze-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	writeImports(&b, imports)
+
+	for _, e := range s.Events {
+		msg := e.Message
+		if msg == "" {
+			msg = strings.Join(splitWords(e.Name), " ")
+		}
+		level := e.Level
+		if level == "" {
+			level = LevelInfo
+		}
+
+		params := []string{"lg logze.Logger"}
+		var args []string
+		if e.Error {
+			params = append(params, "err error")
+			args = append(args, "err")
+		}
+		args = append(args, fmt.Sprintf("%q", msg))
+		if eventKey != "-" {
+			args = append(args, fmt.Sprintf("%q, %q", eventKey, toSnake(e.Name)))
+		}
+		for _, f := range e.Fields {
+			typ := f.Type
+			if typ == "" {
+				typ = "any"
+			}
+			key := f.Key
+			if key == "" {
+				key = toSnake(f.Name)
+			}
+			params = append(params, f.Name+" "+typ)
+			args = append(args, fmt.Sprintf("%q, %s", key, f.Name))
+		}
+
+		method := strings.ToUpper(level[:1]) + level[1:]
+		if e.Error {
+			method = "Err"
+			fmt.Fprintf(&b, "\n// %s logs %q event with an error.\n", e.Name, msg)
+		} else {
+			fmt.Fprintf(&b, "\n// %s logs %q event in %s level.\n", e.Name, msg, level)
+		}
+		// Caller and error caller should point to a call site of the facade, not to the generated file
+		fmt.Fprintf(&b, "func %s(%s) {\n\tlg.WithCallerSkip(1).%s(%s)\n}\n", e.Name, strings.Join(params, ", "), method, strings.Join(args, ", "))
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format source: %w", err)
+	}
+	return src, nil
+}
+
+// logzePath is an import path of logze package used by generated facades.
+const logzePath = "github.com/maxbolgarin/logze/v2"
+
+// writeImports writes an import declaration with standard library packages in the first group.
+func writeImports(b *bytes.Buffer, imports map[string]bool) {
+	var std, other []string
+	for path := range imports {
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	if len(std) > 0 {
+		b.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	b.WriteString(")\n")
+}
+
+// imports returns import paths of packages that are used in a type of the field.
+func (f Field) imports() ([]string, error) {
+	if f.Type == "" {
+		return nil, nil
+	}
+	expr, err := parser.ParseExpr(f.Type)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: invalid type %q", f.Name, f.Type)
+	}
+	var (
+		paths []string
+		errs  []string
+	)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		name, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		path, ok := importPath(name.Name, f.Imports)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("field %s: unknown package %q in type %q, add its path to imports", f.Name, name.Name, f.Type))
+			return false
+		}
+		paths = append(paths, path)
+		return false
+	})
+	if len(errs) > 0 {
+		return nil, errors.New(errs[0])
+	}
+	return paths, nil
+}
+
+// importPath returns a path of a package with provided name from imports of a field
+// or from the standard library if there is a package with such path.
+func importPath(name string, imports []string) (string, bool) {
+	for _, path := range imports {
+		elems := strings.Split(path, "/")
+		last := elems[len(elems)-1]
+		if last == name {
+			return path, true
+		}
+		// Major version suffix is not a part of a package name, e.g. github.com/maxbolgarin/logze/v2
+		if len(elems) > 1 && isMajorVersion(last) && elems[len(elems)-2] == name {
+			return path, true
+		}
+	}
+	if p, err := build.Default.Import(name, "", build.FindOnly); err == nil && p.Goroot {
+		return name, true
+	}
+	return "", false
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// typeImports adds import paths of named types used in t to paths.
+func typeImports(t reflect.Type, paths []string) []string {
+	if t.Name() != "" {
+		if path := t.PkgPath(); path != "" {
+			for _, p := range paths {
+				if p == path {
+					return paths
+				}
+			}
+			paths = append(paths, path)
+		}
+		return paths
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Chan:
+		return typeImports(t.Elem(), paths)
+	case reflect.Map:
+		return typeImports(t.Elem(), typeImports(t.Key(), paths))
+	}
+	return paths
+}
+
+func (s Schema) validate() error {
+	if s.Package != "" && !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("invalid package name %q", s.Package)
+	}
+	if len(s.Events) == 0 {
+		return errors.New("no events in schema")
+	}
+	names := make(map[string]bool, len(s.Events))
+	for _, e := range s.Events {
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			return fmt.Errorf("invalid event name %q, it should be an exported Go identifier", e.Name)
+		}
+		if names[e.Name] {
+			return fmt.Errorf("duplicated event %q", e.Name)
+		}
+		names[e.Name] = true
+
+		switch e.Level {
+		case "", LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError:
+		default:
+			return fmt.Errorf("event %s: invalid level %q", e.Name, e.Level)
+		}
+
+		args := map[string]bool{"lg": true, "err": e.Error}
+		for _, f := range e.Fields {
+			if !token.IsIdentifier(f.Name) || token.IsKeyword(f.Name) {
+				return fmt.Errorf("event %s: invalid field name %q", e.Name, f.Name)
+			}
+			if args[f.Name] {
+				return fmt.Errorf("event %s: duplicated or reserved field name %q", e.Name, f.Name)
+			}
+			args[f.Name] = true
+		}
+	}
+	return nil
+}
+
+func parseEventTag(e *Event, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("invalid tag option %q", part)
+		}
+		switch strings.TrimSpace(k) {
+		case "message":
+			e.Message = v
+		case "level":
+			e.Level = strings.TrimSpace(v)
+		case "error":
+			e.Error = strings.TrimSpace(v) == "true"
+		default:
+			return fmt.Errorf("unknown tag option %q", k)
+		}
+	}
+	return nil
+}
+
+// splitWords splits CamelCase name to lowercase words keeping abbreviations, e.g. HTTPRequestFailed -> http request failed.
+func splitWords(name string) []string {
+	var (
+		words []string
+		cur   []rune
+	)
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower && unicode.IsUpper(runes[i-1]) {
+				words = append(words, strings.ToLower(string(cur)))
+				cur = cur[:0]
+			}
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		words = append(words, strings.ToLower(string(cur)))
+	}
+	return words
+}
+
+func toSnake(name string) string {
+	return strings.Join(splitWords(name), "_")
+}
+
+func lowerFirst(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	// Keep original case of the rest of the name, e.g. UserID -> userID
+	first := len([]rune(words[0]))
+	runes := []rune(name)
+	return strings.ToLower(string(runes[:first])) + string(runes[first:])
+}
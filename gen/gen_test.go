@@ -0,0 +1,166 @@
+package gen_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2/gen"
+)
+
+const testSchema = `
+package: events
+events:
+  - name: UserLoggedIn
+    fields:
+      - {name: userID, type: string}
+      - {name: ip, key: remote_ip, type: string}
+  - name: HTTPRequestFailed
+    message: request failed
+    error: true
+    fields:
+      - {name: path, type: string}
+  - name: CacheMiss
+    level: debug
+`
+
+func TestGenerate(t *testing.T) {
+	s, err := gen.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := gen.Generate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package events",
+		`"github.com/maxbolgarin/logze/v2"`,
+		"func UserLoggedIn(lg logze.Logger, userID string, ip string) {",
+		`lg.WithCallerSkip(1).Info("user logged in", "event", "user_logged_in", "user_id", userID, "remote_ip", ip)`,
+		"func HTTPRequestFailed(lg logze.Logger, err error, path string) {",
+		`lg.WithCallerSkip(1).Err(err, "request failed", "event", "http_request_failed", "path", path)`,
+		`lg.WithCallerSkip(1).Debug("cache miss", "event", "cache_miss")`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected %q in generated source, got\n%s", expected, src)
+		}
+	}
+}
+
+func TestSchemaFromStructs(t *testing.T) {
+	type UserLoggedIn struct {
+		_      struct{} `logze:"message=user has logged in,level=warn"`
+		UserID string   `logze:"uid"`
+		IP     string
+		Secret string `logze:"-"`
+	}
+
+	s, err := gen.SchemaFromStructs("audit", UserLoggedIn{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := gen.Generate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"package audit",
+		"func UserLoggedIn(lg logze.Logger, userID string, ip string) {",
+		`lg.WithCallerSkip(1).Warn("user has logged in", "event", "user_logged_in", "uid", userID, "ip", ip)`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected %q in generated source, got\n%s", expected, src)
+		}
+	}
+
+	if _, err := gen.SchemaFromStructs("audit", "not a struct"); err == nil {
+		t.Error("expected error for not a struct")
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	for _, s := range []gen.Schema{
+		{},
+		{Events: []gen.Event{{Name: "lowercase"}}},
+		{Events: []gen.Event{{Name: "A"}, {Name: "A"}}},
+		{Events: []gen.Event{{Name: "A", Level: "unknown"}}},
+		{Events: []gen.Event{{Name: "A", Fields: []gen.Field{{Name: "lg"}}}}},
+		{Events: []gen.Event{{Name: "A", Fields: []gen.Field{{Name: "func"}}}}},
+		{Package: "my-events", Events: []gen.Event{{Name: "A"}}},
+	} {
+		if _, err := gen.Generate(s); err == nil {
+			t.Errorf("expected error for %+v", s)
+		}
+	}
+}
+
+// typeCheck fails the test if the generated source doesn't compile.
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "events_gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("cannot parse generated source: %v\n%s", err, src)
+	}
+	cfg := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := cfg.Check("events", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("generated source doesn't compile: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateImports(t *testing.T) {
+	s, err := gen.ParseSchema([]byte(`
+events:
+  - name: RequestHandled
+    fields:
+      - {name: took, type: time.Duration}
+      - {name: at, type: "map[string]time.Time"}
+      - {name: target, type: "*url.URL", imports: [net/url]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := gen.Generate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`"net/url"`, `"time"`} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected %q in generated source, got\n%s", expected, src)
+		}
+	}
+	typeCheck(t, src)
+
+	s.Events[0].Fields[2].Imports = nil
+	if _, err := gen.Generate(s); err == nil || !strings.Contains(err.Error(), `unknown package "url"`) {
+		t.Errorf("expected error for unknown package, got %v", err)
+	}
+}
+
+func TestSchemaFromStructsImports(t *testing.T) {
+	type JobFinished struct {
+		Took     time.Duration
+		Started  *time.Time
+		Callback []*url.URL
+	}
+	s, err := gen.SchemaFromStructs("events", JobFinished{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := gen.Generate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "func JobFinished(lg logze.Logger, took time.Duration, started *time.Time, callback []*url.URL) {") {
+		t.Errorf("expected qualified types, got\n%s", src)
+	}
+	typeCheck(t, src)
+}
@@ -0,0 +1,242 @@
+// Package ginmw provides gin middlewares that write access logs and report panics using [logze.Logger]
+// instead of gin's default writer-based logging.
+//
+// Every access log entry has a method, route, path, status, number of written bytes, duration, client IP
+// and request ID. Request ID is taken from a request header or generated if it is missing, it is returned
+// to a client in the same header and added to a request-scoped logger, use [logze.FromContext]
+// with a context of a request to get it.
+//
+// It is a separate module, so the core logze package doesn't depend on gin.
+package ginmw
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureGinMiddleware)
+}
+
+const (
+	// DefaultMessage is a default message of access log entries.
+	DefaultMessage = "request"
+
+	// DefaultPanicMessage is a default message of entries about recovered panics.
+	DefaultPanicMessage = "panic recovered"
+
+	// DefaultRequestIDHeader is a default header to get request ID from and to return it to a client.
+	DefaultRequestIDHeader = "X-Request-ID"
+)
+
+// Enumerating names of fields of entries.
+var (
+	MethodFieldName    = "method"
+	RouteFieldName     = "route"
+	PathFieldName      = "path"
+	StatusFieldName    = "status"
+	BytesFieldName     = "bytes"
+	DurationFieldName  = "duration"
+	ClientIPFieldName  = "client_ip"
+	RequestIDFieldName = "request_id"
+	ErrorsFieldName    = "errors"
+	PanicFieldName     = "panic"
+	StackFieldName     = "stack"
+)
+
+// Option changes a behaviour of [Logger].
+type Option func(o *options)
+
+// WithLevel sets a level of access log entries, default value is [logze.LevelInfo].
+// Requests with errors added to a gin context are logged in error level.
+func WithLevel(level string) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithRouteLevel sets a level of access log entries of requests with a path that starts with provided prefix,
+// e.g. [logze.LevelDisabled] for "/healthz" to skip probes. The longest matching prefix is used.
+func WithRouteLevel(prefix, level string) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, route{prefix: prefix, level: level})
+	}
+}
+
+// WithMessage sets a message of access log entries, default value is [DefaultMessage].
+func WithMessage(msg string) Option {
+	return func(o *options) {
+		o.message = msg
+	}
+}
+
+// WithRequestIDHeader sets a header to get request ID from and to return it to a client,
+// default value is [DefaultRequestIDHeader].
+func WithRequestIDHeader(header string) Option {
+	return func(o *options) {
+		o.requestIDHeader = header
+	}
+}
+
+// Logger returns [gin.HandlerFunc] that writes an access log entry for every request.
+func Logger(l logze.Logger, opts ...Option) gin.HandlerFunc {
+	o := options{
+		level:           logze.LevelInfo,
+		message:         DefaultMessage,
+		requestIDHeader: DefaultRequestIDHeader,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Longest prefixes go first, so the first match is the most specific one
+	sort.SliceStable(o.routes, func(i, j int) bool {
+		return len(o.routes[i].prefix) > len(o.routes[j].prefix)
+	})
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		id := c.GetHeader(o.requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		c.Header(o.requestIDHeader, id)
+		lg := l.WithFields(RequestIDFieldName, id)
+		c.Request = c.Request.WithContext(lg.WithContext(c.Request.Context()))
+		c.Set(loggerKey, lg)
+
+		c.Next()
+
+		level := o.levelFor(path)
+		if level == logze.LevelDisabled {
+			return
+		}
+		if len(c.Errors) > 0 {
+			level = logze.LevelError
+		}
+		if !lg.Enabled(level) {
+			return
+		}
+		fields := []any{
+			MethodFieldName, c.Request.Method,
+			RouteFieldName, c.FullPath(),
+			PathFieldName, path,
+			StatusFieldName, c.Writer.Status(),
+			BytesFieldName, size(c.Writer.Size()),
+			DurationFieldName, time.Since(start),
+			ClientIPFieldName, c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, ErrorsFieldName, c.Errors.Errors())
+		}
+		lg.LogAttrs(level, o.message, fields)
+	}
+}
+
+// Recovery returns [gin.HandlerFunc] that recovers from panics in next handlers, logs them in error level
+// with a stack trace and aborts a request with 500 status. If a client connection is broken,
+// the panic is logged without a stack trace and no status is written.
+func Recovery(l logze.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			brokenPipe := isBrokenPipe(rec)
+			lg := l
+			if v, ok := c.Get(loggerKey); ok {
+				// Request-scoped logger from Logger middleware has request ID
+				lg, _ = v.(logze.Logger)
+			}
+			fields := []any{
+				MethodFieldName, c.Request.Method,
+				PathFieldName, c.Request.URL.Path,
+				PanicFieldName, fmt.Sprint(rec),
+			}
+			if !brokenPipe {
+				fields = append(fields, StackFieldName, string(debug.Stack()))
+			}
+			lg.LogAttrs(logze.LevelError, DefaultPanicMessage, fields)
+
+			if brokenPipe {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
+
+// loggerKey is a key of a request-scoped logger in gin context.
+const loggerKey = "logze.logger"
+
+type route struct {
+	prefix string
+	level  string
+}
+
+type options struct {
+	level           string
+	message         string
+	requestIDHeader string
+	routes          []route
+}
+
+func (o options) levelFor(path string) string {
+	for _, r := range o.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.level
+		}
+	}
+	return o.level
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// size returns 0 instead of -1 that gin returns if nothing was written.
+func size(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// isBrokenPipe returns true if a panic is caused by a broken client connection, it is checked in the same way
+// as in gin's default recovery.
+func isBrokenPipe(rec any) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr.Err, &sysErr) {
+		return errors.Is(sysErr.Err, syscall.EPIPE) || errors.Is(sysErr.Err, syscall.ECONNRESET)
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package ginmw_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/ginmw"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	r := gin.New()
+	r.Use(ginmw.Logger(logger, ginmw.WithRouteLevel("/healthz", logze.LevelDisabled)))
+	r.GET("/users/:id", func(c *gin.Context) {
+		logze.FromContext(c.Request.Context()).Info("handler")
+		c.String(http.StatusOK, "hello")
+	})
+	r.GET("/fail", func(c *gin.Context) {
+		c.Error(errors.New("some error"))
+		c.Status(http.StatusBadRequest)
+	})
+	r.GET("/healthz", func(c *gin.Context) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(ginmw.DefaultRequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if id := rec.Header().Get(ginmw.DefaultRequestIDHeader); id != "req-1" {
+		t.Errorf("expected request ID req-1 in response, got %q", id)
+	}
+	entries := decode(t, &b)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0]["message"] != "handler" || entries[0]["request_id"] != "req-1" {
+		t.Errorf("expected handler entry with request ID, got %v", entries[0])
+	}
+	expected := map[string]any{
+		"level":      "info",
+		"message":    ginmw.DefaultMessage,
+		"method":     "GET",
+		"route":      "/users/:id",
+		"path":       "/users/42",
+		"status":     float64(http.StatusOK),
+		"bytes":      float64(5),
+		"request_id": "req-1",
+	}
+	for k, v := range expected {
+		if entries[1][k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entries[1][k])
+		}
+	}
+
+	b.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	entries = decode(t, &b)
+	if len(entries) != 1 || entries[0]["level"] != "error" || entries[0]["status"] != float64(http.StatusBadRequest) {
+		t.Errorf("expected error entry for request with errors, got %v", entries)
+	}
+
+	b.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if b.Len() != 0 {
+		t.Errorf("expected no entry for disabled route, got %s", b.String())
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	r := gin.New()
+	r.Use(ginmw.Logger(logger), ginmw.Recovery(logger))
+	r.GET("/", func(c *gin.Context) {
+		panic("boom")
+	})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+	entries := decode(t, &b)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	panicEntry := entries[0]
+	if panicEntry["level"] != "error" || panicEntry["panic"] != "boom" || panicEntry["request_id"] == nil {
+		t.Errorf("expected panic entry with request ID, got %v", panicEntry)
+	}
+	if stack, _ := panicEntry["stack"].(string); !strings.Contains(stack, "TestRecovery") {
+		t.Errorf("expected stack with the handler, got %q", stack)
+	}
+	if entries[1]["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("expected access log with status 500, got %v", entries[1])
+	}
+}
+
+func decode(t *testing.T, b *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -6,8 +6,19 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// Package-level functions for Logger methods without a hand-written counterpart are generated to global_gen.go.
+//go:generate go run ./internal/genglobal
+
 var log = NewConsoleJSON()
 
+// global returns a global logger that skips one more frame when capturing a caller,
+// it should be used in package-level logging functions.
+func global() Logger {
+	l := log
+	l.callerSkip++
+	return l
+}
+
 // Default returns a copy on a global logger.
 func Default() Logger {
 	return log
@@ -46,6 +57,26 @@ func SetStdLogger(l Logger, fields ...any) {
 	log = l
 }
 
+// SetStdLoggerLevels sets provided [Logger] with (key, value) pairs as writer for default Go logger, like
+// [SetStdLogger], but messages are logged as entries with levels detected by their prefixes,
+// see [Logger.StdLogLevels]. Messages without known prefixes are logged in info level.
+func SetStdLoggerLevels(l Logger, prefixes map[string]string, fields ...any) {
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(l.WithFields(fields...).StdLogLevels(LevelInfo, prefixes).Writer())
+	log = l
+}
+
+// SetLevel atomically changes the level of a global logger and all its copies sharing the level,
+// including the one installed with [SetStdLogger].
+func SetLevel(level string) error {
+	return log.SetLevel(level)
+}
+
+// GetLevel returns the current level of a global logger in string format.
+func GetLevel() string {
+	return log.GetLevel()
+}
+
 // WithFields returns [Logger] with applied fields, provided as (key, value) pairs, based on a global logger.
 func WithFields(fields ...any) Logger {
 	return log.WithFields(fields...)
@@ -74,126 +105,142 @@ func WithSimpleErrorCounter() Logger {
 
 // WithToIgnore returns [Logger] with the provided list of messages to ignore based on a global logger.
 func WithToIgnore(toIgnore ...string) Logger {
-	log.toIgnore = toIgnore
+	log.ignore = newIgnoreVar(toIgnore)
 	return log
 }
 
+// IfDebug calls provided function with a global logger only if debug level is enabled, see [Logger.IfDebug].
+func IfDebug(f func(l Logger)) {
+	// Logger is passed without additional caller skip, because it is used directly in f
+	log.IfDebug(f)
+}
+
+// IfTrace calls provided function with a global logger only if trace level is enabled, see [Logger.IfDebug].
+func IfTrace(f func(l Logger)) {
+	log.IfTrace(f)
+}
+
 // Trace logs a message in trace level adding provided fields and information about method caller
 // using a global logger.
 func Trace(msg string, fields ...any) {
-	log.log(log.l.Trace().Caller(1), msg, fields)
+	global().Trace(msg, fields...)
 }
 
 // Tracef logs a formatted message in trace level adding provided fields after formatting args
 // and information about method caller using a global logger.
 func Tracef(msg string, args ...any) {
-	log.logf(log.l.Trace().Caller(1), msg, args)
+	global().Tracef(msg, args...)
 }
 
 // Debug logs a message in debug level adding provided fields using a global logger.
 func Debug(msg string, fields ...any) {
-	log.Debug(msg, fields...)
+	global().Debug(msg, fields...)
 }
 
 // Debugf logs a formatted message in debug level adding provided fields after formatting args using a global logger.
 func Debugf(msg string, args ...any) {
-	log.Debugf(msg, args...)
+	global().Debugf(msg, args...)
 }
 
 // Info logs a message in info level adding provided fields using a global logger.
 func Info(msg string, fields ...any) {
-	log.Info(msg, fields...)
+	global().Info(msg, fields...)
 }
 
 // Infof logs a formatted message in info level adding provided fields after formatting args using a global logger.
 func Infof(msg string, args ...any) {
-	log.Infof(msg, args...)
+	global().Infof(msg, args...)
 }
 
 // Warn logs a message in warning level adding provided fields using a global logger.
 func Warn(msg string, fields ...any) {
-	log.Warn(msg, fields...)
+	global().Warn(msg, fields...)
 }
 
 // Warnf logs a formatted message in warn level adding provided fields after formatting args using a global logger.
 func Warnf(msg string, args ...any) {
-	log.Warnf(msg, args...)
+	global().Warnf(msg, args...)
 }
 
 // Err logs a provided error in error level adding provided fields using a global logger.
 func Err(err error, msg string, fields ...any) {
-	log.Err(err, msg, fields...)
+	global().Err(err, msg, fields...)
 }
 
 // Error logs a message in error level adding provided fields using a global logger.
 func Error(msg string, fields ...any) {
-	log.Error(msg, fields...)
+	global().Error(msg, fields...)
 }
 
 // Errorf logs a formatted message in error level adding provided fields after formatting args using a global logger.
 func Errorf(msg string, args ...any) {
-	log.Errorf(msg, args...)
+	global().Errorf(msg, args...)
+}
+
+// E returns [Event] of provided level using a global logger, see [Logger.E].
+func E(level string) *Event {
+	return global().E(level)
 }
 
 // ErrStack logs a stack trace of provided error as message in error level adding fields.
 func ErrStack(err error, fields ...any) {
-	log.ErrStack(err, fields...)
+	global().ErrStack(err, fields...)
 }
 
 // Fatal logs a message in fatal level using fmt.Sprint to interpret args sing a global logger, then calls os.Exit(1).
 func Fatal(v ...any) {
-	log.Fatal(v...)
+	global().Fatal(v...)
 }
 
 // Fatalf logs a formatted message in fatal level using a global logger, then calls os.Exit(1).
 func Fatalf(format string, args ...any) {
-	log.Fatalf(format, args...)
+	global().Fatalf(format, args...)
 }
 
 // Fatalln logs a message in fatal level using fmt.Sprintln to interpret args using a global logger, then calls os.Exit(1).
 func Fatalln(v ...any) {
-	log.Fatalln(v...)
+	global().Fatalln(v...)
 }
 
 // Panic logs a message in fatal level using fmt.Sprint to interpret args using a global logger, then calls panic().
 func Panic(v ...any) {
-	log.Panic(v...)
+	global().Panic(v...)
 }
 
 // Panicf logs a formatted message in fatal level using a global logger, then calls panic().
 func Panicf(format string, args ...any) {
-	log.Panicf(format, args...)
+	global().Panicf(format, args...)
 }
 
 // Panicln logs a message in fatal level using fmt.Sprintln to interpret args using a global logger, then calls panic().
 func Panicln(v ...any) {
-	log.Panicln(v...)
+	global().Panicln(v...)
 }
 
 // Print logs a message without level using [fmt.Sprint] to interpret args using a global logger.
 func Print(v ...any) {
-	log.Print(v...)
+	global().Print(v...)
 }
 
 // PrintStack logs a current stack trace.
 func PrintStack(v ...any) {
-	log.PrintStack(v...)
+	global().PrintStack(v...)
 }
 
 // Log logs a message without level using [fmt.Sprint] to interpret args using a global logger.
 // It is an alias for [Print].
 func Log(v ...any) {
-	log.Log(v...)
+	global().Log(v...)
 }
 
 // Printf logs a formatted message without level using a global logger.
 func Printf(format string, args ...any) {
-	log.Printf(format, args...)
+	global().Printf(format, args...)
 }
 
 // Println writes a message without level using fmt.Sprintln to interpret args using a global logger.
 func Println(v ...any) {
-	log.Println(v...)
+	global().Println(v...)
 }
 
 // Write writes bytes to underlying [io.Writer] using a global logger.
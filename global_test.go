@@ -3,6 +3,7 @@ package logze_test
 import (
 	"bytes"
 	"fmt"
+	stdlog "log"
 	"strings"
 	"testing"
 
@@ -227,3 +228,49 @@ func TestGlobalIgnoreMessages(t *testing.T) {
 		t.Errorf("expected %s, got %s", "ignore me", output)
 	}
 }
+
+func TestGlobalSetLevel(t *testing.T) {
+	var b bytes.Buffer
+	setupGlobalLogger(&b, logze.LevelInfo)
+
+	if err := logze.SetLevel(logze.LevelDisabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logze.GetLevel() != logze.LevelDisabled {
+		t.Errorf("expected %s, got %s", logze.LevelDisabled, logze.GetLevel())
+	}
+
+	logze.Warn("warn message")
+	stdlog.Print("std message")
+	if b.Len() != 0 {
+		t.Errorf("expected no logs, got %s", b.String())
+	}
+
+	if err := logze.SetLevel(logze.LevelTrace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdlog.Print("std message")
+	if !strings.Contains(b.String(), "std message") {
+		t.Errorf("expected std log, got %s", b.String())
+	}
+}
+
+func TestGlobalParity(t *testing.T) {
+	var b bytes.Buffer
+	logze.Init(logze.NewConfig(&b).WithErrorCaller().WithNoDiode())
+
+	logze.Errf(errors.New("some error"), "errf message %d", 1)
+	if !strings.Contains(b.String(), `"message":"errf message 1"`) || !strings.Contains(b.String(), "global_test.go:") {
+		t.Errorf("expected errf message with error caller, got %s", b.String())
+	}
+
+	if logze.NotInited() {
+		t.Error("expected inited global logger")
+	}
+	if err := logze.Reload(logze.NewConfig().WithLevel(logze.LevelError)); err != nil {
+		t.Fatal(err)
+	}
+	if logze.GetLevel() != logze.LevelError {
+		t.Errorf("expected level %s, got %s", logze.LevelError, logze.GetLevel())
+	}
+}
@@ -0,0 +1,168 @@
+// Package gormlog provides an implementation of GORM logger interface that writes through [logze.Logger]:
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{Logger: gormlog.New(logger)})
+//
+// Every query is logged with its SQL, number of affected rows, duration and a source line in an application.
+// Queries are logged in debug level, slow queries in warn level and failed queries in error level.
+//
+// It is a separate module, so the core logze package doesn't depend on GORM.
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureGORMLogger)
+}
+
+// DefaultSlowThreshold is a default duration of a query after which it is logged as slow.
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// Enumerating messages of entries about queries.
+const (
+	QueryMessage      = "sql query"
+	SlowQueryMessage  = "slow sql query"
+	QueryErrorMessage = "sql query failed"
+)
+
+// Enumerating names of fields of entries about queries.
+var (
+	SQLFieldName           = "sql"
+	RowsFieldName          = "rows"
+	DurationFieldName      = "duration"
+	SourceFieldName        = "source"
+	SlowThresholdFieldName = "slow_threshold"
+)
+
+// Option changes a behaviour of [Logger].
+type Option func(l *Logger)
+
+// WithSlowThreshold sets a duration of a query after which it is logged in warn level,
+// default value is [DefaultSlowThreshold]. Zero disables slow query warnings.
+func WithSlowThreshold(d time.Duration) Option {
+	return func(l *Logger) {
+		l.slowThreshold = d
+	}
+}
+
+// WithQueryLevel sets a level of entries about successful queries, default value is [logze.LevelDebug].
+func WithQueryLevel(level string) Option {
+	return func(l *Logger) {
+		l.queryLevel = level
+	}
+}
+
+// WithIgnoreRecordNotFound disables error entries for [gorm.ErrRecordNotFound], such queries are logged
+// as successful ones.
+func WithIgnoreRecordNotFound() Option {
+	return func(l *Logger) {
+		l.ignoreNotFound = true
+	}
+}
+
+var _ gormlogger.Interface = (*Logger)(nil)
+
+// Logger implements [gormlogger.Interface] using [logze.Logger].
+type Logger struct {
+	l              logze.Logger
+	mode           gormlogger.LogLevel
+	slowThreshold  time.Duration
+	queryLevel     string
+	ignoreNotFound bool
+}
+
+// New returns [Logger] that writes GORM messages and queries through provided logger.
+// All messages are passed to the logger, so its level filters them. Use [Logger.LogMode]
+// or Silent mode of GORM session to limit them further.
+func New(l logze.Logger, opts ...Option) *Logger {
+	out := &Logger{
+		l:             l,
+		mode:          gormlogger.Info,
+		slowThreshold: DefaultSlowThreshold,
+		queryLevel:    logze.LevelDebug,
+	}
+	for _, opt := range opts {
+		opt(out)
+	}
+	return out
+}
+
+// LogMode returns a copy of the logger with GORM log level: Silent disables all entries,
+// Error keeps only failed queries and errors, Warn adds slow queries and warnings and Info logs everything.
+func (l *Logger) LogMode(mode gormlogger.LogLevel) gormlogger.Interface {
+	out := *l
+	out.mode = mode
+	return &out
+}
+
+// Info logs a formatted message in info level.
+func (l *Logger) Info(_ context.Context, msg string, args ...any) {
+	if l.mode >= gormlogger.Info {
+		l.l.Infof(msg, args...)
+	}
+}
+
+// Warn logs a formatted message in warn level.
+func (l *Logger) Warn(_ context.Context, msg string, args ...any) {
+	if l.mode >= gormlogger.Warn {
+		l.l.Warnf(msg, args...)
+	}
+}
+
+// Error logs a formatted message in error level.
+func (l *Logger) Error(_ context.Context, msg string, args ...any) {
+	if l.mode >= gormlogger.Error {
+		l.l.Errorf(msg, args...)
+	}
+}
+
+// Trace logs an executed query: failed queries in error level, queries that take more than the slow threshold
+// in warn level and other queries in the query level.
+func (l *Logger) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.mode <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	failed := err != nil && !(l.ignoreNotFound && errors.Is(err, gorm.ErrRecordNotFound))
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+
+	var level, msg string
+	switch {
+	case failed && l.mode >= gormlogger.Error:
+		level, msg = logze.LevelError, QueryErrorMessage
+	case slow && l.mode >= gormlogger.Warn:
+		level, msg = logze.LevelWarn, SlowQueryMessage
+	case !failed && l.mode >= gormlogger.Info:
+		level, msg = l.queryLevel, QueryMessage
+	default:
+		return
+	}
+	if !l.l.Enabled(level) {
+		return
+	}
+
+	sql, rows := fc()
+	fields := []any{
+		SQLFieldName, sql,
+		DurationFieldName, elapsed,
+		SourceFieldName, utils.FileWithLineNum(),
+	}
+	if rows >= 0 {
+		fields = append(fields, RowsFieldName, rows)
+	}
+	switch {
+	case failed:
+		fields = append(fields, "error", err)
+	case slow:
+		fields = append(fields, SlowThresholdFieldName, l.slowThreshold)
+	}
+	l.l.LogAttrs(level, msg, fields)
+}
@@ -0,0 +1,78 @@
+package gormlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/gormlog"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestTrace(t *testing.T) {
+	var b bytes.Buffer
+	logger := gormlog.New(logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelDebug)),
+		gormlog.WithSlowThreshold(time.Second), gormlog.WithIgnoreRecordNotFound())
+	query := func() (string, int64) { return "SELECT * FROM users", 3 }
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		begin time.Time
+		err   error
+		level string
+		msg   string
+	}{
+		{"query", time.Now(), nil, "debug", gormlog.QueryMessage},
+		{"slow", time.Now().Add(-2 * time.Second), nil, "warn", gormlog.SlowQueryMessage},
+		{"failed", time.Now(), errors.New("some error"), "error", gormlog.QueryErrorMessage},
+		{"not found", time.Now(), gorm.ErrRecordNotFound, "debug", gormlog.QueryMessage},
+	}
+	for _, tt := range tests {
+		b.Reset()
+		logger.Trace(ctx, tt.begin, query, tt.err)
+
+		var entry map[string]any
+		if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if entry["level"] != tt.level || entry["message"] != tt.msg {
+			t.Errorf("%s: expected %s %q, got %s", tt.name, tt.level, tt.msg, b.String())
+		}
+		if entry["sql"] != "SELECT * FROM users" || entry["rows"] != float64(3) {
+			t.Errorf("%s: expected sql and rows, got %s", tt.name, b.String())
+		}
+		if source, _ := entry["source"].(string); !strings.Contains(source, "gormlog_test.go") {
+			t.Errorf("%s: expected source in the test, got %q", tt.name, source)
+		}
+	}
+
+	b.Reset()
+	logger.LogMode(gormlogger.Warn).Trace(ctx, time.Now(), query, nil)
+	logger.LogMode(gormlogger.Silent).Trace(ctx, time.Now(), query, errors.New("some error"))
+	if b.Len() != 0 {
+		t.Errorf("expected no entries, got %s", b.String())
+	}
+}
+
+func TestMessages(t *testing.T) {
+	var b bytes.Buffer
+	logger := gormlog.New(logze.New(logze.NewConfig(&b).WithNoDiode()))
+
+	logger.Warn(context.Background(), "record %s is deprecated", "users")
+	if !strings.Contains(b.String(), `"level":"warn"`) || !strings.Contains(b.String(), "record users is deprecated") {
+		t.Errorf("expected warn entry, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.LogMode(gormlogger.Error).Info(context.Background(), "message")
+	if b.Len() != 0 {
+		t.Errorf("expected no info entry in error mode, got %s", b.String())
+	}
+}
@@ -0,0 +1,29 @@
+package logze
+
+import "runtime"
+
+// GoroutinesFieldName is a field name for a dump of all goroutines, see [Config.WithGoroutineDump].
+var GoroutinesFieldName = "goroutines"
+
+// maxGoroutineDumpSize limits a size of a goroutine dump to keep an entry writable.
+const maxGoroutineDumpSize = 64 << 20
+
+// WithGoroutineDump returns [Config] with a dump of all goroutines added to messages of Fatal and Panic methods,
+// it is the same dump that [runtime.Stack] makes with all set to true. It is often the only evidence
+// of a deadlocked shutdown, but it is large, so it is not added to messages of other levels.
+func (c Config) WithGoroutineDump() Config {
+	c.GoroutineDump = true
+	return c
+}
+
+// goroutineDump returns stack traces of all goroutines, the buffer is grown until the dump fits in it.
+func goroutineDump() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDumpSize {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
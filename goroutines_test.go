@@ -0,0 +1,39 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestGoroutineDump(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithGoroutineDump())
+
+	logger.Error("error")
+	if strings.Contains(b.String(), logze.GoroutinesFieldName) {
+		t.Errorf("expected no goroutine dump for error, got %s", b.String())
+	}
+
+	b.Reset()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		logger.Panic("panic")
+	}()
+
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dump, _ := entry[logze.GoroutinesFieldName].(string)
+	if !strings.Contains(dump, "goroutine ") || !strings.Contains(dump, "TestGoroutineDump") {
+		t.Errorf("expected goroutine dump with the test, got %q", dump)
+	}
+}
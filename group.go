@@ -0,0 +1,59 @@
+package logze
+
+import "github.com/rs/zerolog"
+
+// WithGroup returns [Logger] that nests all subsequent fields (added with [Logger.WithFields]
+// or passed to logging methods) under a JSON object with provided name, as [log/slog.Logger.WithGroup] does:
+//
+//	logger.WithGroup("request").Info("handled", "id", id, "method", "GET")
+//	// {"level":"info","request":{"id":"..","method":"GET"},"message":"handled"}
+//
+// Calling WithGroup on a grouped logger opens a nested group. Fields added before opening a group,
+// an error and a stack trace are not nested. Group without fields is omitted. Empty name is ignored.
+func (l Logger) WithGroup(name string) Logger {
+	if name == "" {
+		return l
+	}
+	l.group = &fieldGroup{parent: l.group, name: name}
+	l.cache = newFieldsCache()
+	return l
+}
+
+// fieldGroup is a named group of fields, it is immutable, so it can be shared between copies of [Logger].
+type fieldGroup struct {
+	parent *fieldGroup
+	name   string
+	fields []any
+}
+
+// with returns a copy of the group with added fields.
+func (g *fieldGroup) with(fields []any) *fieldGroup {
+	ng := *g
+	ng.fields = append(g.fields[:len(g.fields):len(g.fields)], fields...)
+	return &ng
+}
+
+func (g *fieldGroup) empty() bool {
+	for ; g != nil; g = g.parent {
+		if len(g.fields) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// appendTo adds fields of the group and its parents with provided fields of a message nested in the group.
+func (g *fieldGroup) appendTo(ev *zerolog.Event, fields []any) *zerolog.Event {
+	if ev == nil || len(fields) == 0 && g.empty() {
+		return ev
+	}
+	return g.appendDict(ev, zerolog.Dict().Fields(g.fields).Fields(fields))
+}
+
+// appendDict adds a dict with fields of the group to the event nesting it in parents of the group.
+func (g *fieldGroup) appendDict(ev *zerolog.Event, d *zerolog.Event) *zerolog.Event {
+	for ; g.parent != nil; g = g.parent {
+		d = zerolog.Dict().Fields(g.parent.fields).Dict(g.name, d)
+	}
+	return ev.Dict(g.name, d)
+}
@@ -0,0 +1,46 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/pkg/errors"
+)
+
+func TestWithGroup(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode(), "app", "test")
+
+	request := logger.WithFields("top", 1).WithGroup("request").WithFields("id", "abc")
+	request.Info("handled", "method", "GET")
+	if !strings.Contains(b.String(), `"app":"test","top":1,"request":{"id":"abc","method":"GET"}`) {
+		t.Errorf("expected nested fields, got %s", b.String())
+	}
+
+	b.Reset()
+	request.WithGroup("user").Infof("user %s", "bob", "name", "bob")
+	if !strings.Contains(b.String(), `"request":{"id":"abc","user":{"name":"bob"}}`) || !strings.Contains(b.String(), `"message":"user bob"`) {
+		t.Errorf("expected nested groups, got %s", b.String())
+	}
+
+	b.Reset()
+	request.Err(errors.New("some error"), "failed", "code", 500)
+	if !strings.Contains(b.String(), `"error":"some error"`) || !strings.Contains(b.String(), `"request":{"id":"abc","code":500}`) {
+		t.Errorf("expected top-level error and nested fields, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.WithGroup("empty").Info("message")
+	if strings.Contains(b.String(), "empty") {
+		t.Errorf("expected empty group to be omitted, got %s", b.String())
+	}
+
+	b.Reset()
+	request.WithFields("extra", true)
+	request.Info("message")
+	if strings.Contains(b.String(), "extra") {
+		t.Errorf("expected group of parent logger to be unchanged, got %s", b.String())
+	}
+}
@@ -0,0 +1,168 @@
+// Package grpcmw provides gRPC interceptors that log every call using [logze.Logger].
+//
+// Every entry has a full method name, status code, duration and peer address. Server interceptors also put
+// a request-scoped logger with a method and a peer into a context of a handler, use [logze.FromContext] to get it.
+//
+// It is a separate module, so the core logze package doesn't depend on gRPC.
+package grpcmw
+
+import (
+	"context"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureGRPCMiddleware)
+}
+
+// DefaultMessage is a default message of entries.
+const DefaultMessage = "grpc call"
+
+// Enumerating names of fields of an entry.
+var (
+	MethodFieldName   = "method"
+	CodeFieldName     = "code"
+	DurationFieldName = "duration"
+	PeerFieldName     = "peer"
+)
+
+// Option changes a behaviour of interceptors.
+type Option func(o *options)
+
+// WithCodeLevel sets a function that returns a level of an entry by a status code of a call,
+// default value is [DefaultCodeLevel]. Return [logze.LevelDisabled] to skip an entry.
+func WithCodeLevel(f func(code codes.Code) string) Option {
+	return func(o *options) {
+		o.codeLevel = f
+	}
+}
+
+// WithMessage sets a message of entries, default value is [DefaultMessage].
+func WithMessage(msg string) Option {
+	return func(o *options) {
+		o.message = msg
+	}
+}
+
+// DefaultCodeLevel returns info level for successful calls, warn level for errors that are caused by a client
+// and error level for errors of a server.
+func DefaultCodeLevel(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return logze.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.ResourceExhausted, codes.FailedPrecondition, codes.OutOfRange:
+		return logze.LevelWarn
+	}
+	return logze.LevelError
+}
+
+// UnaryServerInterceptor returns [grpc.UnaryServerInterceptor] that logs every unary call
+// and puts a request-scoped logger into a context of a handler.
+func UnaryServerInterceptor(l logze.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		lg := l.WithFields(MethodFieldName, info.FullMethod, PeerFieldName, peerAddr(ctx))
+		resp, err := handler(lg.WithContext(ctx), req)
+		o.log(lg, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns [grpc.StreamServerInterceptor] that logs every stream when it is finished
+// and puts a request-scoped logger into a context of a stream.
+func StreamServerInterceptor(l logze.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		lg := l.WithFields(MethodFieldName, info.FullMethod, PeerFieldName, peerAddr(ss.Context()))
+		err := handler(srv, serverStream{ServerStream: ss, ctx: lg.WithContext(ss.Context())})
+		o.log(lg, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns [grpc.UnaryClientInterceptor] that logs every unary call.
+func UnaryClientInterceptor(l logze.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		var p peer.Peer
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(&p))...)
+		addr := cc.Target()
+		if p.Addr != nil {
+			addr = p.Addr.String()
+		}
+		o.log(l.WithFields(MethodFieldName, method, PeerFieldName, addr), start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns [grpc.StreamClientInterceptor] that logs every stream when it is created.
+// Only errors of creation are logged, because a client stream has no single point where it is finished.
+func StreamClientInterceptor(l logze.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		o.log(l.WithFields(MethodFieldName, method, PeerFieldName, cc.Target()), start, err)
+		return cs, err
+	}
+}
+
+type options struct {
+	codeLevel func(code codes.Code) string
+	message   string
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		codeLevel: DefaultCodeLevel,
+		message:   DefaultMessage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o options) log(l logze.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	level := o.codeLevel(code)
+	if level == logze.LevelDisabled || !l.Enabled(level) {
+		return
+	}
+	fields := []any{
+		CodeFieldName, code.String(),
+		DurationFieldName, time.Since(start),
+	}
+	if err != nil {
+		fields = append(fields, "error", err)
+	}
+	l.LogAttrs(level, o.message, fields)
+}
+
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// serverStream overrides a context of a stream to pass a request-scoped logger to a handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,110 @@
+package grpcmw_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/grpcmw"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) entries(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]any
+	sc := bufio.NewScanner(strings.NewReader(b.b.String()))
+	for sc.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// checkServer logs a message using a logger from a context of a handler.
+type checkServer struct {
+	*health.Server
+}
+
+func (s checkServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	logze.FromContext(ctx).Info("handler")
+	return s.Server.Check(ctx, req)
+}
+
+func TestInterceptors(t *testing.T) {
+	var serverLog, clientLog syncBuffer
+	serverLogger := logze.New(logze.NewConfig(&serverLog).WithNoDiode())
+	clientLogger := logze.New(logze.NewConfig(&clientLog).WithNoDiode())
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcmw.UnaryServerInterceptor(serverLogger)))
+	healthpb.RegisterHealthServer(srv, checkServer{Server: health.NewServer()})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcmw.UnaryClientInterceptor(clientLogger)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("expected error for unknown service")
+	}
+
+	const method = "/grpc.health.v1.Health/Check"
+	var serverEntries []map[string]any
+	for _, e := range serverLog.entries(t) {
+		if e["message"] != "handler" {
+			serverEntries = append(serverEntries, e)
+			continue
+		}
+		if e["method"] != method {
+			t.Errorf("expected request-scoped logger in handler context, got %v", e)
+		}
+	}
+
+	for name, entries := range map[string][]map[string]any{"server": serverEntries, "client": clientLog.entries(t)} {
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 %s entries, got %d", name, len(entries))
+		}
+		if e := entries[0]; e["level"] != "info" || e["code"] != "OK" || e["method"] != method || e["peer"] == "" {
+			t.Errorf("expected successful %s call, got %v", name, e)
+		}
+		if e := entries[1]; e["level"] != "warn" || e["code"] != "NotFound" || e["error"] == nil {
+			t.Errorf("expected failed %s call, got %v", name, e)
+		}
+	}
+}
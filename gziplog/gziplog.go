@@ -0,0 +1,160 @@
+// Package gziplog provides an [io.Writer] that streams log entries to a gzip-compressed output,
+// e.g. a file of a long-running batch job that produces gigabytes of JSON logs.
+//
+// Compressed data is flushed periodically, so entries become readable without waiting for the end of the job,
+// and a file that is appended by several runs remains a valid multi-member gzip file.
+package gziplog
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureGzip)
+}
+
+// DefaultFlushInterval is a default interval between flushes of compressed data to the underlying [io.Writer].
+const DefaultFlushInterval = 5 * time.Second
+
+// ErrClosed is returned when writing to a closed [Writer].
+var ErrClosed = errors.New("gziplog: writer is closed")
+
+// Options is using for configuring [NewWriter] and [OpenFile].
+type Options struct {
+	// Level is a compression level from [gzip.BestSpeed] to [gzip.BestCompression].
+	// Default value is 0, in that case [gzip.DefaultCompression] is used.
+	Level int
+
+	// FlushInterval is an interval between flushes of compressed data to the underlying [io.Writer].
+	// Frequent flushes make entries visible sooner but worsen compression. Default value is 5s,
+	// negative value disables periodic flushes, data is flushed by the compressor and on [Writer.Flush] or [Writer.Close].
+	FlushInterval time.Duration
+}
+
+// Writer compresses written entries with gzip and writes them to the underlying [io.Writer].
+// It is safe for concurrent use. [Writer.Close] must be called to write the gzip footer.
+type Writer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	gz     *gzip.Writer
+	closer io.Closer
+	closed bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWriter returns a new [Writer] that compresses entries to w and starts periodic flushes.
+// It returns an error if the compression level is invalid.
+func NewWriter(w io.Writer, opts Options) (*Writer, error) {
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+	gz, err := gzip.NewWriterLevel(w, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	gw := &Writer{
+		w:    w,
+		gz:   gz,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if opts.FlushInterval > 0 {
+		go gw.run(opts.FlushInterval)
+	} else {
+		close(gw.done)
+	}
+	return gw, nil
+}
+
+// OpenFile opens or creates a file with provided path for appending and returns [Writer] compressing to it.
+// Every run appends a new gzip member, it is read as a single stream by [gzip.Reader] and gunzip.
+// [Writer.Close] closes the file.
+func OpenFile(path string, opts Options) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w, err := NewWriter(f, opts)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	w.closer = f
+	return w, nil
+}
+
+// Write compresses an entry, compressed data may be buffered until the next flush.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	return w.gz.Write(p)
+}
+
+// Flush writes all compressed data to the underlying [io.Writer], so written entries can be decompressed.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	return w.gz.Flush()
+}
+
+// Close stops periodic flushes, writes the rest of compressed data and the gzip footer.
+// It closes the underlying file if the writer is created with [OpenFile], other writers are not closed.
+// It is safe to call Close several times.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.stop)
+	w.mu.Unlock()
+
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.gz.Close()
+	if w.closer != nil {
+		if cerr := w.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (w *Writer) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Errors of the underlying writer are returned by the next Write or Close
+			_ = w.Flush()
+		case <-w.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package gziplog_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/gziplog"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.b.Bytes()...)
+}
+
+func decompress(t *testing.T, data []byte) string {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(out)
+}
+
+func TestWriter(t *testing.T) {
+	var b bytes.Buffer
+	w, err := gziplog.NewWriter(&b, gziplog.Options{Level: gzip.BestCompression})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logze.New(logze.C(w).WithNoDiode())
+	for i := 0; i < 1000; i++ {
+		logger.Info("request handled", "method", "GET", "path", "/api/users", "id", i)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("expected nil error on second close, got %v", err)
+	}
+
+	out := decompress(t, b.Bytes())
+	if n := strings.Count(out, "request handled"); n != 1000 {
+		t.Errorf("expected 1000 messages, got %d", n)
+	}
+	if b.Len()*5 > len(out) {
+		t.Errorf("expected compression, got %d bytes from %d", b.Len(), len(out))
+	}
+	if _, err := w.Write([]byte("message")); err != gziplog.ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestWriterPeriodicFlush(t *testing.T) {
+	var b syncBuffer
+	w, err := gziplog.NewWriter(&b, gziplog.Options{FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("message\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(decompress(t, b.Bytes()), "message") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected message to be flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.gz")
+	for _, msg := range []string{"first\n", "second\n"} {
+		w, err := gziplog.OpenFile(path, gziplog.Options{FlushInterval: -1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := w.Write([]byte(msg)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := decompress(t, data); out != "first\nsecond\n" {
+		t.Errorf("expected both runs in the file, got %q", out)
+	}
+}
+
+func TestInvalidLevel(t *testing.T) {
+	if _, err := gziplog.NewWriter(io.Discard, gziplog.Options{Level: 100}); err == nil {
+		t.Errorf("expected error for invalid level")
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureGzip) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureGzip, logze.Features())
+	}
+}
@@ -0,0 +1,243 @@
+// Package httpmw provides a net/http middleware that writes an access log entry for every request using
+// [logze.Logger] and recovers from panics in handlers.
+//
+// Every entry has a method, path, status, number of written bytes, duration, remote address and request ID.
+// Request ID is taken from a request header or generated if it is missing, it is passed to handlers
+// in a request context (see [RequestID]) and returned to a client in the same header.
+package httpmw
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureHTTPMiddleware)
+}
+
+const (
+	// DefaultMessage is a default message of access log entries.
+	DefaultMessage = "request"
+
+	// DefaultRequestIDHeader is a default header to get request ID from and to return it to a client.
+	DefaultRequestIDHeader = "X-Request-ID"
+)
+
+// Enumerating names of fields of an access log entry.
+var (
+	MethodFieldName     = "method"
+	PathFieldName       = "path"
+	StatusFieldName     = "status"
+	BytesFieldName      = "bytes"
+	DurationFieldName   = "duration"
+	RemoteAddrFieldName = "remote_addr"
+	RequestIDFieldName  = "request_id"
+	PanicFieldName      = "panic"
+	StackFieldName      = "stack"
+)
+
+// Option changes a behaviour of [Middleware].
+type Option func(o *options)
+
+// WithLevel sets a level of access log entries, default value is [logze.LevelInfo].
+func WithLevel(level string) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithRouteLevel sets a level of access log entries of requests with a path that starts with provided prefix,
+// e.g. "debug" for "/healthz" to hide probes from production logs or "disabled" to skip them at all.
+// The longest matching prefix is used.
+func WithRouteLevel(prefix, level string) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, route{prefix: prefix, level: level})
+	}
+}
+
+// WithMessage sets a message of access log entries, default value is [DefaultMessage].
+func WithMessage(msg string) Option {
+	return func(o *options) {
+		o.message = msg
+	}
+}
+
+// WithRequestIDHeader sets a header to get request ID from and to return it to a client,
+// default value is [DefaultRequestIDHeader].
+func WithRequestIDHeader(header string) Option {
+	return func(o *options) {
+		o.requestIDHeader = header
+	}
+}
+
+// Middleware returns a function that wraps [http.Handler] with access logging and panic recovery.
+// A panic in a handler is logged in error level with a stack trace and a client gets 500 status
+// if nothing was written yet. [http.ErrAbortHandler] is not logged and is panicked again
+// to let the server abort the response.
+func Middleware(l logze.Logger, opts ...Option) func(next http.Handler) http.Handler {
+	o := options{
+		level:           logze.LevelInfo,
+		message:         DefaultMessage,
+		requestIDHeader: DefaultRequestIDHeader,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Longest prefixes go first, so the first match is the most specific one
+	sort.SliceStable(o.routes, func(i, j int) bool {
+		return len(o.routes[i].prefix) > len(o.routes[j].prefix)
+	})
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(o.requestIDHeader)
+			if id == "" {
+				id = newRequestID()
+			}
+			w.Header().Set(o.requestIDHeader, id)
+			r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				level := o.levelFor(r.URL.Path)
+				if rec != nil {
+					level = logze.LevelError
+					if !rw.wroteHeader {
+						rw.WriteHeader(http.StatusInternalServerError)
+					}
+				}
+				if level == logze.LevelDisabled || !l.Enabled(level) {
+					return
+				}
+
+				fields := []any{
+					MethodFieldName, r.Method,
+					PathFieldName, r.URL.Path,
+					StatusFieldName, rw.statusCode(),
+					BytesFieldName, rw.bytes,
+					DurationFieldName, time.Since(start),
+					RemoteAddrFieldName, r.RemoteAddr,
+					RequestIDFieldName, id,
+				}
+				if rec != nil {
+					fields = append(fields, PanicFieldName, fmt.Sprint(rec), StackFieldName, string(debug.Stack()))
+				}
+				l.LogAttrs(level, o.message, fields)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// RequestID returns an ID of a request from the context of a request handled by [Middleware]
+// and an empty string if there is no ID.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type requestIDKey struct{}
+
+type route struct {
+	prefix string
+	level  string
+}
+
+type options struct {
+	level           string
+	message         string
+	requestIDHeader string
+	routes          []route
+}
+
+func (o options) levelFor(path string) string {
+	for _, r := range o.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.level
+		}
+	}
+	return o.level
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// responseWriter records a status and a number of written bytes of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+func (w *responseWriter) statusCode() int {
+	if !w.wroteHeader {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush implements [http.Flusher] if the underlying writer implements it.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements [http.Hijacker] if the underlying writer implements it, e.g. for websockets.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("httpmw: underlying response writer is not a http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying writer for [http.ResponseController].
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
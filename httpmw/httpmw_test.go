@@ -0,0 +1,95 @@
+package httpmw_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/httpmw"
+)
+
+func TestMiddleware(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel("debug"))
+
+	var gotID string
+	handler := httpmw.Middleware(logger, httpmw.WithRouteLevel("/healthz", logze.LevelDisabled))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = httpmw.RequestID(r.Context())
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set(httpmw.DefaultRequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if gotID != "req-1" {
+		t.Errorf("expected request ID req-1 in context, got %q", gotID)
+	}
+	if id := rec.Header().Get(httpmw.DefaultRequestIDHeader); id != "req-1" {
+		t.Errorf("expected request ID req-1 in response, got %q", id)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{
+		"level":       "info",
+		"message":     httpmw.DefaultMessage,
+		"method":      "POST",
+		"path":        "/users",
+		"status":      float64(http.StatusCreated),
+		"bytes":       float64(5),
+		"request_id":  "req-1",
+		"remote_addr": req.RemoteAddr,
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Errorf("expected duration, got %s", b.String())
+	}
+
+	b.Reset()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if b.Len() != 0 {
+		t.Errorf("expected no entry for disabled route, got %s", b.String())
+	}
+	if rec.Header().Get(httpmw.DefaultRequestIDHeader) == "" {
+		t.Error("expected generated request ID")
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	handler := httpmw.Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry["level"] != "error" || entry["panic"] != "boom" || entry["status"] != float64(500) {
+		t.Errorf("expected error entry with panic, got %s", b.String())
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "TestMiddlewarePanic") {
+		t.Errorf("expected stack with the handler, got %q", stack)
+	}
+}
@@ -0,0 +1,135 @@
+package logze
+
+import "strings"
+
+// ignoreLoopLimit is a max number of messages to ignore that are checked one by one with [strings.Contains],
+// it is faster than the automaton for a couple of patterns.
+const ignoreLoopLimit = 3
+
+// ignoreMatcher checks if a message contains any of messages to ignore. It is built once when the list is set:
+// a few patterns are checked in a loop, more patterns are compiled to Aho–Corasick automaton,
+// so a message is scanned once regardless of the number of patterns.
+type ignoreMatcher struct {
+	patterns []string
+	matchAll bool
+
+	// classes maps a byte of a message to a column of the transition table, bytes that are absent
+	// in all patterns share column 0
+	classes [256]uint16
+	width   int
+	// next is a transition table of the automaton with failure links resolved, it has width columns per state
+	// and stores offsets of rows instead of numbers of states; transition to a state where any pattern ends
+	// is -1, because there is no need to continue matching after it
+	next []int32
+}
+
+func newIgnoreMatcher(patterns []string) *ignoreMatcher {
+	m := &ignoreMatcher{patterns: patterns}
+	for _, p := range patterns {
+		if p == "" {
+			// Every message contains an empty string
+			m.matchAll = true
+			return m
+		}
+	}
+	if len(patterns) > ignoreLoopLimit {
+		m.compile()
+	}
+	return m
+}
+
+// match returns true if msg contains any of the patterns.
+func (m *ignoreMatcher) match(msg string) bool {
+	switch {
+	case m.matchAll:
+		return true
+	case m.next == nil:
+		for _, p := range m.patterns {
+			if strings.Contains(msg, p) {
+				return true
+			}
+		}
+		return false
+	}
+	row := int32(0)
+	for i := 0; i < len(msg); i++ {
+		row = m.next[row+int32(m.classes[msg[i]])]
+		if row < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ignoreMatcher) compile() {
+	m.width = 1
+	for _, p := range m.patterns {
+		for i := 0; i < len(p); i++ {
+			if m.classes[p[i]] == 0 {
+				m.classes[p[i]] = uint16(m.width)
+				m.width++
+			}
+		}
+	}
+
+	// Build a trie, -1 means there is no transition yet
+	m.next = m.newState(nil)
+	final := []bool{false}
+	for _, p := range m.patterns {
+		state := 0
+		for i := 0; i < len(p); i++ {
+			idx := state*m.width + int(m.classes[p[i]])
+			if m.next[idx] < 0 {
+				m.next[idx] = int32(len(final))
+				m.next = m.newState(m.next)
+				final = append(final, false)
+			}
+			state = int(m.next[idx])
+		}
+		final[state] = true
+	}
+
+	// Resolve failure links in BFS order, so missing transitions of a state are taken
+	// from its failure state that is already resolved
+	fail := make([]int32, len(final))
+	queue := make([]int32, 0, len(final))
+	for c := 0; c < m.width; c++ {
+		if s := m.next[c]; s > 0 {
+			queue = append(queue, s)
+		} else {
+			m.next[c] = 0
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		row := int(state) * m.width
+		failRow := int(fail[state]) * m.width
+		for c := 0; c < m.width; c++ {
+			s := m.next[row+c]
+			if s < 0 {
+				m.next[row+c] = m.next[failRow+c]
+				continue
+			}
+			fail[s] = m.next[failRow+c]
+			final[s] = final[s] || final[fail[s]]
+			queue = append(queue, s)
+		}
+	}
+
+	for i, s := range m.next {
+		if final[s] {
+			m.next[i] = -1
+		} else {
+			m.next[i] = s * int32(m.width)
+		}
+	}
+}
+
+// newState appends a row of a new state without transitions to the table.
+func (m *ignoreMatcher) newState(next []int32) []int32 {
+	for c := 0; c < m.width; c++ {
+		next = append(next, -1)
+	}
+	return next
+}
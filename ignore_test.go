@@ -0,0 +1,73 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestIgnoreManyPatterns(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().
+		WithToIgnore("he", "she", "his", "hers", "GOAWAY received"))
+
+	tests := []struct {
+		msg     string
+		ignored bool
+	}{
+		{"ushers", true},
+		{"a hero", true},
+		{"this", true},
+		{"client GOAWAY received from server", true},
+		{"GOAWAY", false},
+		{"Hello", false},
+		{"shop is open", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		b.Reset()
+		logger.Info(tt.msg)
+		if logged := b.Len() > 0; logged == tt.ignored {
+			t.Errorf("expected ignored=%t for %q, got output %s", tt.ignored, tt.msg, b.String())
+		}
+	}
+}
+
+func TestIgnoreEmptyPattern(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode().
+		WithToIgnore("one", "two", "three", "four", ""))
+
+	logger.Info("anything")
+	logger.Infof("formatted %d", 1)
+	if b.Len() != 0 {
+		t.Errorf("expected empty output, got %s", b.String())
+	}
+}
+
+func TestIgnoreReload(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode()
+	logger := logze.New(cfg)
+
+	logger.Info("first message")
+	if err := logger.Reload(cfg.WithToIgnore("x", "y", "z", "first", "second")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	logger.Info("first message")
+	logger.Info("second message")
+	logger.Info("third message")
+
+	output := b.String()
+	if n := strings.Count(output, "first message"); n != 1 {
+		t.Errorf("expected 1 first message, got %d", n)
+	}
+	if strings.Contains(output, "second message") {
+		t.Errorf("expected second message to be ignored, got %s", output)
+	}
+	if !strings.Contains(output, "third message") {
+		t.Errorf("expected %s, got %s", "third message", output)
+	}
+}
@@ -0,0 +1,220 @@
+// Package batcher implements a bounded queue of entries that are sent in batches in background
+// with retries of failed requests. It is shared by sinks shipping logs over the network,
+// so they implement only encoding and sending of a batch.
+package batcher
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config is a configuration of [Batcher], all values must be positive except MaxRetries.
+type Config[T any] struct {
+	// Size is a max number of entries in one batch.
+	Size int
+	// Wait is a max time an entry waits in a batch before it is sent.
+	Wait time.Duration
+	// QueueSize is a max number of entries waiting to be sent.
+	QueueSize int
+	// MinBackoff is a delay before the first retry of a failed batch.
+	MinBackoff time.Duration
+	// MaxBackoff is a max delay between retries of a failed batch.
+	MaxBackoff time.Duration
+	// MaxRetries is a max number of retries of a failed batch.
+	MaxRetries int
+	// ErrQueueFull is passed to OnDrop when an entry is dropped because the queue is full.
+	ErrQueueFull error
+	// OnDrop is called with entries that are dropped and the reason, it can be nil.
+	OnDrop func(entries []T, err error)
+}
+
+// SendFunc sends a batch and returns entries that should be retried with the error. Entries that can't
+// be retried should be passed to [Batcher.Drop]. Error can be wrapped with [RetryAfter] to set a delay
+// before the retry, otherwise exponential backoff with full jitter is used.
+type SendFunc[T any] func(entries []T) (retry []T, err error)
+
+// Batcher collects entries in batches and sends them in background, it is safe for concurrent use.
+// [Batcher.Close] must be called to send the rest of entries.
+type Batcher[T any] struct {
+	cfg  Config[T]
+	send SendFunc[T]
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan T
+	flush  chan chan error
+	stop   chan struct{}
+	done   chan struct{}
+
+	dropped atomic.Int64
+}
+
+// New returns [Batcher] sending entries with provided function and starts its background goroutine.
+func New[T any](cfg Config[T], send SendFunc[T]) *Batcher[T] {
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = cfg.MinBackoff
+	}
+	b := &Batcher[T]{
+		cfg:   cfg,
+		send:  send,
+		queue: make(chan T, cfg.QueueSize),
+		flush: make(chan chan error),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add adds an entry to the queue, it never blocks. If the queue is full, the entry is dropped.
+// It returns [os.ErrClosed] after [Batcher.Close].
+func (b *Batcher[T]) Add(e T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return os.ErrClosed
+	}
+	select {
+	case b.queue <- e:
+	default:
+		b.Drop([]T{e}, b.cfg.ErrQueueFull)
+	}
+	return nil
+}
+
+// Flush sends entries that are added before the call and returns an error of sending.
+func (b *Batcher[T]) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case b.flush <- ch:
+		return <-ch
+	case <-b.done:
+		return nil
+	}
+}
+
+// Drop counts entries as dropped and passes them to OnDrop.
+func (b *Batcher[T]) Drop(entries []T, err error) {
+	b.dropped.Add(int64(len(entries)))
+	if b.cfg.OnDrop != nil {
+		b.cfg.OnDrop(entries, err)
+	}
+}
+
+// Dropped returns a number of entries dropped since the batcher is created.
+func (b *Batcher[T]) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+// Close stops retrying, sends the rest of entries once and stops the background goroutine.
+// It returns a number of entries dropped during closing.
+func (b *Batcher[T]) Close() int64 {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return 0
+	}
+	b.closed = true
+	close(b.queue)
+	close(b.stop)
+	b.mu.Unlock()
+
+	before := b.dropped.Load()
+	<-b.done
+	return b.dropped.Load() - before
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+
+	var batch []T
+	timer := time.NewTimer(b.cfg.Wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case e, ok := <-b.queue:
+			if !ok {
+				b.push(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= b.cfg.Size {
+				b.push(batch)
+				batch = batch[:0]
+			}
+		case <-timer.C:
+			b.push(batch)
+			batch = batch[:0]
+			timer.Reset(b.cfg.Wait)
+		case ch := <-b.flush:
+			// Entries added before Flush are in the queue already
+			for n := len(b.queue); n > 0; n-- {
+				batch = append(batch, <-b.queue)
+			}
+			ch <- b.push(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// push sends a batch retrying returned entries, they are dropped if they can't be sent.
+func (b *Batcher[T]) push(batch []T) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	backoff := b.cfg.MinBackoff
+	for retry := 0; ; retry++ {
+		pending, err := b.send(batch)
+		if err == nil || len(pending) == 0 {
+			return err
+		}
+		if retry >= b.cfg.MaxRetries {
+			b.Drop(pending, err)
+			return err
+		}
+		batch = pending
+
+		var wait time.Duration
+		var ra *retryAfterError
+		if errors.As(err, &ra) {
+			wait = ra.wait
+		} else {
+			// Full jitter spreads retries of many instances sending to the same endpoint
+			wait = time.Duration(rand.Int63n(int64(backoff)) + 1)
+			if backoff *= 2; backoff > b.cfg.MaxBackoff {
+				backoff = b.cfg.MaxBackoff
+			}
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-b.stop:
+			timer.Stop()
+			b.Drop(pending, err)
+			return err
+		}
+	}
+}
+
+// RetryAfter returns err with a delay before the retry requested by the server, e.g. with Retry-After header.
+func RetryAfter(wait time.Duration, err error) error {
+	return &retryAfterError{wait: wait, err: err}
+}
+
+type retryAfterError struct {
+	wait time.Duration
+	err  error
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
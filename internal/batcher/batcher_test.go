@@ -0,0 +1,135 @@
+package batcher_test
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2/internal/batcher"
+)
+
+var errQueueFull = errors.New("queue is full")
+
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	dropped []int
+}
+
+func (r *recorder) onDrop(entries []int, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dropped = append(r.dropped, entries...)
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+func config(r *recorder) batcher.Config[int] {
+	return batcher.Config[int]{
+		Size:         2,
+		Wait:         time.Hour,
+		QueueSize:    10,
+		MinBackoff:   time.Millisecond,
+		MaxBackoff:   time.Millisecond,
+		MaxRetries:   2,
+		ErrQueueFull: errQueueFull,
+		OnDrop:       r.onDrop,
+	}
+}
+
+func TestBatcher(t *testing.T) {
+	r := &recorder{}
+	b := batcher.New(config(r), func(entries []int) ([]int, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.batches = append(r.batches, append([]int(nil), entries...))
+		return nil, nil
+	})
+	_ = b.Add(1)
+	_ = b.Add(2)
+	// Full batch is sent without waiting
+	for i := 0; i < 100 && r.count() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := r.count(); n != 1 {
+		t.Errorf("expected 1 batch, got %d", n)
+	}
+
+	_ = b.Add(3)
+	if err := b.Flush(); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if n := b.Close(); n != 0 {
+		t.Errorf("expected 0 dropped, got %d", n)
+	}
+	if err := b.Add(4); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected %v, got %v", os.ErrClosed, err)
+	}
+
+	if len(r.batches) != 2 || len(r.batches[0]) != 2 || len(r.batches[1]) != 1 {
+		t.Errorf("expected batches of 2 and 1 entries, got %v", r.batches)
+	}
+}
+
+func TestBatcherRetry(t *testing.T) {
+	r := &recorder{}
+	var calls int
+	b := batcher.New(config(r), func(entries []int) ([]int, error) {
+		calls++
+		if calls == 1 {
+			// The first entry is rejected, the second one is retried after the delay
+			return entries[1:], batcher.RetryAfter(10*time.Millisecond, errors.New("unavailable"))
+		}
+		return nil, nil
+	})
+	_ = b.Add(1)
+	_ = b.Add(2)
+
+	start := time.Now()
+	if err := b.Flush(); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("expected retry after 10ms, got %v", d)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	b.Close()
+}
+
+func TestBatcherDrop(t *testing.T) {
+	r := &recorder{}
+	errSend := errors.New("unavailable")
+	block := make(chan struct{})
+	b := batcher.New(config(r), func(entries []int) ([]int, error) {
+		<-block
+		return entries, errSend
+	})
+	// The first batch blocks the goroutine, so the queue is filled up
+	_ = b.Add(1)
+	_ = b.Add(2)
+	for i := 0; i < 11; i++ {
+		_ = b.Add(i + 3)
+	}
+	if n := b.Dropped(); n == 0 {
+		t.Errorf("expected dropped entries, got %d", n)
+	}
+	close(block)
+
+	b.Close()
+	if n := b.Dropped(); n != 13 {
+		t.Errorf("expected 13 dropped, got %d", n)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.dropped) != 13 {
+		t.Errorf("expected 13 entries passed to OnDrop, got %d", len(r.dropped))
+	}
+}
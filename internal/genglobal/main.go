@@ -0,0 +1,251 @@
+// Command genglobal generates package-level functions of logze for every exported method of Logger
+// that has no hand-written package-level counterpart, so the global API never falls behind the Logger one.
+// It also generates a shim for v1 import path that forwards to v2 API.
+//
+// It is run using go:generate directive in global.go.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultOutput is a name of generated file.
+const DefaultOutput = "global_gen.go"
+
+func main() {
+	dir := flag.String("dir", ".", "directory of logze package")
+	out := flag.String("out", DefaultOutput, "name of output file")
+	shim := flag.String("shim", DefaultShim, "path of v1 shim relative to dir, empty to skip it")
+	flag.Parse()
+
+	src, err := Generate(*dir, *out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "genglobal:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "genglobal:", err)
+		os.Exit(1)
+	}
+	if *shim == "" {
+		return
+	}
+
+	src, err = GenerateShim(*dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "genglobal:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *shim), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "genglobal:", err)
+		os.Exit(1)
+	}
+}
+
+type method struct {
+	decl    *ast.FuncDecl
+	imports map[string]string
+}
+
+// Generate returns a source of file with package-level wrappers of Logger methods from the package in dir.
+// File with name out is ignored while parsing.
+func Generate(dir, out string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != out
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	pkg, ok := pkgs["logze"]
+	if !ok {
+		return nil, fmt.Errorf("package logze is not found in %s", dir)
+	}
+
+	funcs := make(map[string]bool)
+	var methods []method
+	for _, f := range pkg.Files {
+		imports := fileImports(f)
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || !fn.Name.IsExported() {
+				continue
+			}
+			if fn.Recv == nil {
+				funcs[fn.Name.Name] = true
+				continue
+			}
+			if recv, ok := fn.Recv.List[0].Type.(*ast.Ident); ok && recv.Name == "Logger" {
+				methods = append(methods, method{decl: fn, imports: imports})
+			}
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].decl.Pos() < methods[j].decl.Pos()
+	})
+
+	var body bytes.Buffer
+	usedImports := make(map[string]string)
+	for _, m := range methods {
+		if funcs[m.decl.Name.Name] {
+			continue
+		}
+		if err := writeWrapper(&body, fset, m, usedImports); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by genglobal; DO NOT EDIT.\n\npackage logze\n\n")
+	if len(usedImports) > 0 {
+		paths := make([]string, 0, len(usedImports))
+		for p := range usedImports {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		buf.WriteString("import (\n")
+		for _, p := range paths {
+			writeImport(&buf, p, usedImports[p])
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format: %w", err)
+	}
+	return src, nil
+}
+
+func writeWrapper(w *bytes.Buffer, fset *token.FileSet, m method, usedImports map[string]string) error {
+	fn := m.decl
+	name := fn.Name.Name
+
+	var params, args []string
+	for i, p := range fn.Type.Params.List {
+		typ, err := nodeString(fset, p.Type)
+		if err != nil {
+			return err
+		}
+		collectImports(p.Type, m.imports, usedImports)
+		names := p.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(i))}
+		}
+		for j, n := range names {
+			arg := n.Name
+			if arg == "_" {
+				// Blank parameters should be passed through, so they need a name
+				arg = "arg" + strconv.Itoa(i) + "_" + strconv.Itoa(j)
+			}
+			params = append(params, arg+" "+typ)
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+
+	var results []string
+	named := false
+	if fn.Type.Results != nil {
+		for _, f := range fn.Type.Results.List {
+			typ, err := nodeString(fset, f.Type)
+			if err != nil {
+				return err
+			}
+			collectImports(f.Type, m.imports, usedImports)
+			if len(f.Names) == 0 {
+				results = append(results, typ)
+				continue
+			}
+			named = true
+			for _, n := range f.Names {
+				results = append(results, n.Name+" "+typ)
+			}
+		}
+	}
+	result := strings.Join(results, ", ")
+	if named || len(results) > 1 {
+		result = "(" + result + ")"
+	}
+	if result != "" {
+		result = " " + result
+	}
+
+	if fn.Doc != nil {
+		for _, line := range strings.Split(strings.TrimRight(fn.Doc.Text(), "\n"), "\n") {
+			w.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+		}
+		w.WriteString("//\n")
+	}
+	fmt.Fprintf(w, "// It is a shortcut for [Logger.%s] of a global logger.\n", name)
+	fmt.Fprintf(w, "func %s(%s)%s {\n", name, strings.Join(params, ", "), result)
+
+	// Methods without results are logging ones and should skip one more frame for caller,
+	// methods with results (e.g. derived loggers) are called on a global logger itself
+	call := fmt.Sprintf("global().%s(%s)", name, strings.Join(args, ", "))
+	if len(results) > 0 {
+		call = fmt.Sprintf("return log.%s(%s)", name, strings.Join(args, ", "))
+	}
+	w.WriteString("\t" + call + "\n}\n\n")
+	return nil
+}
+
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string, len(f.Imports))
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// collectImports adds paths of packages used in the node to used map with their names in the source file.
+func collectImports(n ast.Node, imports map[string]string, used map[string]string) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if path, ok := imports[id.Name]; ok {
+				used[path] = id.Name
+			}
+		}
+		return false
+	})
+}
+
+// writeImport writes an import spec keeping a name of the package from the source file, e.g. stdlog for "log".
+func writeImport(w *bytes.Buffer, path, name string) {
+	if name != "" && name != filepath.Base(path) {
+		w.WriteString(name + " ")
+	}
+	w.WriteString(strconv.Quote(path) + "\n")
+}
+
+func nodeString(fset *token.FileSet, n ast.Node) (string, error) {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, fset, n); err != nil {
+		return "", fmt.Errorf("print node: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGeneratedIsUpToDate(t *testing.T) {
+	src, err := Generate("../..", DefaultOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.ReadFile("../../" + DefaultOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, current) {
+		t.Errorf("expected %s to be up to date, run go generate", DefaultOutput)
+	}
+}
+
+func TestShimIsUpToDate(t *testing.T) {
+	src, err := GenerateShim("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.ReadFile("../../" + DefaultShim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, current) {
+		t.Errorf("expected %s to be up to date, run go generate", DefaultShim)
+	}
+}
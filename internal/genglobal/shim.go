@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultShim is a path of generated v1 shim relative to logze package.
+const DefaultShim = "compat/logzev1/logze_gen.go"
+
+const v2Path = "github.com/maxbolgarin/logze/v2"
+
+// GenerateShim returns a source of a v1 compatibility shim: exported types of the package in dir are aliased,
+// constants and variables are copied and functions are wrapped.
+func GenerateShim(dir string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	pkg, ok := pkgs["logze"]
+	if !ok {
+		return nil, fmt.Errorf("package logze is not found in %s", dir)
+	}
+
+	var types, consts, vars []string
+	var funcs []method
+	for _, f := range pkg.Files {
+		imports := fileImports(f)
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.IsExported() {
+					funcs = append(funcs, method{decl: d, imports: imports})
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							types = append(types, spec.Name.Name)
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if !name.IsExported() {
+								continue
+							}
+							if d.Tok == token.CONST {
+								consts = append(consts, name.Name)
+							} else {
+								vars = append(vars, name.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(types)
+	sort.Strings(consts)
+	sort.Strings(vars)
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].decl.Name.Name < funcs[j].decl.Name.Name
+	})
+
+	var body bytes.Buffer
+	usedImports := map[string]string{v2Path: "v2"}
+	writeAliases(&body, "type", types, " = ")
+	writeAliases(&body, "const", consts, " = ")
+	writeAliases(&body, "var", vars, " = ")
+	for _, m := range funcs {
+		if err := writeShimFunc(&body, fset, m, usedImports); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by genglobal; DO NOT EDIT.\n\npackage logze\n\n")
+	paths := make([]string, 0, len(usedImports))
+	for p := range usedImports {
+		paths = append(paths, p)
+	}
+	// Standard library goes first
+	sort.Slice(paths, func(i, j int) bool {
+		si, sj := isStdPath(paths[i]), isStdPath(paths[j])
+		return si && !sj || si == sj && paths[i] < paths[j]
+	})
+	buf.WriteString("import (\n")
+	for i, p := range paths {
+		if i > 0 && isStdPath(paths[i-1]) && !isStdPath(p) {
+			buf.WriteString("\n")
+		}
+		if p == v2Path {
+			// Base of the path is a major version, not a package name
+			buf.WriteString("v2 " + strconv.Quote(p) + "\n")
+			continue
+		}
+		writeImport(&buf, p, usedImports[p])
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format: %w", err)
+	}
+	return src, nil
+}
+
+func writeAliases(w *bytes.Buffer, tok string, names []string, sep string) {
+	if len(names) == 0 {
+		return
+	}
+	w.WriteString(tok + " (\n")
+	for _, name := range names {
+		w.WriteString(name + sep + "v2." + name + "\n")
+	}
+	w.WriteString(")\n\n")
+}
+
+func writeShimFunc(w *bytes.Buffer, fset *token.FileSet, m method, usedImports map[string]string) error {
+	fn := m.decl
+	var params, args []string
+	for i, p := range fn.Type.Params.List {
+		typ, err := nodeString(fset, p.Type)
+		if err != nil {
+			return err
+		}
+		collectImports(p.Type, m.imports, usedImports)
+		names := p.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(i))}
+		}
+		for j, n := range names {
+			arg := n.Name
+			if arg == "_" {
+				// Blank parameters should be passed through, so they need a name
+				arg = "arg" + strconv.Itoa(i) + "_" + strconv.Itoa(j)
+			}
+			params = append(params, arg+" "+typ)
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+
+	var results []string
+	if fn.Type.Results != nil {
+		for _, f := range fn.Type.Results.List {
+			typ, err := nodeString(fset, f.Type)
+			if err != nil {
+				return err
+			}
+			collectImports(f.Type, m.imports, usedImports)
+			n := len(f.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				results = append(results, typ)
+			}
+		}
+	}
+	result := strings.Join(results, ", ")
+	if len(results) > 1 {
+		result = "(" + result + ")"
+	}
+	if result != "" {
+		result = " " + result
+	}
+
+	var typeParams []string
+	if fn.Type.TypeParams != nil {
+		for _, f := range fn.Type.TypeParams.List {
+			constraint, err := nodeString(fset, f.Type)
+			if err != nil {
+				return err
+			}
+			collectImports(f.Type, m.imports, usedImports)
+			names := make([]string, len(f.Names))
+			for i, n := range f.Names {
+				names[i] = n.Name
+			}
+			typeParams = append(typeParams, strings.Join(names, ", ")+" "+constraint)
+		}
+	}
+	typeParamList := ""
+	if len(typeParams) > 0 {
+		typeParamList = "[" + strings.Join(typeParams, ", ") + "]"
+	}
+
+	name := fn.Name.Name
+	fmt.Fprintf(w, "// %s calls [v2.%s].\n", name, name)
+	fmt.Fprintf(w, "func %s%s(%s)%s {\n\t", name, typeParamList, strings.Join(params, ", "), result)
+	if len(results) > 0 {
+		w.WriteString("return ")
+	}
+	fmt.Fprintf(w, "v2.%s(%s)\n}\n\n", name, strings.Join(args, ", "))
+	return nil
+}
+
+func isStdPath(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package journald
+
+import (
+	"errors"
+	"net"
+)
+
+func isMessageTooLarge(error) bool {
+	return false
+}
+
+func sendFile(*net.UnixConn, *net.UnixAddr, []byte) error {
+	return errors.New("passing of file descriptors is not supported")
+}
@@ -0,0 +1,32 @@
+//go:build unix
+
+package journald
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+func isMessageTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// sendFile writes an entry to an unlinked temporary file and passes its descriptor to journald,
+// it is how journald accepts entries that don't fit in a datagram.
+func sendFile(conn *net.UnixConn, addr *net.UnixAddr, msg []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "logze-journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(msg); err != nil {
+		return err
+	}
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), addr)
+	return err
+}
@@ -0,0 +1,278 @@
+// Package journald provides an [io.Writer] that sends log entries to systemd journald using its native protocol.
+//
+// Unlike capturing of stdout, every field of an entry becomes a journal field, so entries can be filtered
+// with journalctl (e.g. journalctl REQUEST_ID=42), and PRIORITY is mapped from the level of an entry:
+//
+//	w, err := journald.NewWriter(journald.WithIdentifier("billing"))
+//	if err != nil {
+//		return err
+//	}
+//	logger := logze.New(logze.NewConfig(w))
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureJournald)
+}
+
+// DefaultSocket is a default path of a socket of journald.
+const DefaultSocket = "/run/systemd/journal/socket"
+
+// ErrClosed is returned when writing to a closed [Writer].
+var ErrClosed = errors.New("journald: writer is closed")
+
+// Option changes a behaviour of [NewWriter].
+type Option func(o *options)
+
+// WithSocket sets a path of a socket of journald, default value is [DefaultSocket].
+func WithSocket(path string) Option {
+	return func(o *options) {
+		o.socket = path
+	}
+}
+
+// WithIdentifier sets SYSLOG_IDENTIFIER field of entries, default value is a name of the executable.
+func WithIdentifier(identifier string) Option {
+	return func(o *options) {
+		o.identifier = identifier
+	}
+}
+
+// WithFields adds fields to every entry, e.g. a version of the application. Names are converted
+// as names of fields of entries.
+func WithFields(fields map[string]string) Option {
+	return func(o *options) {
+		for k, v := range fields {
+			o.fields = append(o.fields, field{name: fieldName(k), value: v})
+		}
+		sort.Slice(o.fields, func(i, j int) bool { return o.fields[i].name < o.fields[j].name })
+	}
+}
+
+type options struct {
+	socket     string
+	identifier string
+	fields     []field
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+// Enabled returns true if the socket of journald exists, e.g. to choose between journald and stderr at startup.
+func Enabled() bool {
+	_, err := os.Stat(DefaultSocket)
+	return err == nil
+}
+
+// Writer sends JSON entries to journald. The message and the level of an entry become MESSAGE and PRIORITY,
+// other fields become journal fields with upper case names, where every character except letters and digits
+// is replaced with '_' (e.g. "request_id" becomes REQUEST_ID). Nested objects and arrays are sent as JSON.
+// It is safe for concurrent use.
+type Writer struct {
+	opts options
+
+	mu     sync.Mutex
+	conn   *net.UnixConn
+	addr   *net.UnixAddr
+	closed bool
+}
+
+// NewWriter returns [Writer] connected to the socket of journald.
+func NewWriter(opts ...Option) (*Writer, error) {
+	o := options{
+		socket:     DefaultSocket,
+		identifier: filepath.Base(os.Args[0]),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if _, err := os.Stat(o.socket); err != nil {
+		return nil, fmt.Errorf("journald: %w", err)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("journald: %w", err)
+	}
+	return &Writer{
+		opts: o,
+		conn: conn,
+		addr: &net.UnixAddr{Name: o.socket, Net: "unixgram"},
+	}, nil
+}
+
+// Write sends an entry with PRIORITY of its level field, entries without level are sent with info priority.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// ParsesEntries marks the writer as [logze.EntryParser], so it gets entries with standard field names.
+func (w *Writer) ParsesEntries() {}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg, err := w.encode(level, p)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if _, _, err := w.conn.WriteMsgUnix(msg, nil, w.addr); err != nil {
+		if !isMessageTooLarge(err) {
+			return 0, fmt.Errorf("journald: %w", err)
+		}
+		// Big entries (e.g. with long stack traces) are passed to journald in a file descriptor
+		if err := sendFile(w.conn, w.addr, msg); err != nil {
+			return 0, fmt.Errorf("journald: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+// Close closes the connection to journald.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.conn.Close()
+}
+
+// encode returns an entry in the native journal protocol.
+func (w *Writer) encode(level zerolog.Level, p []byte) ([]byte, error) {
+	fields := make(map[string]any)
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("journald: decode entry: %w", err)
+	}
+
+	if level == zerolog.NoLevel {
+		if s, ok := fields[zerolog.LevelFieldName].(string); ok {
+			if l, err := zerolog.ParseLevel(s); err == nil {
+				level = l
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	msg, _ := fields[zerolog.MessageFieldName].(string)
+	appendField(&b, "MESSAGE", msg)
+	appendField(&b, "PRIORITY", strconv.Itoa(priority(level)))
+	appendField(&b, "SYSLOG_IDENTIFIER", w.opts.identifier)
+	for _, f := range w.opts.fields {
+		appendField(&b, f.name, f.value)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != zerolog.MessageFieldName && k != zerolog.LevelFieldName {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendField(&b, fieldName(k), fieldValue(fields[k]))
+	}
+	return b.Bytes(), nil
+}
+
+// appendField appends a field in the native journal protocol, values with newlines are length-prefixed.
+func appendField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b.Write(size[:])
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// fieldName converts a name of a field of an entry to a valid name of a journal field: upper case letters,
+// digits and '_', it can't start with '_' or a digit.
+func fieldName(name string) string {
+	out := make([]byte, 0, len(name)+1)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			out = append(out, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			out = append(out, c)
+		default:
+			out = append(out, '_')
+		}
+	}
+	if len(out) == 0 || out[0] == '_' || out[0] >= '0' && out[0] <= '9' {
+		// Fields starting with '_' are trusted fields of journald, they are set only by journald itself
+		out = append([]byte("F_"), out...)
+	}
+	return string(out)
+}
+
+func fieldValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// priority maps a level to a syslog priority that is used by journald.
+func priority(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 7
+	case zerolog.WarnLevel:
+		return 4
+	case zerolog.ErrorLevel:
+		return 3
+	case zerolog.FatalLevel:
+		return 0
+	case zerolog.PanicLevel:
+		return 1
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,170 @@
+//go:build unix
+
+package journald_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/journald"
+)
+
+// listenJournal starts a fake journald and returns a function that reads fields of the next entry.
+func listenJournal(t *testing.T) (string, func() map[string]string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	buf := make([]byte, 1<<20)
+	oob := make([]byte, 1024)
+	return path, func() map[string]string {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data := buf[:n]
+		if oobn > 0 {
+			msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fds, err := syscall.ParseUnixRights(&msgs[0])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			f := os.NewFile(uintptr(fds[0]), "entry")
+			defer f.Close()
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if data, err = io.ReadAll(f); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return parseEntry(t, data)
+	}
+}
+
+func parseEntry(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	out := make(map[string]string)
+	for len(data) > 0 {
+		i := bytes.IndexAny(data, "=\n")
+		if i < 0 {
+			t.Fatalf("invalid entry: %q", data)
+		}
+		name := string(data[:i])
+		if data[i] == '=' {
+			end := bytes.IndexByte(data[i:], '\n')
+			out[name] = string(data[i+1 : i+end])
+			data = data[i+end+1:]
+			continue
+		}
+		size := int(binary.LittleEndian.Uint64(data[i+1 : i+9]))
+		out[name] = string(data[i+9 : i+9+size])
+		data = data[i+9+size+1:]
+	}
+	return out
+}
+
+func TestWriter(t *testing.T) {
+	path, read := listenJournal(t)
+	w, err := journald.NewWriter(journald.WithSocket(path), journald.WithIdentifier("billing"),
+		journald.WithFields(map[string]string{"version": "1.2.3"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+	defer logger.Close()
+
+	logger.Warn("slow request", "request-id", 42, "user", map[string]any{"id": 1}, "text", "a\nb")
+	fields := read()
+
+	expected := map[string]string{
+		"MESSAGE":           "slow request",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "billing",
+		"VERSION":           "1.2.3",
+		"REQUEST_ID":        "42",
+		"USER":              `{"id":1}`,
+		"TEXT":              "a\nb",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("expected %s=%q, got %q", k, v, fields[k])
+		}
+	}
+	if _, ok := fields["LEVEL"]; ok {
+		t.Errorf("expected level to be sent as PRIORITY, got %v", fields)
+	}
+}
+
+func TestWriterWithDiode(t *testing.T) {
+	path, read := listenJournal(t)
+	w, err := journald.NewWriter(journald.WithSocket(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logze.New(logze.NewConfig(w))
+	defer logger.Close()
+
+	logger.Err(io.EOF, "cannot read")
+	if fields := read(); fields["PRIORITY"] != "3" || fields["ERROR"] != "EOF" {
+		t.Errorf("expected error entry, got %v", fields)
+	}
+}
+
+func TestWriterLargeEntry(t *testing.T) {
+	path, read := listenJournal(t)
+	w, err := journald.NewWriter(journald.WithSocket(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	large := strings.Repeat("x", 4<<20)
+	if _, err := w.Write([]byte(`{"level":"info","message":"large","data":"` + large + `"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields := read(); fields["DATA"] != large {
+		t.Errorf("expected large field, got %d bytes", len(fields["DATA"]))
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	path, _ := listenJournal(t)
+	w, err := journald.NewWriter(journald.WithSocket(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte(`{"message":"message"}`)); err != journald.ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if _, err := journald.NewWriter(journald.WithSocket(filepath.Join(t.TempDir(), "missing"))); err == nil {
+		t.Errorf("expected error for missing socket")
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureJournald) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureJournald, logze.Features())
+	}
+}
@@ -0,0 +1,168 @@
+// Package kafka provides an [io.Writer] that publishes every log entry as a message to a Kafka topic,
+// for pipelines that consume logs from Kafka.
+//
+// The package doesn't depend on a Kafka client, a producer of any client is plugged in with [Producer].
+// For example, with franz-go:
+//
+//	w := kafka.New(kafka.ProducerFunc(func(ctx context.Context, msg kafka.Message) error {
+//		rec := &kgo.Record{Topic: msg.Topic, Key: msg.Key, Value: msg.Value}
+//		for _, h := range msg.Headers {
+//			rec.Headers = append(rec.Headers, kgo.RecordHeader{Key: h.Key, Value: h.Value})
+//		}
+//		client.Produce(ctx, rec, nil)
+//		return nil
+//	}), "logs", kafka.WithKeyField("request_id"))
+//	logger := logze.New(logze.NewConfig(w))
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureKafka)
+}
+
+// DefaultTimeout is a default timeout of publishing one message.
+const DefaultTimeout = 5 * time.Second
+
+// ServiceFieldName is a name of a field of entries with a name of a service, it is added to headers by default.
+var ServiceFieldName = "service"
+
+// Message is a message that is published for an entry.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []Header
+}
+
+// Header is a header of [Message].
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Producer publishes messages to Kafka, it is implemented by an adapter of a Kafka client.
+// It may publish a message asynchronously, in that case it should implement Flush(ctx) error.
+type Producer interface {
+	Produce(ctx context.Context, msg Message) error
+}
+
+// ProducerFunc is an adapter to use a function as [Producer].
+type ProducerFunc func(ctx context.Context, msg Message) error
+
+// Produce calls f(ctx, msg).
+func (f ProducerFunc) Produce(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithKeyField sets a name of a field of entries which value is a key of messages, e.g. "request_id"
+// to keep entries of a request in one partition. Default value is empty, messages have no key.
+func WithKeyField(field string) Option {
+	return func(o *options) {
+		o.keyField = field
+	}
+}
+
+// WithHeaderFields sets names of fields of entries which values are added to headers of messages,
+// default value is level and [ServiceFieldName]. Missing fields are skipped.
+func WithHeaderFields(fields ...string) Option {
+	return func(o *options) {
+		o.headerFields = fields
+	}
+}
+
+// WithTimeout sets a timeout of publishing one message, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+type options struct {
+	keyField     string
+	headerFields []string
+	timeout      time.Duration
+}
+
+// Writer publishes every written entry as a message with the entry as a value. It is safe for concurrent use
+// if the producer is. Errors of the producer are returned, so they are counted in errors of [logze.Logger.Close].
+type Writer struct {
+	p     Producer
+	topic string
+	o     options
+}
+
+// New returns [Writer] that publishes entries to provided topic using the producer.
+func New(p Producer, topic string, opts ...Option) *Writer {
+	o := options{
+		headerFields: []string{zerolog.LevelFieldName, ServiceFieldName},
+		timeout:      DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+	return &Writer{p: p, topic: topic, o: o}
+}
+
+// Write publishes a copy of an entry, the trailing newline is removed.
+func (w *Writer) Write(p []byte) (int, error) {
+	value := p
+	if n := len(value); n > 0 && value[n-1] == '\n' {
+		value = value[:n-1]
+	}
+	msg := Message{
+		Topic: w.topic,
+		Value: append([]byte(nil), value...),
+	}
+	if w.o.keyField != "" || len(w.o.headerFields) > 0 {
+		// Entries of the logger are valid JSON, other data is published without a key and headers
+		e, _ := logze.ParseEntry(value)
+		if v, ok := e.FieldString(w.o.keyField); ok {
+			msg.Key = []byte(v)
+		}
+		for _, name := range w.o.headerFields {
+			if v, ok := e.FieldString(name); ok {
+				msg.Headers = append(msg.Headers, Header{Key: name, Value: []byte(v)})
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := w.p.Produce(ctx, msg); err != nil {
+		return 0, fmt.Errorf("kafka: %w", err)
+	}
+	return len(p), nil
+}
+
+// Flush waits for messages published asynchronously if the producer implements Flush(ctx) error.
+func (w *Writer) Flush() error {
+	f, ok := w.p.(interface{ Flush(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := f.Flush(ctx); err != nil {
+		return fmt.Errorf("kafka: %w", err)
+	}
+	return nil
+}
+
+// Name returns a name of the writer for errors of [logze.Logger.Close].
+func (w *Writer) Name() string {
+	return "kafka " + w.topic
+}
@@ -0,0 +1,102 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/kafka"
+)
+
+type fakeProducer struct {
+	mu      sync.Mutex
+	msgs    []kafka.Message
+	err     error
+	flushed bool
+}
+
+func (p *fakeProducer) Produce(_ context.Context, msg kafka.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *fakeProducer) Flush(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flushed = true
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	p := &fakeProducer{}
+	w := kafka.New(p, "logs", kafka.WithKeyField("request_id"))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode(), "service", "billing")
+
+	logger.Warn("slow request", "request_id", 42)
+	logger.Info("no key")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(p.msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(p.msgs))
+	}
+	msg := p.msgs[0]
+	if msg.Topic != "logs" || string(msg.Key) != "42" {
+		t.Errorf("expected topic logs and key 42, got %s and %s", msg.Topic, msg.Key)
+	}
+	if !strings.Contains(string(msg.Value), `"message":"slow request"`) || strings.HasSuffix(string(msg.Value), "\n") {
+		t.Errorf("expected entry without newline, got %q", msg.Value)
+	}
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if headers["level"] != "warn" || headers["service"] != "billing" {
+		t.Errorf("expected level and service headers, got %v", headers)
+	}
+	if p.msgs[1].Key != nil {
+		t.Errorf("expected no key, got %s", p.msgs[1].Key)
+	}
+	if !p.flushed {
+		t.Errorf("expected producer to be flushed on close")
+	}
+}
+
+func TestWriterError(t *testing.T) {
+	p := &fakeProducer{err: errors.New("broker is unavailable")}
+	logger := logze.New(logze.NewConfig(kafka.New(p, "logs")).WithNoDiode())
+
+	logger.Info("message")
+	if err := logger.Close(); err == nil || !strings.Contains(err.Error(), "kafka logs") {
+		t.Errorf("expected lost entry error, got %v", err)
+	}
+}
+
+func TestProducerFunc(t *testing.T) {
+	var got kafka.Message
+	w := kafka.New(kafka.ProducerFunc(func(_ context.Context, msg kafka.Message) error {
+		got = msg
+		return nil
+	}), "logs", kafka.WithHeaderFields())
+	if _, err := w.Write([]byte("plain text\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got.Value) != "plain text" || len(got.Headers) != 0 {
+		t.Errorf("expected plain text without headers, got %+v", got)
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureKafka) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureKafka, logze.Features())
+	}
+}
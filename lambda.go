@@ -0,0 +1,87 @@
+package logze
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Names of fields added by [Lambda] to entries of an invocation.
+var (
+	LambdaRequestIDFieldName = "request_id"
+	LambdaColdStartFieldName = "cold_start"
+)
+
+// Lambda is a [Logger] for AWS Lambda and other serverless runtimes that freeze a process between invocations.
+// It writes JSON to stdout without diode, so there are no background goroutines and no entries are left
+// in buffers when an invocation ends. Use [NewLambda] to create it and [WrapLambda] to wrap a handler:
+//
+//	log := logze.NewLambda()
+//	lambda.Start(logze.WrapLambda(log, func(ctx context.Context, req Request) (Response, error) {
+//		logze.FromContext(ctx).Info("handling request")
+//		...
+//	}))
+type Lambda struct {
+	Logger
+
+	// RequestID returns an ID of an invocation from its context, default value is nil and entries have
+	// no request ID. Set it using lambdacontext package of github.com/aws/aws-lambda-go:
+	//
+	//	log.RequestID = func(ctx context.Context) string {
+	//		if lc, ok := lambdacontext.FromContext(ctx); ok {
+	//			return lc.AwsRequestID
+	//		}
+	//		return ""
+	//	}
+	RequestID func(ctx context.Context) string
+
+	started atomic.Bool
+}
+
+// NewLambda returns a new [Lambda] with JSON logging to stdout without diode. Level is taken from
+// AWS_LAMBDA_LOG_LEVEL environment variable (info by default), function and function_version fields
+// are added from AWS_LAMBDA_FUNCTION_NAME and AWS_LAMBDA_FUNCTION_VERSION environment variables.
+func NewLambda(fields ...any) *Lambda {
+	cfg := NewConfig(os.Stdout).WithNoDiode()
+	if level := os.Getenv("AWS_LAMBDA_LOG_LEVEL"); level != "" {
+		cfg = cfg.WithLevel(strings.ToLower(level))
+	}
+	if name := os.Getenv("AWS_LAMBDA_FUNCTION_NAME"); name != "" {
+		fields = append(fields, "function", name)
+	}
+	if version := os.Getenv("AWS_LAMBDA_FUNCTION_VERSION"); version != "" {
+		fields = append(fields, "function_version", version)
+	}
+	return &Lambda{Logger: New(cfg, fields...)}
+}
+
+// Start returns a logger of an invocation with request ID and cold start fields and ctx with this logger,
+// so handlers can get it with [FromContext]. Cold start field is true only for the first invocation of a process.
+// Call [Logger.Flush] at the end of the invocation or use [WrapLambda] that does it.
+func (l *Lambda) Start(ctx context.Context) (context.Context, Logger) {
+	fields := []any{LambdaColdStartFieldName, !l.started.Swap(true)}
+	if l.RequestID != nil {
+		if id := l.RequestID(ctx); id != "" {
+			fields = append(fields, LambdaRequestIDFieldName, id)
+		}
+	}
+	logger := l.Logger.WithFields(fields...)
+	return logger.WithContext(ctx), logger
+}
+
+// WrapLambda returns a handler that calls h with a context that has a logger of an invocation (see [Lambda.Start])
+// and flushes writers of the logger when h returns or panics, before the runtime freezes the process.
+// Errors of flushing are printed to stderr.
+func WrapLambda[In, Out any](l *Lambda, h func(ctx context.Context, in In) (Out, error)) func(ctx context.Context, in In) (Out, error) {
+	return func(ctx context.Context, in In) (Out, error) {
+		ctx, _ = l.Start(ctx)
+		defer func() {
+			if err := l.Flush(); err != nil {
+				fmt.Fprintln(os.Stderr, "ERR: logze: cannot flush writers: "+err.Error())
+			}
+		}()
+		return h(ctx, in)
+	}
+}
@@ -0,0 +1,106 @@
+package logze_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+type lambdaContext struct {
+	AwsRequestID       string
+	InvokedFunctionArn string
+}
+
+type lambdaContextKey struct{}
+
+func TestLambda(t *testing.T) {
+	var b bytes.Buffer
+	l := logze.NewLambda()
+	l.Logger = logze.New(logze.NewConfig(&b).WithNoDiode())
+	l.RequestID = func(ctx context.Context) string {
+		if lc, ok := ctx.Value(lambdaContextKey{}).(*lambdaContext); ok {
+			return lc.AwsRequestID
+		}
+		return ""
+	}
+
+	handler := logze.WrapLambda(l, func(ctx context.Context, in string) (string, error) {
+		logze.FromContext(ctx).Info("handled", "in", in)
+		return in + "!", nil
+	})
+
+	base, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx := context.WithValue(base, lambdaContextKey{}, &lambdaContext{AwsRequestID: "req-1"})
+	ctx, cancel = context.WithCancel(context.WithValue(ctx, "other", "value"))
+	defer cancel()
+
+	out, err := handler(ctx, "ping")
+	if err != nil || out != "ping!" {
+		t.Errorf("expected ping!, got %q, %v", out, err)
+	}
+	if _, err := handler(context.Background(), "pong"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", b.String())
+	}
+	if !strings.Contains(lines[0], `"request_id":"req-1"`) || !strings.Contains(lines[0], `"cold_start":true`) {
+		t.Errorf("expected request ID and cold start, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") || !strings.Contains(lines[1], `"cold_start":false`) {
+		t.Errorf("expected no request ID and warm start, got %s", lines[1])
+	}
+}
+
+func TestLambdaRequestID(t *testing.T) {
+	var b bytes.Buffer
+	l := logze.NewLambda()
+	l.Logger = logze.New(logze.NewConfig(&b).WithNoDiode())
+	l.RequestID = func(ctx context.Context) string { return "custom" }
+
+	_, logger := l.Start(context.Background())
+	logger.Info("message")
+	if !strings.Contains(b.String(), `"request_id":"custom"`) {
+		t.Errorf("expected custom request ID, got %s", b.String())
+	}
+}
+
+type flushWriter struct {
+	bytes.Buffer
+	flushed int
+	err     error
+}
+
+func (w *flushWriter) Flush() error {
+	w.flushed++
+	return w.err
+}
+
+func TestLoggerFlush(t *testing.T) {
+	w := &flushWriter{}
+	logger := logze.New(logze.NewConfig(w).WithNoDiode().WithFieldNames(logze.FieldNames{Message: "msg"}))
+
+	logger.Info("message")
+	if err := logger.Flush(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if w.flushed != 1 {
+		t.Errorf("expected 1 flush of wrapped writer, got %d", w.flushed)
+	}
+
+	w.err = errors.New("flush failed")
+	if err := logger.Flush(); err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("expected flush error, got %v", err)
+	}
+	if err := logze.Nop().Flush(); err != nil {
+		t.Errorf("expected nil for nop logger, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package logze
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// LevelHandler returns an [http.Handler] to get and change the level of a global logger at runtime.
+// It can be mounted on an admin mux:
+//
+//	mux.Handle("/log/level", logze.LevelHandler())
+//
+// GET request returns the current level: {"level":"info"}.
+// PUT request changes the level using JSON body {"level":"debug"} or form value level=debug
+// and returns the new level.
+//
+// Levels of named loggers (see [Logger.Named]) are managed with logger query parameter:
+// GET /log/level?logger=db returns {"logger":"db","level":"debug"} (level is omitted if it is not set by name),
+// PUT sets the level with [SetNamedLevel] and DELETE resets it with [ResetNamedLevel].
+// Names of loggers that are not created with [Logger.Named] or [SetNamedLevel] are rejected with 404 status,
+// so requests cannot fill the registry of names.
+func LevelHandler() http.Handler {
+	return levelHandler{getLogger: Default}
+}
+
+// LevelHandler returns an [http.Handler] to get and change the level of the logger at runtime,
+// see [LevelHandler] for details.
+func (l Logger) LevelHandler() http.Handler {
+	return levelHandler{getLogger: func() Logger { return l }}
+}
+
+type levelPayload struct {
+	Logger string `json:"logger,omitempty"`
+	Level  string `json:"level,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type levelHandler struct {
+	getLogger func() Logger
+}
+
+func (h levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if name := r.URL.Query().Get("logger"); name != "" {
+		serveNamedLevel(w, r, name)
+		return
+	}
+	logger := h.getLogger()
+
+	switch r.Method {
+	case http.MethodGet:
+		writeLevelPayload(w, http.StatusOK, levelPayload{Level: logger.GetLevel()})
+
+	case http.MethodPut:
+		level, err := decodeLevel(r)
+		if err != nil {
+			writeLevelPayload(w, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		if err := logger.SetLevel(level); err != nil {
+			writeLevelPayload(w, http.StatusBadRequest, levelPayload{Error: err.Error()})
+			return
+		}
+		writeLevelPayload(w, http.StatusOK, levelPayload{Level: logger.GetLevel()})
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelPayload(w, http.StatusMethodNotAllowed, levelPayload{Error: "only GET and PUT are supported"})
+	}
+}
+
+func serveNamedLevel(w http.ResponseWriter, r *http.Request, name string) {
+	if !namedExists(name) {
+		writeLevelPayload(w, http.StatusNotFound, levelPayload{Logger: name, Error: "unknown logger"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeLevelPayload(w, http.StatusOK, levelPayload{Logger: name, Level: GetNamedLevel(name)})
+
+	case http.MethodPut:
+		level, err := decodeLevel(r)
+		if err != nil {
+			writeLevelPayload(w, http.StatusBadRequest, levelPayload{Logger: name, Error: err.Error()})
+			return
+		}
+		if err := SetNamedLevel(name, level); err != nil {
+			writeLevelPayload(w, http.StatusBadRequest, levelPayload{Logger: name, Error: err.Error()})
+			return
+		}
+		writeLevelPayload(w, http.StatusOK, levelPayload{Logger: name, Level: GetNamedLevel(name)})
+
+	case http.MethodDelete:
+		ResetNamedLevel(name)
+		writeLevelPayload(w, http.StatusOK, levelPayload{Logger: name})
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeLevelPayload(w, http.StatusMethodNotAllowed, levelPayload{Logger: name, Error: "only GET, PUT and DELETE are supported"})
+	}
+}
+
+func decodeLevel(r *http.Request) (string, error) {
+	// Content type can have parameters, e.g. "application/x-www-form-urlencoded; charset=UTF-8"
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		if level := r.FormValue("level"); level != "" {
+			return level, nil
+		}
+		return "", fmt.Errorf("level is not provided")
+	}
+
+	var p levelPayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return "", fmt.Errorf("decode request: %w", err)
+	}
+	if p.Level == "" {
+		return "", fmt.Errorf("level is not provided")
+	}
+	return p.Level, nil
+}
+
+func writeLevelPayload(w http.ResponseWriter, code int, p levelPayload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(p)
+}
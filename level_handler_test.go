@@ -0,0 +1,103 @@
+package logze_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestLevelHandler(t *testing.T) {
+	var b bytes.Buffer
+	setupGlobalLogger(&b, logze.LevelInfo)
+	h := logze.LevelHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"info"}` {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`)))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"debug"}` {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if logze.GetLevel() != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, logze.GetLevel())
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(url.Values{"level": {"warn"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || logze.GetLevel() != logze.LevelWarn {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/", strings.NewReader(url.Values{"level": {"error"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || logze.GetLevel() != logze.LevelError {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLevelHandlerErrors(t *testing.T) {
+	logger := logze.New(logze.NewConfig())
+	h := logger.LevelHandler()
+
+	for _, c := range []struct {
+		method string
+		body   string
+		code   int
+	}{
+		{http.MethodPut, `{"level":"verbose"}`, http.StatusBadRequest},
+		{http.MethodPut, `{}`, http.StatusBadRequest},
+		{http.MethodPut, `level`, http.StatusBadRequest},
+		{http.MethodPost, `{"level":"debug"}`, http.StatusMethodNotAllowed},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(c.method, "/", strings.NewReader(c.body)))
+		if rec.Code != c.code || !strings.Contains(rec.Body.String(), "error") {
+			t.Errorf("%s %s: unexpected response: %d %s", c.method, c.body, rec.Code, rec.Body.String())
+		}
+	}
+	if logger.GetLevel() != logze.LevelInfo {
+		t.Errorf("expected %s, got %s", logze.LevelInfo, logger.GetLevel())
+	}
+}
+
+func TestLevelHandlerNamed(t *testing.T) {
+	h := logze.LevelHandler()
+	logze.Named("handler-db")
+	defer logze.ResetNamedLevel("handler-db")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/?logger=handler-db", strings.NewReader(`{"level":"trace"}`)))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"logger":"handler-db","level":"trace"}` {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if logze.GetNamedLevel("handler-db") != logze.LevelTrace {
+		t.Errorf("expected %s, got %s", logze.LevelTrace, logze.GetNamedLevel("handler-db"))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/?logger=handler-db", nil))
+	if rec.Code != http.StatusOK || logze.GetNamedLevel("handler-db") != "" {
+		t.Errorf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/?logger=handler-unknown", strings.NewReader(`{"level":"debug"}`)))
+		if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "unknown logger") {
+			t.Errorf("%s: unexpected response: %d %s", method, rec.Code, rec.Body.String())
+		}
+	}
+}
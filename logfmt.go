@@ -0,0 +1,165 @@
+package logze
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"unicode"
+
+	"github.com/rs/zerolog"
+)
+
+// LogfmtMessageFieldName is a name of a message field in logfmt output.
+var LogfmtMessageFieldName = "msg"
+
+// WithLogfmt returns [Config] with a configurated output to stderr in logfmt format:
+//
+//	time=2024-05-01T12:04:05Z level=info msg="request handled" status=200 path=/users
+//
+// Use [NewLogfmtWriter] to write logfmt to another destination.
+func (c Config) WithLogfmt() Config {
+	return c.WithWriter(NewLogfmtWriter(os.Stderr))
+}
+
+// LogfmtWriter converts JSON entries to logfmt lines and writes them to the underlying writer.
+// Time, level and message go first, other fields follow in the order of the entry.
+// Objects and arrays (e.g. stack traces) are written as quoted JSON, data that is not a JSON object is written as is.
+//
+// Standard fields are not renamed by [Config.WithFieldNames], logfmt has its own conventional names.
+type LogfmtWriter struct {
+	w io.Writer
+}
+
+// NewLogfmtWriter returns [LogfmtWriter] that writes logfmt lines to w.
+func NewLogfmtWriter(w io.Writer) *LogfmtWriter {
+	return &LogfmtWriter{w: w}
+}
+
+// Write converts an entry to logfmt and writes it.
+func (w *LogfmtWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(toLogfmt(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *LogfmtWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	if _, err := lw.WriteLevel(level, toLogfmt(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jsonField is a top level field of a JSON entry with its raw value.
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// splitFields returns top level fields of a JSON entry in their order, it returns false if p is not a JSON object.
+func splitFields(p []byte) ([]jsonField, bool) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	fields := make([]jsonField, 0, 8)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		fields = append(fields, jsonField{key: key, value: value})
+	}
+	return fields, true
+}
+
+// toLogfmt converts a JSON entry to a logfmt line, it returns p if it is not a JSON object.
+func toLogfmt(p []byte) []byte {
+	fields, ok := splitFields(p)
+	if !ok {
+		return p
+	}
+	var first [3]*jsonField
+	for i := range fields {
+		switch fields[i].key {
+		case zerolog.TimestampFieldName:
+			first[0] = &fields[i]
+		case zerolog.LevelFieldName:
+			first[1] = &fields[i]
+		case zerolog.MessageFieldName:
+			first[2] = &fields[i]
+		}
+	}
+
+	out := make([]byte, 0, len(p))
+	for _, f := range first {
+		if f == nil {
+			continue
+		}
+		key := f.key
+		if key == zerolog.MessageFieldName {
+			key = LogfmtMessageFieldName
+		}
+		out = appendLogfmtField(out, key, f.value)
+	}
+	for i := range fields {
+		if f := &fields[i]; f != first[0] && f != first[1] && f != first[2] {
+			out = appendLogfmtField(out, f.key, f.value)
+		}
+	}
+	return append(out, '\n')
+}
+
+func appendLogfmtField(dst []byte, key string, value json.RawMessage) []byte {
+	if len(dst) > 0 {
+		dst = append(dst, ' ')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		dst = append(dst, string(r)...)
+	}
+	dst = append(dst, '=')
+
+	switch value[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return append(dst, value...)
+		}
+		return appendLogfmtValue(dst, s)
+	case '{', '[':
+		var b bytes.Buffer
+		if err := json.Compact(&b, value); err != nil {
+			return appendLogfmtValue(dst, string(value))
+		}
+		return appendLogfmtValue(dst, b.String())
+	}
+	// Numbers, booleans and null
+	return append(dst, value...)
+}
+
+func appendLogfmtValue(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, `""`...)
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.AppendQuote(dst, s)
+		}
+	}
+	return append(dst, s...)
+}
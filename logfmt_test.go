@@ -0,0 +1,86 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestLogfmtWriter(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(logze.NewLogfmtWriter(&b)).WithNoDiode().WithLevel(logze.LevelDebug))
+
+	logger.Info("request handled", "status", 200, "path", "/users", "ok", true)
+	line := b.String()
+	if !strings.HasPrefix(line, "time=") {
+		t.Errorf("expected time first, got %q", line)
+	}
+	if !strings.Contains(line, ` level=info msg="request handled" status=200 path=/users ok=true`+"\n") {
+		t.Errorf("expected logfmt fields in order, got %q", line)
+	}
+
+	b.Reset()
+	logger.Err(errors.New(`bad "quote"`), "failed", "empty", "", "user", map[string]any{"id": 1}, "a key", "x=y")
+	line = b.String()
+	for _, expected := range []string{
+		` level=error `,
+		` msg=failed `,
+		`error="bad \"quote\""`,
+		` empty=""`,
+		` user="{\"id\":1}"`,
+		` a_key="x=y"`,
+	} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("expected %q in %q", expected, line)
+		}
+	}
+
+	b.Reset()
+	w := logze.NewLogfmtWriter(&b)
+	if _, err := w.Write([]byte("plain text\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "plain text\n" {
+		t.Errorf("expected plain text as is, got %q", b.String())
+	}
+}
+
+func TestLogfmtFieldNames(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(logze.NewLogfmtWriter(&b)).WithNoDiode().
+		WithFieldNames(logze.FieldNames{Message: "text"}))
+	logger.Info("hello")
+	if !strings.Contains(b.String(), ` msg=hello`) {
+		t.Errorf("expected logfmt names, got %q", b.String())
+	}
+}
+
+func TestLogfmtFlag(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg := logze.NewConfig().WithNoDiode()
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-log-format", logze.FormatLogfmt, "-log-file", logPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("started")
+	logger.Close()
+
+	output, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "level=info msg=started") {
+		t.Errorf("expected logfmt output, got %q", output)
+	}
+}
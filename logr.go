@@ -0,0 +1,70 @@
+package logze
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/rs/zerolog"
+)
+
+// NewLogrSink returns [logr.LogSink] that writes through the logger, so libraries that demand [logr.Logger]
+// (e.g. controller-runtime or Kubernetes client-go) use the same writers and settings:
+//
+//	ctrl.SetLogger(logr.New(logze.NewLogrSink(logger)))
+//
+// V-levels are mapped to logze levels: V(0) is info, V(1) is debug and V(2) and above are trace.
+// Names of logr loggers are added with [Logger.Named], so their levels can be changed with [SetNamedLevel].
+func NewLogrSink(l Logger) logr.LogSink {
+	return &logrSink{l: l}
+}
+
+type logrSink struct {
+	l Logger
+}
+
+// Init implements [logr.LogSink], it skips frames of logr to get a caller of logr.Logger method.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	// Methods of the sink are entry points of the logger, so only frames of logr should be skipped
+	s.l = s.l.WithCallerSkip(info.CallDepth)
+}
+
+// Enabled implements [logr.LogSink].
+func (s *logrSink) Enabled(level int) bool {
+	return s.l.enabled(logrLevel(level))
+}
+
+// Info implements [logr.LogSink].
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.l.log(s.l.newEvent(logrLevel(level)), msg, keysAndValues)
+}
+
+// Error implements [logr.LogSink].
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	if err == nil && s.l.nilErrors == NilErrorsSkip {
+		return
+	}
+	s.l.log(s.l.setError(s.l.setErrorCaller(s.l.newEvent(s.l.errLevel(err))), err), msg, keysAndValues)
+}
+
+// WithValues implements [logr.LogSink].
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logrSink{l: s.l.WithFields(keysAndValues...)}
+}
+
+// WithName implements [logr.LogSink].
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return &logrSink{l: s.l.Named(name)}
+}
+
+// WithCallDepth implements [logr.CallDepthLogSink].
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return &logrSink{l: s.l.WithCallerSkip(depth)}
+}
+
+func logrLevel(v int) zerolog.Level {
+	switch {
+	case v <= 0:
+		return zerolog.InfoLevel
+	case v == 1:
+		return zerolog.DebugLevel
+	}
+	return zerolog.TraceLevel
+}
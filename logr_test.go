@@ -0,0 +1,59 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestLogrSink(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelDebug).WithCallerLevels(logze.LevelInfo))
+	lr := logr.New(logze.NewLogrSink(logger)).WithName("controller").WithValues("kind", "Pod")
+
+	lr.V(2).Info("trace message")
+	if b.Len() != 0 {
+		t.Errorf("expected no entry for V(2) in debug level, got %s", b.String())
+	}
+	if lr.V(2).Enabled() || !lr.V(1).Enabled() {
+		t.Error("expected V(1) enabled and V(2) disabled")
+	}
+
+	lr.Info("info message", "name", "nginx")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{
+		"level":   "info",
+		"message": "info message",
+		"logger":  "controller",
+		"kind":    "Pod",
+		"name":    "nginx",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logr_test.go") {
+		t.Errorf("expected caller in the test, got %q", caller)
+	}
+
+	b.Reset()
+	lr.V(1).Info("debug message")
+	if !strings.Contains(b.String(), `"level":"debug"`) {
+		t.Errorf("expected debug entry for V(1), got %s", b.String())
+	}
+
+	b.Reset()
+	lr.Error(errors.New("some error"), "error message")
+	if !strings.Contains(b.String(), `"level":"error"`) || !strings.Contains(b.String(), `"error":"some error"`) {
+		t.Errorf("expected error entry, got %s", b.String())
+	}
+}
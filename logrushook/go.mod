@@ -0,0 +1,20 @@
+module github.com/maxbolgarin/logze/v2/logrushook
+
+go 1.21
+
+require (
+	github.com/maxbolgarin/logze/v2 v2.0.0
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/rs/zerolog v1.33.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/maxbolgarin/logze/v2 => ../
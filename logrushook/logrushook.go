@@ -0,0 +1,111 @@
+// Package logrushook provides a logrus hook that forwards logrus entries to [logze.Logger],
+// so codebases that still call logrus internally write to the same writers with the same settings:
+//
+//	logrushook.Redirect(logrus.StandardLogger(), logger)
+//
+// Levels of logrus are mapped to logze levels, Panic and Fatal are logged in fatal level, logrus panics or exits
+// after firing hooks itself. An error added with [logrus.WithError] is passed to logze as an error,
+// so it gets a stack trace if it is enabled in logze.
+//
+// It is a separate module, so the core logze package doesn't depend on logrus.
+package logrushook
+
+import (
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureLogrusHook)
+}
+
+// Hook implements [logrus.Hook] and forwards entries to [logze.Logger].
+type Hook struct {
+	l      logze.Logger
+	levels []logrus.Level
+}
+
+// New returns [Hook] that forwards entries of provided levels to the logger, all levels are forwarded
+// if no levels are provided. Entries are filtered by the level of the logger too.
+func New(l logze.Logger, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{l: l, levels: levels}
+}
+
+// Redirect makes logrus logger write all entries only through the logze logger: it adds [Hook], discards
+// the output of logrus and enables all its levels, so the level of the logze logger is used instead.
+func Redirect(lr *logrus.Logger, l logze.Logger) {
+	lr.AddHook(New(l))
+	lr.SetOutput(io.Discard)
+	lr.SetLevel(logrus.TraceLevel)
+}
+
+// Levels implements [logrus.Hook].
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire implements [logrus.Hook].
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	level := logzeLevel(entry.Level)
+	if !h.l.Enabled(level) {
+		return nil
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]any, 0, 2*len(keys))
+	for _, k := range keys {
+		fields = append(fields, k, entry.Data[k])
+	}
+	h.l.WithCallerSkip(callerSkip()).LogAttrs(level, entry.Message, fields)
+	return nil
+}
+
+const logrusPackage = "github.com/sirupsen/logrus."
+
+// callerSkip returns a number of frames between Fire and a code that calls logrus,
+// so callers of logze point to it.
+func callerSkip() int {
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers and callerSkip, so the first frame is Fire
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	// Skip Fire
+	frames.Next()
+	for i := 1; ; i++ {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, logrusPackage) {
+			return i
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+func logzeLevel(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel:
+		return logze.LevelTrace
+	case logrus.DebugLevel:
+		return logze.LevelDebug
+	case logrus.InfoLevel:
+		return logze.LevelInfo
+	case logrus.WarnLevel:
+		return logze.LevelWarn
+	case logrus.ErrorLevel:
+		return logze.LevelError
+	}
+	return logze.LevelFatal
+}
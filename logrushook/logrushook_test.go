@@ -0,0 +1,51 @@
+package logrushook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/logrushook"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedirect(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithCallerLevels(logze.LevelWarn))
+	lr := logrus.New()
+	logrushook.Redirect(lr, logger)
+
+	lr.Debug("debug message")
+	if b.Len() != 0 {
+		t.Errorf("expected no debug entry in info level, got %s", b.String())
+	}
+
+	lr.WithField("user", "bob").WithError(errors.New("some error")).Warn("warn message")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{
+		"level":   "warn",
+		"message": "warn message",
+		"user":    "bob",
+		"error":   "some error",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logrushook_test.go") {
+		t.Errorf("expected caller in the test, got %q", caller)
+	}
+
+	b.Reset()
+	lr.Infof("formatted %d", 42)
+	if !strings.Contains(b.String(), `"message":"formatted 42"`) {
+		t.Errorf("expected formatted info entry, got %s", b.String())
+	}
+}
@@ -2,27 +2,49 @@
 package logze
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/diode"
-	"github.com/rs/zerolog/pkgerrors"
 )
 
+// ErrorCallerFieldName is a field name for file:line where error was logged, see [Config.WithErrorCaller].
+var ErrorCallerFieldName = "error_caller"
+
+var warnNoWritersOnce sync.Once
+
 // Logger represents an initialized logger.
 // Default value behaves as default [zerolog.Logger].
 type Logger struct {
-	l          zerolog.Logger
-	errCounter ErrorCounter
-	toIgnore   []string
-	stackTrace bool
-	inited     bool
+	l               zerolog.Logger
+	level           *levelVar
+	ignore          *ignoreVar
+	out             *swapWriter
+	postWrite       *postWriteHooks
+	errCounter      ErrorCounter
+	stackOpts       *stackOptions
+	stackTrace      bool
+	stackByLevel    bool
+	goroutineDump   bool
+	stackLevel      zerolog.Level
+	errorCaller     bool
+	callerLevels    levelSet
+	callerSkip      int
+	callerMarshaler func(pc uintptr, file string, line int) string
+	timeFormat      string
+	nilErrors       string
+	development     bool
+	name            string
+	group           *fieldGroup
+	cache           *fieldsCache
+	inited          bool
 }
 
 // New returns a new [Logger] with provided config and fields.
@@ -45,68 +67,197 @@ type Logger struct {
 // Thats why you won't see any logs if you shoutdown your app right after logging.
 // Use [Config.WithNoDiode] to disable it,
 // but you will need to fix problem of blocking goroutine when writing may loge in Stderr if you have it.
+//
+// New panics if the level is invalid or if there are no writers and [NoWritersError] mode is set,
+// use [TryNew] to get an error instead.
 func New(cfg Config, fields ...any) Logger {
-	if len(cfg.Writers) == 0 || cfg.Level == LevelDisabled {
-		cfg.Writers = []io.Writer{io.Discard}
+	l, err := TryNew(cfg, fields...)
+	if err != nil {
+		panic(err.Error())
 	}
+	return l
+}
+
+// TryNew returns a new [Logger] with provided config and fields like [New] does,
+// but it returns an error instead of panicking if the level, the stack trace level or the summary template is invalid
+// or if there are no writers and [NoWritersError] mode is set.
+func TryNew(cfg Config, fields ...any) (Logger, error) {
 	if cfg.Level == "" {
 		cfg.Level = LevelInfo
 	}
-	if cfg.TimeFieldFormat == "" {
-		cfg.TimeFieldFormat = time.RFC3339
-	}
-	zerolog.TimeFieldFormat = cfg.TimeFieldFormat
-
 	level, err := zerolog.ParseLevel(cfg.Level)
 	if err != nil {
-		panic("cannot parse level=" + cfg.Level)
+		return Logger{}, errors.New("cannot parse level=" + cfg.Level)
 	}
 
-	output := cfg.Writers[0]
-	if len(cfg.Writers) > 1 {
-		output = zerolog.MultiLevelWriter(cfg.Writers...)
+	if cfg.Development && cfg.CallerLevels == nil {
+		cfg.CallerLevels = Levels
 	}
-	if !cfg.NoDiode {
-		if cfg.DiodeSize == 0 {
-			cfg.DiodeSize = DefaultDiodeSize
-		}
-		if cfg.DiodePollingInterval == 0 {
-			cfg.DiodePollingInterval = DefaultDiodePollingInterval
+
+	cfg.Writers = cfg.outputWriters()
+	if len(cfg.Writers) == 0 && cfg.Level != LevelDisabled {
+		switch cfg.NoWriters {
+		case NoWritersDiscard, "":
+		case NoWritersWarn:
+			warnNoWritersOnce.Do(func() {
+				fmt.Fprintln(os.Stderr, "WRN: logze: no writers provided, all logs will be discarded")
+			})
+		case NoWritersError:
+			return Logger{}, errors.New("no writers provided")
+		default:
+			return Logger{}, errors.New("unknown no writers mode=" + cfg.NoWriters)
 		}
-		if cfg.UseDiodeWaiter {
-			cfg.DiodePollingInterval = 0
+	}
+
+	summary, err := parseSummaryTemplate(cfg.Summary)
+	if err != nil {
+		return Logger{}, err
+	}
+
+	stackLevel := zerolog.Disabled
+	if cfg.StackTraceLevel != "" {
+		stackLevel, err = zerolog.ParseLevel(cfg.StackTraceLevel)
+		if err != nil {
+			return Logger{}, errors.New("cannot parse stack trace level=" + cfg.StackTraceLevel)
 		}
-		if cfg.DiodeAlertFunc == nil {
-			cfg.DiodeAlertFunc = func(missed int) {
-				fmt.Fprintf(os.Stderr, "WRN: logger dropped %d messages\n", missed)
-			}
+	}
+
+	if cfg.TimeFieldFormat == "" {
+		cfg.TimeFieldFormat = time.RFC3339
+		if cfg.HighResTimestamps {
+			cfg.TimeFieldFormat = TimeFormatHighRes
 		}
-		// To fix problem of blocking goroutine when writing in Stderr
-		// https://github.com/cloudfoundry/go-diodes
-		output = diode.NewWriter(output, cfg.DiodeSize, cfg.DiodePollingInterval, cfg.DiodeAlertFunc)
+	}
+	if writers, ok := withWriterTimeFormats(cfg.Writers, cfg.TimeFieldFormat); ok {
+		// Writers rewrite time to their own formats, so it is encoded without loss of precision
+		cfg.Writers = writers
+		cfg.TimeFieldFormat = time.RFC3339Nano
 	}
 
-	l := zerolog.New(output).With().Timestamp().Fields(fields).Logger().Level(level)
+	postWrite := newPostWriteHooks(cfg.PostWriteHooks)
+	out := newSwapWriter(newOutput(cfg, summary, postWrite, nil))
+
+	// Level is checked by Logger using levelVar, so it can be changed at runtime
+	l := zerolog.New(out).With().Fields(fields).Logger().Level(zerolog.TraceLevel)
+
+	// Time is formatted by the logger itself, so loggers with different formats don't clobber each other
+	if !cfg.NoTimestamp {
+		ts := newTimestampHook(cfg.Clock, cfg.TimeFieldFormat)
+		if cfg.HighResTimestamps && ts.now == nil {
+			ts.now = newHighResClock().now
+		}
+		l = l.Hook(ts)
+	}
 
 	if cfg.Hook != nil {
 		l = l.Hook(cfg.Hook)
 	}
 
-	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
-
 	return Logger{
-		l:          l,
-		toIgnore:   cfg.ToIgnore,
-		errCounter: cfg.ErrorCounter,
-		stackTrace: cfg.StackTrace,
-		inited:     true,
+		l:               l,
+		level:           newLevelVar(level),
+		ignore:          newIgnoreVar(cfg.ToIgnore),
+		out:             out,
+		postWrite:       postWrite,
+		errCounter:      cfg.ErrorCounter,
+		stackTrace:      cfg.StackTrace,
+		stackByLevel:    cfg.StackTraceLevel != "",
+		goroutineDump:   cfg.GoroutineDump,
+		stackLevel:      stackLevel,
+		stackOpts:       newStackOptions(cfg),
+		errorCaller:     cfg.ErrorCaller,
+		callerLevels:    newCallerLevels(cfg.CallerLevels),
+		callerSkip:      cfg.CallerSkip,
+		callerMarshaler: cfg.CallerMarshaler,
+		timeFormat:      cfg.TimeFieldFormat,
+		nilErrors:       cfg.NilErrors,
+		development:     cfg.Development,
+		cache:           newFieldsCache(),
+		inited:          true,
+	}, nil
+}
+
+// outputWriters returns writers of the config or writers that are used if there are none:
+// a console writer in development mode or stderr in [NoWritersStderr] mode. It returns nil if logs are discarded.
+func (c Config) outputWriters() []io.Writer {
+	switch {
+	case len(c.Writers) > 0:
+		return c.Writers
+	case c.Development && c.NoWriters == "":
+		return []io.Writer{getConsoleWriter(os.Stderr, true)}
+	case c.NoWriters == NoWritersStderr:
+		return []io.Writer{os.Stderr}
 	}
+	return nil
+}
+
+// newOutput returns an output combining all writers from [Config] wrapped in a diode writer if it is enabled.
+// Entries dropped by diode or async writer are added to provided counter, it is created if it is nil.
+func newOutput(cfg Config, summary summaryTemplate, postWrite *postWriteHooks, dropped *dropCounter) *output {
+	if len(cfg.Writers) == 0 || cfg.Level == LevelDisabled {
+		cfg.Writers = []io.Writer{io.Discard}
+	}
+	cfg.Writers = withConsoleTimeFormat(cfg.Writers, cfg.TimeFieldFormat)
+	cfg.Writers = withFieldNames(cfg.Writers, cfg.FieldNames)
+	if cfg.ECS {
+		cfg.Writers = withECS(cfg.Writers)
+	}
+	if cfg.GCP {
+		cfg.Writers = withGCP(cfg.Writers, cfg.GCPProjectID, cfg.GCPErrorReporting)
+	}
+	if cfg.Datadog != nil {
+		cfg.Writers = withDatadog(cfg.Writers, *cfg.Datadog)
+	}
+
+	out := &output{writers: trackWriters(cfg.Writers)}
+	if len(out.writers) == 1 {
+		out.w = out.writers[0]
+	} else {
+		writers := make([]io.Writer, len(out.writers))
+		for i, w := range out.writers {
+			writers[i] = w
+		}
+		out.w = zerolog.MultiLevelWriter(writers...)
+	}
+	if postWrite != nil {
+		out.w = postWriteWriter{w: out.w, hooks: postWrite}
+	}
+	if len(cfg.EntryHooks) > 0 {
+		out.w = entryHookWriter{w: out.w, hooks: cfg.EntryHooks}
+	}
+	if len(summary) > 0 {
+		// Summary is added before entry hooks, so they get the same entry as writers
+		out.w = summaryWriter{w: out.w, template: summary}
+	}
+	if cfg.Async.Policy != "" || !cfg.NoDiode {
+		if dropped == nil {
+			dropped = newDropCounter(cfg.ErrorCounter)
+		}
+		out.dropped = dropped
+	}
+	switch {
+	case cfg.Async.Policy != "":
+		out.buffer = newAsyncWriter(out.w, cfg.Async, out.dropped)
+		out.w = out.buffer
+	case !cfg.NoDiode:
+		out.buffer = newDiodeWriter(out.w, cfg, out.dropped)
+		out.w = out.buffer
+	}
+	if cfg.MaxThroughput > 0 {
+		// Throttle is applied before buffering, so levels of entries are known
+		out.throttle = newThrottleWriter(out.w, cfg.MaxThroughput)
+		out.w = out.throttle
+	}
+
+	return out
 }
 
 // NewFromZerolog returns a new [Logger] based on provided [zerolog.Logger].
 func NewFromZerolog(l zerolog.Logger) Logger {
 	return Logger{
-		l:      l,
+		l:      l.Level(zerolog.TraceLevel),
+		level:  newLevelVar(l.GetLevel()),
+		cache:  newFieldsCache(),
 		inited: true,
 	}
 }
@@ -124,12 +275,53 @@ func Nop() Logger {
 // Update replaces underlying logger with a new one created using provided config and fields.
 // It is NOT safe for concurrent use.
 func (l *Logger) Update(cfg Config, fields ...any) {
-	newLogger := New(cfg, fields...)
-	l.l = newLogger.l
-	l.inited = newLogger.inited
-	l.errCounter = newLogger.errCounter
-	l.stackTrace = newLogger.stackTrace
-	l.toIgnore = newLogger.toIgnore
+	*l = New(cfg, fields...)
+}
+
+// Reload atomically applies level, list of messages to ignore, writers and summary template from the provided config
+// to the logger and all its copies (e.g. created with [Logger.WithFields] or set with [SetStdLogger]).
+// Writers (with summary template) are replaced only if there is at least one writer in the config.
+// Other settings are not changed, use [Logger.Update] to apply them.
+// Previous diode writer is closed after replacing. It is safe for concurrent use.
+func (l Logger) Reload(cfg Config) error {
+	_, err := l.reload(cfg)
+	return err
+}
+
+// reload applies the config like [Logger.Reload] and returns the replaced output, nil if writers are not replaced.
+func (l Logger) reload(cfg Config) (*output, error) {
+	if l.out == nil {
+		return nil, fmt.Errorf("logger is not created with New")
+	}
+	if cfg.Level == "" {
+		cfg.Level = LevelInfo
+	}
+	level, err := zerolog.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level %q", cfg.Level)
+	}
+	summary, err := parseSummaryTemplate(cfg.Summary)
+	if err != nil {
+		return nil, err
+	}
+
+	var old *output
+	if len(cfg.Writers) > 0 || cfg.Level == LevelDisabled {
+		if cfg.TimeFieldFormat == "" {
+			cfg.TimeFieldFormat = time.RFC3339
+		}
+		cfg.Writers, _ = withWriterTimeFormats(cfg.Writers, cfg.TimeFieldFormat)
+		// Time format of the logger is not changed, new writers should parse it
+		cfg.TimeFieldFormat = l.timeFormat
+		// Counter of dropped entries is kept, so it is cumulative
+		old = l.out.swap(newOutput(cfg, summary, l.postWrite, l.out.load().dropped))
+		// Underlying writers are not closed, only diode poller is stopped
+		closeAsync(old.buffer)
+	}
+	l.ignore.set(cfg.ToIgnore)
+	l.level.set(level)
+
+	return old, nil
 }
 
 // NotInited returns true if [Logger] is not inited (struct with default values).
@@ -138,8 +330,21 @@ func (l Logger) NotInited() bool {
 }
 
 // WithFields returns [Logger] with applied fields to all messages, provided as (key, value) pairs.
+// Level of the returned logger follows the level of the parent logger, changed with [Logger.SetLevel],
+// until its own level is set with [Logger.WithLevel] or [Logger.SetLevel].
+// Fields are nested in the current group if it is opened with [Logger.WithGroup].
+// Fields are encoded once here, so they don't add cost to logging calls; use [Logger.CachedWith]
+// to avoid creating the same child logger again and again in hot paths.
 func (l Logger) WithFields(fields ...any) Logger {
-	l.l = l.l.With().Fields(fields).Logger()
+	fields = resolveValues(expandFields(fields))
+	if l.group != nil {
+		l.group = l.group.with(fields)
+	} else {
+		l.l = l.l.With().Fields(fields).Logger()
+	}
+	l.level = l.level.child()
+	// Children are cached by the logger only if they are created with CachedWith
+	l.cache = nil
 	return l
 }
 
@@ -148,7 +353,8 @@ func (l Logger) With(fields ...any) Logger {
 	return l.WithFields(fields...)
 }
 
-// WithLevel returns [Logger] with an applied log level.
+// WithLevel returns [Logger] with an applied log level. The level is pinned:
+// it won't follow changes of the parent logger level.
 func (l Logger) WithLevel(level string) Logger {
 	if level == "" {
 		return l
@@ -157,10 +363,92 @@ func (l Logger) WithLevel(level string) Logger {
 	if err != nil {
 		panic("cannot parse level=" + level)
 	}
-	l.l = l.l.Level(lvl)
+	l.level = l.level.pinnedChild(lvl)
+	l.cache = newFieldsCache()
 	return l
 }
 
+// SetLevel atomically changes the level of the logger and all its copies and children
+// following its level, e.g. created with [Logger.WithFields] or set with [SetStdLogger].
+// Children with their own level set by [Logger.WithLevel] or [Logger.SetLevel] are not affected.
+// If the logger is a child, its level is pinned and won't follow the parent anymore,
+// use [Logger.ResetLevel] to revert it. It is safe for concurrent use.
+func (l Logger) SetLevel(level string) error {
+	if l.level == nil {
+		return errors.New("logger is not created with New")
+	}
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return errors.New("cannot parse level=" + level)
+	}
+	l.level.set(lvl)
+	return nil
+}
+
+// ResetLevel makes the level of a child logger follow the level of its parent again
+// after it was pinned with [Logger.SetLevel]. It does nothing for a root logger.
+func (l Logger) ResetLevel() {
+	if l.level != nil {
+		l.level.unpin()
+	}
+}
+
+// GetLevel returns the current level of the logger in string format.
+func (l Logger) GetLevel() string {
+	if l.level == nil {
+		return l.l.GetLevel().String()
+	}
+	return l.level.get().String()
+}
+
+// Enabled returns true if messages of provided level will be logged by the logger,
+// it can be used to guard computation of expensive fields. It returns false for unknown level.
+func (l Logger) Enabled(level string) bool {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return false
+	}
+	return l.enabled(lvl)
+}
+
+// IsDebug returns true if messages of debug level will be logged by the logger.
+func (l Logger) IsDebug() bool {
+	return l.enabled(zerolog.DebugLevel)
+}
+
+// IsTrace returns true if messages of trace level will be logged by the logger.
+func (l Logger) IsTrace() bool {
+	return l.enabled(zerolog.TraceLevel)
+}
+
+// IfDebug calls provided function with the logger only if debug level is enabled,
+// it keeps expensive serialization and loops out of the hot path:
+//
+//	logger.IfDebug(func(l logze.Logger) {
+//		for _, item := range items {
+//			l.Debug("item", "value", item.Dump())
+//		}
+//	})
+func (l Logger) IfDebug(f func(l Logger)) {
+	if l.IsDebug() {
+		f(l)
+	}
+}
+
+// IfTrace calls provided function with the logger only if trace level is enabled, see [Logger.IfDebug].
+func (l Logger) IfTrace(f func(l Logger)) {
+	if l.IsTrace() {
+		f(l)
+	}
+}
+
+func (l Logger) enabled(level zerolog.Level) bool {
+	if l.level == nil {
+		return level >= l.l.GetLevel()
+	}
+	return l.level.enabled(level)
+}
+
 // WithStack returns [Logger] with an applied stackTrace.
 func (l Logger) WithStack(stackTrace bool) Logger {
 	l.stackTrace = stackTrace
@@ -181,69 +469,99 @@ func (l Logger) WithSimpleErrorCounter() Logger {
 
 // WithToIgnore returns [Logger] with the provided list of messages to ignore.
 func (l Logger) WithToIgnore(toIgnore ...string) Logger {
-	l.toIgnore = toIgnore
+	l.ignore = newIgnoreVar(toIgnore)
 	return l
 }
 
-// Trace logs a message in trace level adding provided fields and information about method caller.
+// Trace logs a message in trace level adding provided fields and information about method caller
+// (caller is added to trace level by default, see [Config.WithCallerLevels]).
 func (l Logger) Trace(msg string, fields ...any) {
-	l.log(l.l.Trace().Caller(1), msg, fields)
+	l.log(l.newEvent(zerolog.TraceLevel), msg, fields)
 }
 
 // Tracef logs a formatted message in trace level adding provided fields after formatting args
 // and information about method caller.
 func (l Logger) Tracef(msg string, args ...any) {
-	l.logf(l.l.Trace().Caller(1), msg, args)
+	l.logf(l.newEvent(zerolog.TraceLevel), msg, args)
 }
 
 // Debug logs a message in debug level adding provided fields.
 func (l Logger) Debug(msg string, fields ...any) {
-	l.log(l.l.Debug(), msg, fields)
+	l.log(l.newEvent(zerolog.DebugLevel), msg, fields)
 }
 
 // Debugf logs a formatted message in debug level adding provided fields after formatting args.
 func (l Logger) Debugf(msg string, args ...any) {
-	l.logf(l.l.Debug(), msg, args)
+	l.logf(l.newEvent(zerolog.DebugLevel), msg, args)
 }
 
 // Info logs a message in info level adding provided fields.
 func (l Logger) Info(msg string, fields ...any) {
-	l.log(l.l.Info(), msg, fields)
+	l.log(l.newEvent(zerolog.InfoLevel), msg, fields)
 }
 
 // Infof logs a formatted message in info level adding provided fields after formatting args.
 func (l Logger) Infof(msg string, args ...any) {
-	l.logf(l.l.Info(), msg, args)
+	l.logf(l.newEvent(zerolog.InfoLevel), msg, args)
 }
 
 // Warn logs a message in warning level adding provided fields.
 func (l Logger) Warn(msg string, fields ...any) {
-	l.log(l.l.Warn(), msg, fields)
+	l.log(l.newEvent(zerolog.WarnLevel), msg, fields)
 }
 
 // Warnf logs a formatted message in warn level adding provided fields after formatting args.
 func (l Logger) Warnf(msg string, args ...any) {
-	l.logf(l.l.Warn(), msg, args)
+	l.logf(l.newEvent(zerolog.WarnLevel), msg, args)
 }
 
 // Err logs a provided error in error level adding provided fields.
+// Nil error is handled according to [Config.NilErrors].
 func (l Logger) Err(err error, msg string, fields ...any) {
-	l.log(l.setErrorWithStack(l.l.Error(), err), msg, fields)
+	if err == nil && l.nilErrors == NilErrorsSkip {
+		// Errors in fields are not counted and their stacks are not captured
+		return
+	}
+	l.log(l.setError(l.setErrorCaller(l.newEvent(l.errLevel(err))), err), msg, fields)
 }
 
 // Errf logs a formatted message in error level adding provided fields after formatting args.
+// Nil error is handled according to [Config.NilErrors].
 func (l Logger) Errf(err error, msg string, args ...any) {
-	l.logf(l.setErrorWithStack(l.l.Error(), err), msg, args)
+	l.logf(l.setError(l.setErrorCaller(l.newEvent(l.errLevel(err))), err), msg, args)
 }
 
 // Error logs a message in error level adding provided fields.
 func (l Logger) Error(msg string, fields ...any) {
-	l.log(l.l.Error(), msg, fields)
+	l.log(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), msg, fields)
 }
 
 // Errorf logs a formatted message in error level adding provided fields after formatting args.
 func (l Logger) Errorf(msg string, args ...any) {
-	l.logf(l.l.Error(), msg, args)
+	l.logf(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), msg, args)
+}
+
+// DPanic logs a message in error level adding provided fields. In development mode (see [Config.WithDevelopment])
+// it panics after logging, so "impossible" situations are caught in development without crashing production.
+func (l Logger) DPanic(msg string, fields ...any) {
+	l.log(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), msg, fields)
+	if l.development {
+		panic(msg)
+	}
+}
+
+// DPanicf logs a formatted message in error level adding provided fields after formatting args.
+// In development mode it panics after logging, see [Logger.DPanic].
+func (l Logger) DPanicf(msg string, args ...any) {
+	var panicMsg string
+	if l.development {
+		// Message is formatted before logging, because errors are moved from args to the error field
+		panicMsg = formatMessage(msg, args)
+	}
+	l.logf(l.setErrorCaller(l.newEvent(zerolog.ErrorLevel)), msg, args)
+	if l.development {
+		panic(panicMsg)
+	}
 }
 
 // ErrStack logs a stack trace of provided error as message in error level adding fields.
@@ -252,23 +570,23 @@ func (l Logger) ErrStack(err error, fields ...any) {
 		StackForLogger() []any
 	})
 	if !ok {
-		err = errors.WithStack(err)
+		err = withStack(err, l.stackOpts.captureSize())
 	}
-	l.log(l.l.Error(), fmt.Sprintf("%+v", err), fields)
+	l.log(l.newEvent(zerolog.ErrorLevel), fmt.Sprintf("%+v", err), fields)
 }
 
 // Fatal logs a message in fatal level using fmt.Sprint to interpret args, then calls os.Exit(1).
 func (l Logger) Fatal(v ...any) {
 	s := fmt.Sprint(v...)
 	l.incErrorConter(errors.New(s))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), s, nil)
+	l.log(l.newEvent(zerolog.FatalLevel), s, nil)
 	os.Exit(1)
 }
 
 // Fatalf logs a formatted message in fatal level, then calls os.Exit(1).
 func (l Logger) Fatalf(format string, args ...any) {
 	l.incErrorConter(fmt.Errorf(format, args...))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), format, args)
+	l.log(l.newEvent(zerolog.FatalLevel), format, args)
 	os.Exit(1)
 }
 
@@ -276,7 +594,7 @@ func (l Logger) Fatalf(format string, args ...any) {
 func (l Logger) Fatalln(v ...any) {
 	s := fmt.Sprintln(v...)
 	l.incErrorConter(errors.New(s))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), s, nil)
+	l.log(l.newEvent(zerolog.FatalLevel), s, nil)
 	os.Exit(1)
 }
 
@@ -284,14 +602,14 @@ func (l Logger) Fatalln(v ...any) {
 func (l Logger) Panic(v ...any) {
 	s := fmt.Sprint(v...)
 	l.incErrorConter(errors.New(s))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), s, nil)
+	l.log(l.newEvent(zerolog.FatalLevel), s, nil)
 	panic(s)
 }
 
 // Panicf logs a formatted message in fatal level, then calls panic().
 func (l Logger) Panicf(format string, args ...any) {
 	l.incErrorConter(fmt.Errorf(format, args...))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), format, args)
+	l.log(l.newEvent(zerolog.FatalLevel), format, args)
 	panic(fmt.Sprintf(format, args...))
 }
 
@@ -299,7 +617,7 @@ func (l Logger) Panicf(format string, args ...any) {
 func (l Logger) Panicln(v ...any) {
 	s := fmt.Sprintln(v...)
 	l.incErrorConter(errors.New(s))
-	l.log(l.l.WithLevel(zerolog.FatalLevel), s, nil)
+	l.log(l.newEvent(zerolog.FatalLevel), s, nil)
 	panic(s)
 }
 
@@ -308,38 +626,60 @@ func (l Logger) Print(v ...any) {
 	if len(v) == 0 {
 		return
 	}
-	l.log(l.l.Log(), fmt.Sprint(v...), nil)
+	l.log(l.newEvent(zerolog.NoLevel), fmt.Sprint(v...), nil)
 }
 
 // PrintStack logs a current stack trace.
 func (l Logger) PrintStack(v ...any) {
 	stack := debug.Stack()
-	l.log(l.l.Log(), string(stack), v)
+	l.log(l.newEvent(zerolog.NoLevel), string(stack), v)
 }
 
 // Log logs a message without level using [fmt.Sprint] to interpret args.
 // It is an alias for [Logger.Print].
 func (l Logger) Log(v ...any) {
-	l.Print(v...)
+	if len(v) == 0 {
+		return
+	}
+	l.log(l.newEvent(zerolog.NoLevel), fmt.Sprint(v...), nil)
 }
 
 // Printf logs a formatted message without level.
 func (l Logger) Printf(format string, args ...any) {
-	l.logf(l.l.Log(), format, args)
+	l.logf(l.newEvent(zerolog.NoLevel), format, args)
 }
 
 // Println writes a message without level using fmt.Sprintln to interpret args.
 func (l Logger) Println(v ...any) {
-	l.log(l.l.Log(), fmt.Sprintln(v...), nil)
+	l.log(l.newEvent(zerolog.NoLevel), fmt.Sprintln(v...), nil)
+}
+
+// LogAttrs logs a message in provided level with fields, provided as (key, value) pairs, without any formatting.
+// It is a low-level entry point for adapters (e.g. slog handler or middleware): it honors the level,
+// list of messages to ignore, error counter and stack trace of an error in fields, but unlike other methods
+// it doesn't call os.Exit or panic for fatal and panic levels. Unknown level is logged without level.
+// Fields slice can be modified in place, so it should not be used after the call.
+func (l Logger) LogAttrs(level string, msg string, fields []any) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		lvl = zerolog.NoLevel
+	}
+	l.log(l.newEvent(lvl), msg, fields)
 }
 
 // Write writes bytes to underlying [io.Writer].
 func (l Logger) Write(p []byte) (n int, err error) {
+	if !l.level.enabled(zerolog.NoLevel) {
+		return len(p), nil
+	}
 	return l.l.Write(p)
 }
 
-// Raw returns Logger's underlying [zerolog.Logger].
+// Raw returns Logger's underlying [zerolog.Logger] with the current level of [Logger].
 func (l Logger) Raw() *zerolog.Logger {
+	if l.level != nil {
+		l.l = l.l.Level(l.level.get())
+	}
 	return &l.l
 }
 
@@ -348,56 +688,151 @@ func (l Logger) GetErrorCounter() ErrorCounter {
 	return l.errCounter
 }
 
+// newEvent returns an event of provided level or nil if the level is disabled.
+// It adds a caller if it is enabled for the level, so it should be called directly from an exported method.
+func (l Logger) newEvent(level zerolog.Level) *zerolog.Event {
+	if !l.level.enabled(level) {
+		return nil
+	}
+	ev := l.l.WithLevel(level)
+	if l.name != "" {
+		ev = ev.Str(LoggerFieldName, l.name)
+	}
+	if l.stackByLevel && level >= l.stackLevel && level != zerolog.NoLevel {
+		// Skip exported method of Logger, so the stack starts where a message is logged
+		ev = l.stackOpts.setStack(ev, callSiteStack(1+l.callerSkip, l.stackOpts.captureSize()))
+	}
+	if l.goroutineDump && level == zerolog.FatalLevel {
+		ev = ev.Str(GoroutinesFieldName, goroutineDump())
+	}
+	if l.callerLevels.has(level) {
+		// Skip newEvent and exported method of Logger
+		if pc, file, line, ok := runtime.Caller(2 + l.callerSkip); ok {
+			ev = ev.Str(zerolog.CallerFieldName, l.marshalCaller(pc, file, line))
+		}
+	}
+	return ev
+}
+
 func (l Logger) log(ev *zerolog.Event, msg string, fields []any) {
-	for _, ignore := range l.toIgnore {
-		if strings.Contains(msg, ignore) {
+	if l.ignore.match(msg) {
+		return
+	}
+	if ev != nil && l.group == nil {
+		if ev, ok := appendSimpleFields(ev, fields); ok {
+			ev.Msg(msg)
 			return
 		}
 	}
+	fields = expandFields(fields)
 	if len(fields) > 1 {
 		ev = l.setErrorWithStack(ev, fields...)
-		ev = ev.Fields(fields)
+	} else {
+		fields = nil
 	}
+	ev = l.addFields(ev, fields)
 	ev.Msg(msg)
 }
 
 func (l Logger) logf(ev *zerolog.Event, msg string, args []any) {
-	for _, ignore := range l.toIgnore {
-		if strings.Contains(msg, ignore) {
-			return
-		}
+	if ev == nil {
+		// Level is disabled, there is nothing to format
+		return
 	}
-	numberOfFormats := strings.Count(msg, "%")
-	if numberOfFormats > 0 && numberOfFormats <= len(args) {
-		ev = l.setErrorWithStack(ev, args...)
-		ev = ev.Fields(args[numberOfFormats:])
-		args = args[:numberOfFormats]
+	if l.ignore.match(msg) {
+		return
 	}
-	if numberOfFormats == 0 && len(args) > 0 {
+	if len(args) > 0 {
 		ev = l.setErrorWithStack(ev, args...)
-		ev = ev.Fields(args)
-		args = nil
 	}
-	if len(args) == 0 {
+	if strings.IndexByte(msg, '%') < 0 {
+		// Nothing to format, all arguments are fields
+		ev = l.addFields(ev, expandFields(args))
 		ev.Msg(msg)
 		return
 	}
+
+	n, hasWrap := formatArgs(msg)
+	if n < len(args) {
+		ev = l.addFields(ev, expandFields(args[n:]))
+		args = args[:n]
+	}
+	if hasWrap {
+		msg = replaceWrapVerbs(msg)
+	}
 	ev.Msgf(msg, args...)
 }
 
+// addFields adds fields of a message to the event, they are nested in the current group if there is one.
+func (l Logger) addFields(ev *zerolog.Event, fields []any) *zerolog.Event {
+	if ev == nil {
+		// Level is disabled, lazy values should not be resolved
+		return nil
+	}
+	if l.group == nil {
+		if ev, ok := appendSimpleFields(ev, fields); ok {
+			return ev
+		}
+	}
+	fields = resolveValues(fields)
+	if l.group != nil {
+		return l.group.appendTo(ev, fields)
+	}
+	if len(fields) == 0 {
+		return ev
+	}
+	return ev.Fields(fields)
+}
+
+// errLevel returns a level of a message with provided error, it panics for nil error in [NilErrorsPanic] mode in development.
+func (l Logger) errLevel(err error) zerolog.Level {
+	if err != nil {
+		return zerolog.ErrorLevel
+	}
+	switch l.nilErrors {
+	case NilErrorsInfo:
+		return zerolog.InfoLevel
+	case NilErrorsSkip:
+		return zerolog.Disabled
+	case NilErrorsPanic:
+		if l.development {
+			panic("logze: nil error is passed to Err")
+		}
+	}
+	return zerolog.ErrorLevel
+}
+
+// setError adds provided error to the event or a warning if the error is nil in [NilErrorsInfo] mode.
+func (l Logger) setError(ev *zerolog.Event, err error) *zerolog.Event {
+	if err == nil {
+		if l.nilErrors == NilErrorsInfo {
+			return ev.Str(WarningFieldName, "nil error")
+		}
+		return ev
+	}
+	return l.setErrorWithStack(ev, err)
+}
+
 func (l Logger) setErrorWithStack(ev *zerolog.Event, args ...any) *zerolog.Event {
 	for i, a := range args {
 		if err, ok := a.(error); ok {
-			if l.stackTrace {
+			if l.stackTrace || l.stackByLevel {
+				if chain := causeChain(err); chain != nil {
+					ev = ev.Strs(CauseFieldName, chain)
+				}
+			}
+			if l.stackTrace && !l.stackByLevel {
 				// Hack to use github.com/maxbolgarin/errm without importing it
 				errmErr, ok := err.(interface {
 					StackForLogger() []any
 				})
 				if ok {
 					ev = ev.Fields(errmErr.StackForLogger())
+				} else if l.stackOpts.hasMarshaler() {
+					ev = l.stackOpts.marshal(ev, err)
 				} else {
-					ev = ev.Stack()
-					err = errors.WithStack(err)
+					err = withStack(err, l.stackOpts.captureSize())
+					ev = l.stackOpts.setStack(ev, err)
 				}
 			}
 			l.incErrorConter(err)
@@ -411,6 +846,20 @@ func (l Logger) setErrorWithStack(ev *zerolog.Event, args ...any) *zerolog.Event
 	return ev
 }
 
+// setErrorCaller adds file:line of the caller as "error_caller" field if it is enabled,
+// it should be called directly from an exported method.
+func (l Logger) setErrorCaller(ev *zerolog.Event) *zerolog.Event {
+	if !l.errorCaller || ev == nil {
+		return ev
+	}
+	// Skip setErrorCaller and exported method of Logger
+	pc, file, line, ok := runtime.Caller(2 + l.callerSkip)
+	if !ok {
+		return ev
+	}
+	return ev.Str(ErrorCallerFieldName, l.marshalCaller(pc, file, line))
+}
+
 func (l Logger) incErrorConter(err error) {
 	if l.errCounter != nil {
 		l.errCounter.Inc(err)
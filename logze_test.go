@@ -3,11 +3,13 @@ package logze_test
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/maxbolgarin/logze/v2"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 func TestLoggerInitialization(t *testing.T) {
@@ -278,3 +280,188 @@ func TestLoggerPrintf(t *testing.T) {
 		t.Errorf("expected unlevelled formatted log, got %s", output)
 	}
 }
+
+func TestTryNew(t *testing.T) {
+	if _, err := logze.TryNew(logze.NewConfig(io.Discard).WithLevel("verbose")); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if _, err := logze.TryNew(logze.NewConfig().WithNoWriters(logze.NoWritersError)); err == nil {
+		t.Error("expected error for no writers")
+	}
+	if _, err := logze.TryNew(logze.NewConfig().WithNoWriters(logze.NoWritersError).WithLevel(logze.LevelDisabled)); err != nil {
+		t.Errorf("unexpected error for disabled logger: %v", err)
+	}
+	if _, err := logze.TryNew(logze.NewConfig().WithNoWriters("unknown")); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+
+	logger, err := logze.TryNew(logze.NewConfig().WithNoWriters(logze.NoWritersDiscard))
+	if err != nil || logger.NotInited() {
+		t.Errorf("expected inited logger, got error %v", err)
+	}
+}
+
+func TestNewPanicsNoWriters(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic")
+		}
+	}()
+	logze.New(logze.NewConfig().WithNoWriters(logze.NoWritersError))
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+	child := logger.WithFields("foo", "bar")
+	pinned := logger.WithLevel(logze.LevelWarn)
+
+	if logger.GetLevel() != logze.LevelInfo {
+		t.Errorf("expected %s, got %s", logze.LevelInfo, logger.GetLevel())
+	}
+
+	if err := logger.SetLevel(logze.LevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if child.GetLevel() != logze.LevelDebug || logger.Raw().GetLevel().String() != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, child.GetLevel())
+	}
+	if pinned.GetLevel() != logze.LevelWarn {
+		t.Errorf("expected %s, got %s", logze.LevelWarn, pinned.GetLevel())
+	}
+
+	child.Debug("debug message")
+	pinned.Info("info message")
+	if !strings.Contains(b.String(), "debug message") || strings.Contains(b.String(), "info message") {
+		t.Errorf("expected only debug message, got %s", b.String())
+	}
+
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if err := logze.Nop().SetLevel(logze.LevelDebug); err == nil {
+		t.Error("expected error for nop logger")
+	}
+}
+
+func TestLoggerLevelInheritance(t *testing.T) {
+	var b bytes.Buffer
+	parent := logze.New(logze.NewConfig(&b).WithNoDiode())
+	child := parent.WithFields("child", 1)
+	grandchild := child.WithFields("grandchild", 1)
+
+	if err := parent.SetLevel(logze.LevelDebug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grandchild.GetLevel() != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, grandchild.GetLevel())
+	}
+
+	// Setting a level of child pins it and doesn't affect parent
+	if err := child.SetLevel(logze.LevelError); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parent.GetLevel() != logze.LevelDebug {
+		t.Errorf("expected %s, got %s", logze.LevelDebug, parent.GetLevel())
+	}
+	if grandchild.GetLevel() != logze.LevelError {
+		t.Errorf("expected %s, got %s", logze.LevelError, grandchild.GetLevel())
+	}
+
+	if err := parent.SetLevel(logze.LevelTrace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if child.GetLevel() != logze.LevelError {
+		t.Errorf("expected pinned %s, got %s", logze.LevelError, child.GetLevel())
+	}
+
+	child.ResetLevel()
+	if grandchild.GetLevel() != logze.LevelTrace {
+		t.Errorf("expected %s, got %s", logze.LevelTrace, grandchild.GetLevel())
+	}
+
+	grandchild.Trace("trace message")
+	if !strings.Contains(b.String(), "trace message") {
+		t.Errorf("expected trace message, got %s", b.String())
+	}
+}
+
+func TestLogAttrs(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithSimpleErrorCounter().WithToIgnore("ignored").WithNoDiode())
+
+	logger.LogAttrs(logze.LevelWarn, "100% done", []any{"key", "value", "err", errors.New("some error")})
+	if !strings.Contains(b.String(), `"level":"warn"`) || !strings.Contains(b.String(), `"message":"100% done"`) ||
+		!strings.Contains(b.String(), `"key":"value"`) || !strings.Contains(b.String(), `"error":"some error"`) {
+		t.Errorf("expected warn message with fields, got %s", b.String())
+	}
+	if n := logger.GetErrorCounter().(*logze.SimpleErrorCounter).Count.Load(); n != 1 {
+		t.Errorf("expected 1 error, got %d", n)
+	}
+
+	b.Reset()
+	logger.LogAttrs(logze.LevelDebug, "debug", nil)
+	logger.LogAttrs(logze.LevelError, "ignored message", nil)
+	if b.Len() != 0 {
+		t.Errorf("expected no messages, got %s", b.String())
+	}
+
+	logger.LogAttrs(logze.LevelFatal, "fatal", nil)
+	if !strings.Contains(b.String(), `"level":"fatal"`) {
+		t.Errorf("expected fatal message without exit, got %s", b.String())
+	}
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	logger := logze.New(logze.NewConfig(io.Discard).WithLevel(logze.LevelDebug))
+
+	if !logger.Enabled(logze.LevelInfo) || !logger.Enabled(logze.LevelDebug) || logger.Enabled(logze.LevelTrace) {
+		t.Error("expected info and debug to be enabled, trace to be disabled")
+	}
+	if !logger.IsDebug() || logger.IsTrace() {
+		t.Error("expected debug to be enabled, trace to be disabled")
+	}
+	if logger.Enabled("unknown") {
+		t.Error("expected false for unknown level")
+	}
+
+	if err := logger.SetLevel(logze.LevelTrace); err != nil {
+		t.Fatal(err)
+	}
+	if !logger.WithFields("foo", "bar").IsTrace() {
+		t.Error("expected trace to be enabled after SetLevel")
+	}
+
+	if !logze.NewFromZerolog(zerolog.New(io.Discard).Level(zerolog.InfoLevel)).Enabled(logze.LevelWarn) {
+		t.Error("expected warn to be enabled in logger from zerolog")
+	}
+}
+
+func TestLoggerIfDebug(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelDebug).WithNoDiode())
+
+	called := false
+	logger.IfTrace(func(l logze.Logger) {
+		called = true
+	})
+	if called {
+		t.Error("expected no call for disabled trace level")
+	}
+
+	logger.IfDebug(func(l logze.Logger) {
+		l.Debug("debug message")
+	})
+	if !strings.Contains(b.String(), `"message":"debug message"`) {
+		t.Errorf("expected debug message, got %s", b.String())
+	}
+
+	b.Reset()
+	logze.Init(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+	logze.IfTrace(func(l logze.Logger) {
+		l.Trace("trace message")
+	})
+	if !strings.Contains(b.String(), "logze_test.go:") {
+		t.Errorf("expected caller from test file, got %s", b.String())
+	}
+}
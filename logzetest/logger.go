@@ -0,0 +1,64 @@
+package logzetest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// Option changes [logze.Config] of a logger created with [New].
+type Option func(cfg logze.Config) logze.Config
+
+// WithLevel sets a level of a logger created with [New], default value is [logze.LevelTrace].
+func WithLevel(level string) Option {
+	return func(cfg logze.Config) logze.Config {
+		return cfg.WithLevel(level)
+	}
+}
+
+// New returns [logze.Logger] that writes pretty console output through t.Log, so logs are shown only
+// for failing tests or with -v flag and they are attributed to the test that writes them.
+// The logger is closed when the test and all its subtests complete, entries written after that are dropped.
+func New(t testing.TB, opts ...Option) logze.Logger {
+	w := &tbWriter{t: t}
+	cfg := logze.NewConfig().
+		WithConsoleOptions(logze.ConsoleOptions{Out: w, NoColor: true, TimeFormat: "15:04:05"}).
+		WithLevel(logze.LevelTrace).
+		WithNoDiode()
+	for _, opt := range opts {
+		cfg = opt(cfg)
+	}
+	lg := logze.New(cfg)
+	t.Cleanup(func() {
+		if err := lg.Close(); err != nil {
+			t.Errorf("close logger: %v", err)
+		}
+		w.stop()
+	})
+	return lg
+}
+
+// tbWriter writes every entry with t.Log until the test is completed, because t.Log panics after that.
+type tbWriter struct {
+	mu   sync.Mutex
+	t    testing.TB
+	done bool
+}
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.done {
+		w.t.Helper()
+		w.t.Log(strings.TrimRight(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *tbWriter) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
@@ -0,0 +1,60 @@
+package logzetest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/logzetest"
+)
+
+// fakeTB records logs, errors and cleanups of a test.
+type fakeTB struct {
+	testing.TB
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Log(args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestNew(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	lg := logzetest.New(tb, logzetest.WithLevel(logze.LevelInfo))
+
+	lg.Debug("debug message")
+	lg.Info("info message", "key", "value")
+	if len(tb.logs) != 1 {
+		t.Fatalf("expected 1 log, got %v", tb.logs)
+	}
+	if !strings.Contains(tb.logs[0], "INF info message key=value") || strings.HasSuffix(tb.logs[0], "\n") {
+		t.Errorf("expected console entry without newline, got %q", tb.logs[0])
+	}
+
+	if len(tb.cleanups) != 1 {
+		t.Fatalf("expected cleanup, got %d", len(tb.cleanups))
+	}
+	tb.cleanups[0]()
+	lg.Info("after cleanup")
+	if len(tb.logs) != 1 {
+		t.Errorf("expected no logs after cleanup, got %v", tb.logs)
+	}
+}
+
+func TestNewWithT(t *testing.T) {
+	lg := logzetest.New(t)
+	lg.Info("message is shown only if the test fails or with -v flag")
+}
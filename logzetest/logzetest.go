@@ -0,0 +1,72 @@
+// Package logzetest provides helpers for testing code that logs with logze.
+package logzetest
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// ErrTimeout is returned when an expected entry is not written in time.
+var ErrTimeout = errors.New("entry is not written in time")
+
+// WaitFor blocks until an entry matching the predicate is written by the logger or timeout expires.
+// It uses a post-write hook, so it works with diode and other asynchronous writers without sleeps.
+// Entries written before the call are not checked, use [Expect] to subscribe before running the code under test.
+func WaitFor(lg logze.Logger, match func(logze.Entry) bool, timeout time.Duration) (logze.Entry, error) {
+	return Expect(lg, match).Wait(timeout)
+}
+
+// Waiter waits for an entry matching a predicate, it is returned by [Expect].
+type Waiter struct {
+	done   chan struct{}
+	once   sync.Once
+	entry  logze.Entry
+	remove func()
+}
+
+// Expect starts watching for an entry matching the predicate that is written by the logger.
+// Call [Waiter.Wait] to block until the entry is written:
+//
+//	w := logzetest.Expect(lg, logzetest.Message("job done"))
+//	go runJob(lg)
+//	if _, err := w.Wait(time.Second); err != nil {
+//		t.Fatal(err)
+//	}
+func Expect(lg logze.Logger, match func(logze.Entry) bool) *Waiter {
+	w := &Waiter{done: make(chan struct{})}
+	w.remove = lg.AddPostWriteHook(func(e logze.Entry, _ int, err error) {
+		if err != nil || !match(e) {
+			return
+		}
+		w.once.Do(func() {
+			w.entry = e
+			close(w.done)
+		})
+	})
+	return w
+}
+
+// Wait blocks until an expected entry is written and returns it or [ErrTimeout] if timeout expires.
+func (w *Waiter) Wait(timeout time.Duration) (logze.Entry, error) {
+	defer w.remove()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-w.done:
+		return w.entry, nil
+	case <-timer.C:
+		return logze.Entry{}, ErrTimeout
+	}
+}
+
+// Message returns a predicate for [WaitFor] matching entries with provided message.
+func Message(msg string) func(logze.Entry) bool {
+	return func(e logze.Entry) bool {
+		return e.Message == msg
+	}
+}
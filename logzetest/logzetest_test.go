@@ -0,0 +1,44 @@
+package logzetest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/logzetest"
+)
+
+func TestWaitFor(t *testing.T) {
+	var b bytes.Buffer
+	lg := logze.New(logze.NewConfig(&b).WithDiodePollingInterval(50 * time.Millisecond))
+
+	w := logzetest.Expect(lg, logzetest.Message("job done"))
+	go func() {
+		lg.Info("job started")
+		lg.Info("job done", "id", 1)
+	}()
+
+	e, err := w.Wait(time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Fields["id"] != json.Number("1") {
+		t.Errorf("expected id field, got %v", e.Fields)
+	}
+	if !bytes.Contains(b.Bytes(), []byte("job done")) {
+		t.Errorf("expected entry to be written, got %s", b.String())
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	lg := logze.New(logze.NewConfig(&bytes.Buffer{}).WithNoDiode())
+	lg.Info("written before wait")
+
+	_, err := logzetest.WaitFor(lg, logzetest.Message("written before wait"), 20*time.Millisecond)
+	if !errors.Is(err, logzetest.ErrTimeout) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package logzetest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// Recorder is an [io.Writer] that parses written JSON entries, so tests can check them without
+// matching raw JSON strings. It is safe for concurrent use.
+//
+//	lg, rec := logzetest.Record()
+//	handle(lg)
+//	rec.AssertLogged(t, logze.LevelError, "cannot handle")
+type Recorder struct {
+	mu      sync.Mutex
+	entries []logze.Entry
+}
+
+// NewRecorder returns an empty [Recorder], use it as a writer of [logze.Config].
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Record returns a logger of trace level that writes only to a new [Recorder] without diode,
+// so entries are recorded as soon as they are logged.
+func Record(opts ...Option) (logze.Logger, *Recorder) {
+	rec := NewRecorder()
+	cfg := logze.NewConfig(rec).WithLevel(logze.LevelTrace).WithNoDiode()
+	for _, opt := range opts {
+		cfg = opt(cfg)
+	}
+	return logze.New(cfg), rec
+}
+
+// Write parses entries from p, one per line. Lines that are not JSON entries are recorded
+// as entries without level with the line as a message.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		e, err := logze.ParseEntry(line)
+		if err != nil {
+			e = logze.Entry{Message: string(line)}
+		}
+		r.entries = append(r.entries, e)
+	}
+	return len(p), nil
+}
+
+// ParsesEntries marks the recorder as [logze.EntryParser], so it gets entries with standard field names.
+func (r *Recorder) ParsesEntries() {}
+
+// Entries returns a copy of recorded entries.
+func (r *Recorder) Entries() []logze.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]logze.Entry(nil), r.entries...)
+}
+
+// Len returns a number of recorded entries.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Filter returns recorded entries matching the predicate.
+func (r *Recorder) Filter(match func(logze.Entry) bool) []logze.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []logze.Entry
+	for _, e := range r.entries {
+		if match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// CountAtLevel returns a number of recorded entries of provided level.
+func (r *Recorder) CountAtLevel(level string) int {
+	return len(r.Filter(Level(level)))
+}
+
+// AssertLogged reports an error if there is no recorded entry of provided level with a message containing
+// provided substring and returns the first matching entry. Empty level matches entries of any level.
+func (r *Recorder) AssertLogged(t testing.TB, level, msgSubstring string) logze.Entry {
+	t.Helper()
+	found := r.Filter(matchLogged(level, msgSubstring))
+	if len(found) == 0 {
+		t.Errorf("expected %s entry containing %q, got %s", levelName(level), msgSubstring, r.dump())
+		return logze.Entry{}
+	}
+	return found[0]
+}
+
+// AssertNotLogged reports an error if there is a recorded entry of provided level with a message containing
+// provided substring. Empty level matches entries of any level, e.g. use AssertNotLogged(t, logze.LevelError, "")
+// to check that no errors were logged.
+func (r *Recorder) AssertNotLogged(t testing.TB, level, msgSubstring string) {
+	t.Helper()
+	if found := r.Filter(matchLogged(level, msgSubstring)); len(found) > 0 {
+		t.Errorf("expected no %s entries containing %q, got %q", levelName(level), msgSubstring, found[0].Message)
+	}
+}
+
+// Reset removes all recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+func (r *Recorder) dump() string {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return "no entries"
+	}
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, "["+levelName(e.Level)+"] "+e.Message)
+	}
+	return strings.Join(lines, "; ")
+}
+
+// Level returns a predicate for [Recorder.Filter] and [WaitFor] matching entries with provided level.
+func Level(level string) func(logze.Entry) bool {
+	return func(e logze.Entry) bool {
+		return e.Level == level
+	}
+}
+
+func matchLogged(level, msgSubstring string) func(logze.Entry) bool {
+	return func(e logze.Entry) bool {
+		return (level == "" || e.Level == level) && strings.Contains(e.Message, msgSubstring)
+	}
+}
+
+func levelName(level string) string {
+	if level == "" {
+		return "any"
+	}
+	return level
+}
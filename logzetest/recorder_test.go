@@ -0,0 +1,45 @@
+package logzetest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/logzetest"
+)
+
+func TestRecorder(t *testing.T) {
+	lg, rec := logzetest.Record(logzetest.WithLevel(logze.LevelDebug))
+
+	lg.Trace("trace message")
+	lg.Info("request handled", "status", 200)
+	lg.Err(errors.New("some error"), "cannot handle request")
+	lg.Write([]byte("raw line\n"))
+
+	if rec.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", rec.Len())
+	}
+	if n := rec.CountAtLevel(logze.LevelInfo); n != 1 {
+		t.Errorf("expected 1 info entry, got %d", n)
+	}
+	e := rec.AssertLogged(t, logze.LevelInfo, "handled")
+	if e.Fields["status"] != json.Number("200") {
+		t.Errorf("expected status field, got %v", e.Fields)
+	}
+	rec.AssertLogged(t, logze.LevelError, "cannot handle")
+	rec.AssertLogged(t, "", "raw line")
+	rec.AssertNotLogged(t, logze.LevelWarn, "")
+
+	fake := &fakeTB{TB: t}
+	rec.AssertLogged(fake, logze.LevelWarn, "handled")
+	rec.AssertNotLogged(fake, logze.LevelError, "")
+	if len(fake.errors) != 2 {
+		t.Errorf("expected 2 failed assertions, got %v", fake.errors)
+	}
+
+	rec.Reset()
+	if rec.Len() != 0 {
+		t.Errorf("expected no entries after reset, got %d", rec.Len())
+	}
+}
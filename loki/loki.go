@@ -0,0 +1,340 @@
+// Package loki provides an [io.Writer] that batches log entries and pushes them to Grafana Loki
+// using its HTTP push API, so logs can be shipped without promtail or another agent:
+//
+//	w := loki.New("http://loki:3100", loki.WithLabels(map[string]string{"app": "billing"}), loki.WithLabelFields("level"))
+//	logger := logze.New(logze.NewConfig(w))
+//	defer logger.Close()
+//
+// Entries are sent in background. Failed pushes are retried with exponential backoff, entries are kept
+// in a bounded queue and new entries are dropped when it is full, so a slow Loki doesn't block logging calls.
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/internal/batcher"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureLoki)
+}
+
+const (
+	// PushPath is a path of the push API of Loki.
+	PushPath = "/loki/api/v1/push"
+
+	// DefaultBatchSize is a default max number of entries in one push request.
+	DefaultBatchSize = 1000
+	// DefaultBatchWait is a default max time an entry waits in a batch before it is pushed.
+	DefaultBatchWait = time.Second
+	// DefaultQueueSize is a default max number of entries waiting to be pushed.
+	DefaultQueueSize = 10000
+	// DefaultMinBackoff is a default delay before the first retry of a failed push.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is a default max delay between retries of a failed push.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultMaxRetries is a default max number of retries of a failed push.
+	DefaultMaxRetries = 10
+)
+
+// ErrQueueFull is passed to the drop callback when entries are dropped because the queue is full.
+var ErrQueueFull = errors.New("loki: queue is full")
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithLabels sets static labels of all streams, e.g. app and env.
+func WithLabels(labels map[string]string) Option {
+	return func(o *options) {
+		for k, v := range labels {
+			o.labels[k] = v
+		}
+	}
+}
+
+// WithLabelFields sets names of fields of entries which values become labels, e.g. level and service.
+// Keep the number of their values small, every set of labels is a separate stream in Loki.
+func WithLabelFields(fields ...string) Option {
+	return func(o *options) {
+		o.labelFields = append(o.labelFields, fields...)
+	}
+}
+
+// WithBatch sets a max number of entries in one push request and a max time an entry waits in a batch,
+// default values are [DefaultBatchSize] and [DefaultBatchWait].
+func WithBatch(size int, wait time.Duration) Option {
+	return func(o *options) {
+		o.batchSize = size
+		o.batchWait = wait
+	}
+}
+
+// WithQueueSize sets a max number of entries waiting to be pushed, default value is [DefaultQueueSize].
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithRetry sets a delay before the first retry of a failed push, a max delay between retries and a max number
+// of retries, default values are [DefaultMinBackoff], [DefaultMaxBackoff] and [DefaultMaxRetries].
+// Responses with 429 and 5xx codes and network errors are retried, other errors drop the batch.
+func WithRetry(minBackoff, maxBackoff time.Duration, maxRetries int) Option {
+	return func(o *options) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithTenant sets X-Scope-OrgID header for multi-tenant Loki.
+func WithTenant(tenant string) Option {
+	return WithHeader("X-Scope-OrgID", tenant)
+}
+
+// WithHeader adds a header to push requests, e.g. Authorization.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithHTTPClient sets a client of push requests, default value is a client with 10s timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithOnDrop sets a function that is called with a number of dropped entries and the reason,
+// default function prints a warning to stderr.
+func WithOnDrop(onDrop func(n int, err error)) Option {
+	return func(o *options) {
+		o.onDrop = onDrop
+	}
+}
+
+type options struct {
+	labels      map[string]string
+	labelFields []string
+	batchSize   int
+	batchWait   time.Duration
+	queueSize   int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxRetries  int
+	headers     http.Header
+	client      *http.Client
+	onDrop      func(n int, err error)
+}
+
+type entry struct {
+	ts   time.Time
+	line []byte
+}
+
+// Writer pushes entries to Loki in background, it is safe for concurrent use.
+// [Writer.Close] must be called to push the rest of entries.
+type Writer struct {
+	url string
+	o   options
+	b   *batcher.Batcher[entry]
+}
+
+// New returns [Writer] pushing entries to Loki with provided base URL (e.g. "http://loki:3100")
+// and starts its background goroutine.
+func New(url string, opts ...Option) *Writer {
+	o := options{
+		labels:     make(map[string]string),
+		batchSize:  DefaultBatchSize,
+		batchWait:  DefaultBatchWait,
+		queueSize:  DefaultQueueSize,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		maxRetries: DefaultMaxRetries,
+		headers:    make(http.Header),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		onDrop: func(n int, err error) {
+			fmt.Fprintf(os.Stderr, "WRN: loki dropped %d entries: %v\n", n, err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = DefaultBatchSize
+	}
+	if o.batchWait <= 0 {
+		o.batchWait = DefaultBatchWait
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = DefaultQueueSize
+	}
+
+	w := &Writer{url: strings.TrimSuffix(url, "/") + PushPath, o: o}
+	w.b = batcher.New(batcher.Config[entry]{
+		Size:         o.batchSize,
+		Wait:         o.batchWait,
+		QueueSize:    o.queueSize,
+		MinBackoff:   o.minBackoff,
+		MaxBackoff:   o.maxBackoff,
+		MaxRetries:   o.maxRetries,
+		ErrQueueFull: ErrQueueFull,
+		OnDrop: func(entries []entry, err error) {
+			if o.onDrop != nil {
+				o.onDrop(len(entries), err)
+			}
+		},
+	}, w.send)
+	return w
+}
+
+// Write adds a copy of an entry to the queue, it never blocks. If the queue is full, the entry is dropped.
+// It returns [os.ErrClosed] after [Writer.Close].
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.b.Add(entry{ts: time.Now(), line: bytes.TrimRight(append([]byte(nil), p...), "\n")}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush pushes entries that are written before the call and returns an error of the push.
+func (w *Writer) Flush() error {
+	return w.b.Flush()
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *Writer) Dropped() int64 {
+	return w.b.Dropped()
+}
+
+// Close stops retrying, pushes the rest of entries once and stops the background goroutine.
+func (w *Writer) Close() error {
+	if n := w.b.Close(); n > 0 {
+		return fmt.Errorf("loki: %d entries dropped on close", n)
+	}
+	return nil
+}
+
+// send makes a push request, it returns the batch if the error can be retried.
+func (w *Writer) send(batch []entry) ([]entry, error) {
+	body, err := w.encode(batch)
+	if err != nil {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	req.Header = w.o.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.o.client.Do(req)
+	if err != nil {
+		return batch, fmt.Errorf("loki: %w", err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 == 2 {
+		return nil, nil
+	}
+
+	err = fmt.Errorf("loki: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s > 0 {
+		return batch, batcher.RetryAfter(time.Duration(s)*time.Second, err)
+	}
+	return batch, err
+}
+
+type pushRequest struct {
+	Streams []*stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encode groups entries by their labels and returns a body of a push request.
+func (w *Writer) encode(batch []entry) ([]byte, error) {
+	streams := make(map[string]*stream)
+	var keys []string
+	for _, e := range batch {
+		labels := w.labels(e.line)
+		key := labelsKey(labels)
+		s, ok := streams[key]
+		if !ok {
+			s = &stream{Stream: labels}
+			streams[key] = s
+			keys = append(keys, key)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.ts.UnixNano(), 10), string(e.line)})
+	}
+
+	req := pushRequest{Streams: make([]*stream, 0, len(keys))}
+	for _, key := range keys {
+		req.Streams = append(req.Streams, streams[key])
+	}
+	return json.Marshal(req)
+}
+
+// labels returns static labels with values of label fields of an entry. Loki rejects streams without labels,
+// so "job" label with a name of the executable is used if there are no other labels.
+func (w *Writer) labels(line []byte) map[string]string {
+	labels := make(map[string]string, len(w.o.labels)+len(w.o.labelFields))
+	for k, v := range w.o.labels {
+		labels[k] = v
+	}
+	var fields map[string]any
+	if len(w.o.labelFields) > 0 {
+		_ = json.Unmarshal(line, &fields)
+	}
+	for _, name := range w.o.labelFields {
+		switch v := fields[name].(type) {
+		case nil:
+		case string:
+			labels[name] = v
+		default:
+			labels[name] = fmt.Sprint(v)
+		}
+	}
+	if len(labels) == 0 {
+		labels["job"] = filepath.Base(os.Args[0])
+	}
+	return labels
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(labels[k])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
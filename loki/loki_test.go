@@ -0,0 +1,171 @@
+package loki_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/loki"
+)
+
+type pushRequest struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+type fakeLoki struct {
+	mu       sync.Mutex
+	requests []pushRequest
+	headers  []http.Header
+	fails    atomic.Int32
+	status   int
+}
+
+func (f *fakeLoki) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != loki.PushPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if f.fails.Add(-1) >= 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	var req pushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.headers = append(f.headers, r.Header)
+	f.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeLoki) lines() map[string][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string][]string)
+	for _, req := range f.requests {
+		for _, s := range req.Streams {
+			for _, v := range s.Values {
+				out[s.Stream["level"]] = append(out[s.Stream["level"]], v[1])
+			}
+		}
+	}
+	return out
+}
+
+func TestWriter(t *testing.T) {
+	f := &fakeLoki{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	w := loki.New(srv.URL, loki.WithLabels(map[string]string{"app": "billing"}), loki.WithLabelFields("level"),
+		loki.WithTenant("team-a"))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Info("first")
+	logger.Warn("second")
+	logger.Info("third")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := f.lines()
+	if len(lines["info"]) != 2 || len(lines["warn"]) != 1 {
+		t.Fatalf("expected 2 info and 1 warn lines, got %v", lines)
+	}
+	if !strings.Contains(lines["info"][1], `"message":"third"`) {
+		t.Errorf("expected entries in order, got %v", lines["info"])
+	}
+	f.mu.Lock()
+	if app := f.requests[0].Streams[0].Stream["app"]; app != "billing" {
+		t.Errorf("expected app label, got %q", app)
+	}
+	if tenant := f.headers[0].Get("X-Scope-OrgID"); tenant != "team-a" {
+		t.Errorf("expected tenant header, got %q", tenant)
+	}
+	f.mu.Unlock()
+
+	logger.Info("last")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := f.lines(); len(lines["info"]) != 3 {
+		t.Errorf("expected entries to be pushed on close, got %v", lines)
+	}
+}
+
+func TestWriterRetry(t *testing.T) {
+	f := &fakeLoki{status: http.StatusServiceUnavailable}
+	f.fails.Store(2)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	w := loki.New(srv.URL, loki.WithRetry(time.Millisecond, 10*time.Millisecond, 5))
+	defer w.Close()
+	if _, err := w.Write([]byte(`{"message":"message"}` + "\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := f.lines()
+	if len(lines[""]) != 1 || lines[""][0] != `{"message":"message"}` {
+		t.Errorf("expected message after retries, got %v", lines)
+	}
+	f.mu.Lock()
+	if job := f.requests[0].Streams[0].Stream["job"]; job == "" {
+		t.Errorf("expected default job label")
+	}
+	f.mu.Unlock()
+}
+
+func TestWriterDrop(t *testing.T) {
+	f := &fakeLoki{status: http.StatusBadRequest}
+	f.fails.Store(1)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	var dropped atomic.Int64
+	w := loki.New(srv.URL, loki.WithQueueSize(10), loki.WithBatch(10, time.Hour),
+		loki.WithOnDrop(func(n int, err error) { dropped.Add(int64(n)) }))
+	defer w.Close()
+
+	// Bad request is not retried
+	_, _ = w.Write([]byte(`{"message":"bad"}`))
+	if err := w.Flush(); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected 400 error, got %v", err)
+	}
+	if dropped.Load() != 1 || w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", dropped.Load())
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := loki.New("http://127.0.0.1:1")
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("message")); err == nil {
+		t.Errorf("expected error after close")
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("expected nil error after close, got %v", err)
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureLoki) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureLoki, logze.Features())
+	}
+}
@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// logrusLevels maps logrus level methods to logze methods.
+var logrusLevels = map[string]string{
+	"Trace":   "Trace",
+	"Debug":   "Debug",
+	"Info":    "Info",
+	"Warn":    "Warn",
+	"Warning": "Warn",
+	"Error":   "Error",
+}
+
+// rewriteLogrus rewrites calls like logrus.WithField("k", v).WithError(err).Error("msg") and logrus.Infof("msg %d", n).
+func (r *rewriter) rewriteLogrus(call *ast.CallExpr) *ast.CallExpr {
+	sel, ok := selector(call.Fun)
+	if !ok || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return nil
+	}
+	name := sel.Sel.Name
+	format := strings.HasSuffix(name, "f")
+	level, ok := logrusLevels[strings.TrimSuffix(name, "f")]
+	if !ok || !format && len(call.Args) != 1 {
+		// logrus joins multiple args with fmt.Sprint
+		return nil
+	}
+
+	var (
+		fields []ast.Expr
+		err    ast.Expr
+	)
+	x := sel.X
+	for {
+		inner, ok := x.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		isel, ok := selector(inner.Fun)
+		if !ok {
+			return nil
+		}
+		switch {
+		case isel.Sel.Name == "WithField" && len(inner.Args) == 2:
+			fields = append([]ast.Expr{inner.Args[0], inner.Args[1]}, fields...)
+		case isel.Sel.Name == "WithFields" && len(inner.Args) == 1:
+			lit, ok := inner.Args[0].(*ast.CompositeLit)
+			if !ok {
+				return nil
+			}
+			var pairs []ast.Expr
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return nil
+				}
+				pairs = append(pairs, kv.Key, kv.Value)
+			}
+			fields = append(pairs, fields...)
+		case isel.Sel.Name == "WithError" && len(inner.Args) == 1 && err == nil:
+			err = inner.Args[0]
+		default:
+			return nil
+		}
+		x = isel.X
+	}
+
+	id, isIdent := x.(*ast.Ident)
+	isPkg := isIdent && id.Name == r.imports[LogrusPath]
+	if !isPkg && len(fields) == 0 && err == nil {
+		// Call of a logger variable that is already compatible with logze
+		return nil
+	}
+	recv := r.receiver(x, LogrusPath)
+
+	msg, args := call.Args[0], call.Args[1:]
+	return logCall(recv, level, format, err, msg, args, fields)
+}
@@ -0,0 +1,306 @@
+// Package migrate rewrites common zerolog, zap and logrus call sites into idiomatic logze calls
+// to accelerate adoption of logze in existing services. Examples of rewritten calls:
+//
+//	log.Info().Str("user", id).Int("n", n).Msg("logged in")  ->  logze.Info("logged in", "user", id, "n", n)
+//	log.Error().Err(err).Msgf("cannot %s", op)               ->  logze.Errf(err, "cannot %s", op)
+//	logger.Error("failed", zap.String("path", p), zap.Error(err)) ->  logger.Err(err, "failed", "path", p)
+//	sugar.Infow("started", "port", port)                     ->  sugar.Info("started", "port", port)
+//	logrus.WithField("user", id).WithError(err).Error("failed") ->  logze.Err(err, "failed", "user", id)
+//
+// Calls of package-level loggers (github.com/rs/zerolog/log, github.com/sirupsen/logrus, zap.L() and zap.S())
+// are replaced with calls of logze global logger, calls of logger variables keep their receivers, so types of these
+// variables should be changed to [logze.Logger] by hand. Calls that cannot be expressed with logze
+// (unknown field methods, Fatal and Panic levels with fields, zap loggers returned by other calls, etc.) are left untouched.
+// Imports are updated: logze is added and imports of replaced packages are removed if they are not used anymore.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+)
+
+// Import paths of supported packages.
+const (
+	LogzePath      = "github.com/maxbolgarin/logze/v2"
+	ZerologPath    = "github.com/rs/zerolog"
+	ZerologLogPath = "github.com/rs/zerolog/log"
+	ZapPath        = "go.uber.org/zap"
+	LogrusPath     = "github.com/sirupsen/logrus"
+)
+
+// Result is a result of rewriting of a file.
+type Result struct {
+	// Source is a formatted source of a rewritten file.
+	Source []byte
+
+	// Rewritten is a number of rewritten calls.
+	Rewritten int
+}
+
+// Rewrite rewrites zerolog, zap and logrus calls in a source of a Go file. Filename is used only in error messages.
+func Rewrite(filename string, src []byte) (Result, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return Result{}, fmt.Errorf("parse: %w", err)
+	}
+
+	r := newRewriter(f)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			r.rewriteCall(call)
+		}
+		return true
+	})
+	if r.rewritten == 0 {
+		return Result{Source: src}, nil
+	}
+	r.fixImports()
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return Result{}, fmt.Errorf("format: %w", err)
+	}
+	return Result{Source: buf.Bytes(), Rewritten: r.rewritten}, nil
+}
+
+// RewriteFile rewrites calls in a file in place and returns a number of rewritten calls.
+func RewriteFile(path string) (int, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read file: %w", err)
+	}
+	res, err := Rewrite(path, src)
+	if err != nil {
+		return 0, err
+	}
+	if res.Rewritten == 0 {
+		return 0, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("stat file: %w", err)
+	}
+	if err := os.WriteFile(path, res.Source, info.Mode()); err != nil {
+		return 0, fmt.Errorf("write file: %w", err)
+	}
+	return res.Rewritten, nil
+}
+
+type rewriter struct {
+	file      *ast.File
+	imports   map[string]string // path -> local name
+	logzeName string
+	usesLogze bool
+	rewritten int
+}
+
+func newRewriter(f *ast.File) *rewriter {
+	r := &rewriter{file: f, imports: make(map[string]string)}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := defaultImportName(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		r.imports[path] = name
+	}
+	r.logzeName = "logze"
+	if name, ok := r.imports[LogzePath]; ok {
+		r.logzeName = name
+	}
+	return r
+}
+
+func (r *rewriter) rewriteCall(call *ast.CallExpr) {
+	var out *ast.CallExpr
+	switch {
+	case r.imports[ZerologPath] != "" || r.imports[ZerologLogPath] != "":
+		out = r.rewriteZerolog(call)
+	}
+	if out == nil && r.imports[ZapPath] != "" {
+		out = r.rewriteZap(call)
+	}
+	if out == nil && r.imports[LogrusPath] != "" {
+		out = r.rewriteLogrus(call)
+	}
+	if out == nil {
+		return
+	}
+	*call = *out
+	r.rewritten++
+}
+
+// receiver returns a receiver for rewritten call: logze package for package-level loggers or the same expression.
+func (r *rewriter) receiver(base ast.Expr, pkgPath string) ast.Expr {
+	if id, ok := base.(*ast.Ident); ok && pkgPath != "" && id.Name == r.imports[pkgPath] {
+		r.usesLogze = true
+		return ast.NewIdent(r.logzeName)
+	}
+	return base
+}
+
+// logCall builds a call of logze logging method. Error is passed as the first argument of Err and Errf methods
+// for error level, for other levels it is added as a field.
+func logCall(recv ast.Expr, level string, format bool, err ast.Expr, msg ast.Expr, args, fields []ast.Expr) *ast.CallExpr {
+	method := level
+	var callArgs []ast.Expr
+	if err != nil {
+		if level == "Error" {
+			method = "Err"
+			callArgs = append(callArgs, err)
+		} else {
+			fields = append([]ast.Expr{stringLit("error"), err}, fields...)
+		}
+	}
+	if format {
+		method += "f"
+	}
+	callArgs = append(callArgs, msg)
+	callArgs = append(callArgs, args...)
+	callArgs = append(callArgs, fields...)
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: recv, Sel: ast.NewIdent(method)},
+		Args: callArgs,
+	}
+}
+
+func (r *rewriter) fixImports() {
+	_, added := r.imports[LogzePath]
+	added = added || !r.usesLogze
+	for _, path := range []string{ZerologLogPath, ZerologPath, ZapPath, LogrusPath} {
+		name, ok := r.imports[path]
+		if !ok || usesName(r.file, name) {
+			continue
+		}
+		if !added {
+			// Reuse a spec to keep grouping of imports
+			replaceImport(r.file, path, LogzePath)
+			added = true
+			continue
+		}
+		deleteImport(r.file, path)
+	}
+	if !added {
+		addImport(r.file, LogzePath)
+	}
+}
+
+func replaceImport(f *ast.File, oldPath, newPath string) {
+	quoted := strconv.Quote(oldPath)
+	for _, imp := range f.Imports {
+		if imp.Path.Value == quoted {
+			imp.Name = nil
+			imp.Path.Value = strconv.Quote(newPath)
+			return
+		}
+	}
+}
+
+func addImport(f *ast.File, path string) {
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if !gd.Lparen.IsValid() {
+			// Single import without parens
+			gd.Lparen = gd.Pos()
+			gd.Rparen = gd.End()
+		}
+		gd.Specs = append(gd.Specs, spec)
+		f.Imports = append(f.Imports, spec)
+		return
+	}
+	gd := &ast.GenDecl{Tok: token.IMPORT, Specs: []ast.Spec{spec}}
+	f.Decls = append([]ast.Decl{gd}, f.Decls...)
+	f.Imports = append(f.Imports, spec)
+}
+
+func deleteImport(f *ast.File, path string) {
+	quoted := strconv.Quote(path)
+	for i := 0; i < len(f.Decls); i++ {
+		gd, ok := f.Decls[i].(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		for j, spec := range gd.Specs {
+			if spec.(*ast.ImportSpec).Path.Value != quoted {
+				continue
+			}
+			gd.Specs = append(gd.Specs[:j], gd.Specs[j+1:]...)
+			if len(gd.Specs) == 0 {
+				f.Decls = append(f.Decls[:i], f.Decls[i+1:]...)
+			}
+			break
+		}
+	}
+	for i, imp := range f.Imports {
+		if imp.Path.Value == quoted {
+			f.Imports = append(f.Imports[:i], f.Imports[i+1:]...)
+			break
+		}
+	}
+}
+
+// usesName returns true if the file has selector expressions with provided package name.
+func usesName(f *ast.File, name string) bool {
+	used := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name {
+				used = true
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+func defaultImportName(path string) string {
+	switch path {
+	case LogzePath:
+		return "logze"
+	case ZerologLogPath:
+		return "log"
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func stringLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+func selector(e ast.Expr) (*ast.SelectorExpr, bool) {
+	sel, ok := e.(*ast.SelectorExpr)
+	return sel, ok
+}
+
+// isPkgCall returns true if the expression is a call of a function from a package with provided name.
+func isPkgCall(e ast.Expr, pkgName string) (*ast.CallExpr, string, bool) {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return nil, "", false
+	}
+	sel, ok := selector(call.Fun)
+	if !ok {
+		return nil, "", false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok || id.Name != pkgName {
+		return nil, "", false
+	}
+	return call, sel.Sel.Name, true
+}
@@ -0,0 +1,144 @@
+package migrate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2/migrate"
+)
+
+func TestRewriteZerolog(t *testing.T) {
+	src := `package main
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	log.Info().Str("user", id).Int("n", 1).Msg("logged in")
+	log.Error().Err(err).Msgf("cannot %s", op)
+	log.Warn().Err(err).Send()
+	logger.Debug().Bool("ok", true).Msg("debug")
+	log.Info().Caller().Msg("unsupported")
+}
+`
+	res, err := migrate.Rewrite("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewrite(t, res, 4, []string{
+		`"github.com/maxbolgarin/logze/v2"`,
+		`logze.Info("logged in", "user", id, "n", 1)`,
+		`logze.Errf(err, "cannot %s", op)`,
+		`logze.Warn("", "error", err)`,
+		`logger.Debug("debug", "ok", true)`,
+		`log.Info().Caller().Msg("unsupported")`,
+		`"github.com/rs/zerolog/log"`,
+	})
+}
+
+func TestRewriteZap(t *testing.T) {
+	src := `package main
+
+import "go.uber.org/zap"
+
+func run(logger *zap.Logger, sugar *zap.SugaredLogger) {
+	logger.Error("failed", zap.String("path", p), zap.Error(err))
+	logger.Info("started", zap.Int("port", 80))
+	sugar.Infow("started", "port", 80)
+	logger.Info("unsupported", zap.Object("obj", o))
+}
+`
+	res, err := migrate.Rewrite("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewrite(t, res, 3, []string{
+		`logger.Err(err, "failed", "path", p)`,
+		`logger.Info("started", "port", 80)`,
+		`sugar.Info("started", "port", 80)`,
+		`zap.Object("obj", o)`,
+	})
+}
+
+func TestRewriteZapGlobal(t *testing.T) {
+	src := `package main
+
+import "go.uber.org/zap"
+
+func run(s *server) {
+	zap.L().Info("global", zap.String("a", "b"))
+	zap.S().Infow("sugared", "a", 1)
+	s.log.Warn("field", zap.Int("n", 1))
+	newLogger().Info("call", zap.String("a", "b"))
+	newSugar().Infow("call", "a", 1)
+}
+`
+	res, err := migrate.Rewrite("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewrite(t, res, 3, []string{
+		`logze.Info("global", "a", "b")`,
+		`logze.Info("sugared", "a", 1)`,
+		`s.log.Warn("field", "n", 1)`,
+		`newLogger().Info("call", zap.String("a", "b"))`,
+		`newSugar().Infow("call", "a", 1)`,
+		`"github.com/maxbolgarin/logze/v2"`,
+		`"go.uber.org/zap"`,
+	})
+}
+
+func TestRewriteLogrus(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	log.WithField("user", id).WithError(err).Error("failed")
+	log.WithFields(log.Fields{"a": 1, "b": "c"}).Warningf("value %d", v)
+	log.Info("started")
+	fmt.Println("done")
+}
+`
+	res, err := migrate.Rewrite("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewrite(t, res, 3, []string{
+		`logze.Err(err, "failed", "user", id)`,
+		`logze.Warnf("value %d", v, "a", 1, "b", "c")`,
+		`logze.Info("started")`,
+		`"github.com/maxbolgarin/logze/v2"`,
+	})
+	if strings.Contains(string(res.Source), "sirupsen") {
+		t.Errorf("expected unused logrus import to be removed, got\n%s", res.Source)
+	}
+}
+
+func TestRewriteNothing(t *testing.T) {
+	src := "package main\n\nfunc main() {}\n"
+	res, err := migrate.Rewrite("main.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rewritten != 0 || string(res.Source) != src {
+		t.Errorf("expected unchanged source, got %d\n%s", res.Rewritten, res.Source)
+	}
+}
+
+func checkRewrite(t *testing.T, res migrate.Result, rewritten int, expected []string) {
+	t.Helper()
+	if res.Rewritten != rewritten {
+		t.Errorf("expected %d rewritten calls, got %d", rewritten, res.Rewritten)
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(res.Source), e) {
+			t.Errorf("expected %s, got\n%s", e, res.Source)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package migrate
+
+import "go/ast"
+
+// zapLevels maps levels of zap.Logger methods to logze methods.
+var zapLevels = map[string]string{
+	"Debug": "Debug",
+	"Info":  "Info",
+	"Warn":  "Warn",
+	"Error": "Error",
+}
+
+// zapSugarLevels maps zap.SugaredLogger methods with key-value pairs to logze methods.
+var zapSugarLevels = map[string]string{
+	"Debugw": "Debug",
+	"Infow":  "Info",
+	"Warnw":  "Warn",
+	"Errorw": "Error",
+}
+
+// zapFields is a set of zap field constructors with (key, value) arguments.
+var zapFields = map[string]bool{
+	"String": true, "Strings": true, "Stringer": true, "ByteString": true, "ByteStrings": true, "Binary": true,
+	"Int": true, "Int8": true, "Int16": true, "Int32": true, "Int64": true, "Ints": true, "Int32s": true, "Int64s": true,
+	"Uint": true, "Uint8": true, "Uint16": true, "Uint32": true, "Uint64": true, "Uints": true, "Uint64s": true, "Uintptr": true,
+	"Float32": true, "Float64": true, "Float32s": true, "Float64s": true,
+	"Bool": true, "Bools": true, "Duration": true, "Durations": true, "Time": true, "Times": true,
+	"Any": true, "Reflect": true, "NamedError": true, "Errors": true,
+}
+
+// rewriteZap rewrites logger.Info("msg", zap.String("k", v)) and sugar.Infow("msg", "k", v) calls.
+func (r *rewriter) rewriteZap(call *ast.CallExpr) *ast.CallExpr {
+	sel, ok := selector(call.Fun)
+	if !ok || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return nil
+	}
+	zap := r.imports[ZapPath]
+	recv, ok := r.zapReceiver(sel.X, zap)
+	if !ok {
+		return nil
+	}
+	if level, ok := zapSugarLevels[sel.Sel.Name]; ok {
+		return logCall(r.useReceiver(recv), level, false, nil, call.Args[0], nil, call.Args[1:])
+	}
+
+	level, ok := zapLevels[sel.Sel.Name]
+	if !ok || len(call.Args) < 2 {
+		return nil
+	}
+	var (
+		fields []ast.Expr
+		err    ast.Expr
+	)
+	for _, arg := range call.Args[1:] {
+		fieldCall, name, ok := isPkgCall(arg, zap)
+		if !ok {
+			return nil
+		}
+		switch {
+		case name == "Error" && len(fieldCall.Args) == 1 && err == nil:
+			err = fieldCall.Args[0]
+		case zapFields[name] && len(fieldCall.Args) == 2:
+			fields = append(fields, fieldCall.Args[0], fieldCall.Args[1])
+		default:
+			return nil
+		}
+	}
+	return logCall(r.useReceiver(recv), level, false, err, call.Args[0], nil, fields)
+}
+
+// zapReceiver returns a receiver of a rewritten call: nil for global loggers zap.L() and zap.S(),
+// that are replaced with logze global logger, or a logger variable or field. Other receivers
+// (e.g. results of calls) are not rewritten, because their types cannot be changed by hand.
+func (r *rewriter) zapReceiver(x ast.Expr, zap string) (ast.Expr, bool) {
+	if call, name, ok := isPkgCall(x, zap); ok {
+		return nil, (name == "L" || name == "S") && len(call.Args) == 0
+	}
+	return x, isVariable(x)
+}
+
+// isVariable returns true for identifiers and selectors of their fields, e.g. logger or s.log.
+func isVariable(x ast.Expr) bool {
+	switch e := x.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isVariable(e.X)
+	}
+	return false
+}
+
+// useReceiver returns logze package for nil receiver of a global logger.
+func (r *rewriter) useReceiver(recv ast.Expr) ast.Expr {
+	if recv != nil {
+		return recv
+	}
+	r.usesLogze = true
+	return ast.NewIdent(r.logzeName)
+}
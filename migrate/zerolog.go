@@ -0,0 +1,94 @@
+package migrate
+
+import "go/ast"
+
+// zerologLevels maps zerolog level methods to logze methods.
+var zerologLevels = map[string]string{
+	"Trace": "Trace",
+	"Debug": "Debug",
+	"Info":  "Info",
+	"Warn":  "Warn",
+	"Error": "Error",
+	"Fatal": "Fatal",
+}
+
+// zerologFields is a set of zerolog field methods with (key, value) arguments.
+var zerologFields = map[string]bool{
+	"Str": true, "Strs": true, "Stringer": true, "Bytes": true, "Hex": true,
+	"Int": true, "Int8": true, "Int16": true, "Int32": true, "Int64": true,
+	"Ints": true, "Ints8": true, "Ints16": true, "Ints32": true, "Ints64": true,
+	"Uint": true, "Uint8": true, "Uint16": true, "Uint32": true, "Uint64": true,
+	"Uints": true, "Uints8": true, "Uints16": true, "Uints32": true, "Uints64": true,
+	"Float32": true, "Float64": true, "Floats32": true, "Floats64": true,
+	"Bool": true, "Bools": true, "Dur": true, "Durs": true, "Time": true, "Times": true,
+	"Any": true, "Interface": true, "AnErr": true, "Errs": true, "IPAddr": true, "IPPrefix": true, "MACAddr": true,
+}
+
+// rewriteZerolog rewrites chains like log.Info().Str("k", v).Err(err).Msg("msg").
+func (r *rewriter) rewriteZerolog(call *ast.CallExpr) *ast.CallExpr {
+	sel, ok := selector(call.Fun)
+	if !ok {
+		return nil
+	}
+
+	var (
+		msg    ast.Expr
+		args   []ast.Expr
+		format bool
+	)
+	switch sel.Sel.Name {
+	case "Msg":
+		if len(call.Args) != 1 {
+			return nil
+		}
+		msg = call.Args[0]
+	case "Msgf":
+		if len(call.Args) < 1 || call.Ellipsis.IsValid() {
+			return nil
+		}
+		msg, args, format = call.Args[0], call.Args[1:], true
+	case "Send":
+		if len(call.Args) != 0 {
+			return nil
+		}
+		msg = stringLit("")
+	default:
+		return nil
+	}
+
+	var (
+		fields []ast.Expr
+		err    ast.Expr
+	)
+	x := sel.X
+	for {
+		inner, ok := x.(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+		isel, ok := selector(inner.Fun)
+		if !ok {
+			return nil
+		}
+		name := isel.Sel.Name
+
+		if level, ok := zerologLevels[name]; ok && len(inner.Args) == 0 {
+			if level == "Fatal" && (len(fields) > 0 || err != nil) {
+				// logze.Fatal doesn't accept fields
+				return nil
+			}
+			recv := r.receiver(isel.X, ZerologLogPath)
+			return logCall(recv, level, format, err, msg, args, fields)
+		}
+
+		switch {
+		case name == "Err" && len(inner.Args) == 1 && err == nil:
+			err = inner.Args[0]
+		case zerologFields[name] && len(inner.Args) == 2:
+			fields = append([]ast.Expr{inner.Args[0], inner.Args[1]}, fields...)
+		default:
+			return nil
+		}
+		x = isel.X
+	}
+}
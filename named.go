@@ -0,0 +1,208 @@
+package logze
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// LoggerFieldName is a field name for a name of a logger created with [Logger.Named].
+var LoggerFieldName = "logger"
+
+var registry = newNamedRegistry()
+
+// Named returns a child [Logger] with a name, that is added to every message as "logger" field.
+// Level of the returned logger can be changed at runtime for all loggers with the same name
+// using [SetNamedLevel], e.g. to get debug messages only from a database layer:
+//
+//	db := logze.Named("db")
+//	...
+//	logze.SetNamedLevel("db", logze.LevelDebug)
+//
+// Until the level is set by name, the logger follows the level of its parent.
+//
+// Names are hierarchical: calling Named on a named logger appends a name using a dot,
+// e.g. logze.Named("server").Named("http") has "server.http" name. Level set for "server"
+// applies to "server.http" and "server.http.h2" loggers unless they have their own level set by name.
+func (l Logger) Named(name string) Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	l.name = name
+	l.level = l.level.namedChild(registry.get(name))
+	l.cache = newFieldsCache()
+	return l
+}
+
+// SetNamedLevel atomically changes the level of all loggers with provided name created with [Logger.Named],
+// including loggers that will be created later, and their descendants in the names hierarchy
+// (e.g. "server.http" for "server") without their own level. It overrides the level of their parent loggers.
+func SetNamedLevel(name, level string) error {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return errors.New("cannot parse level=" + level)
+	}
+	registry.get(name).set(lvl)
+	return nil
+}
+
+// ResetNamedLevel makes loggers with provided name follow the level of their parents again.
+func ResetNamedLevel(name string) {
+	if n, ok := registry.lookup(name); ok {
+		n.reset()
+	}
+}
+
+// GetNamedLevel returns the level set with [SetNamedLevel] for loggers with provided name
+// or an empty string if the level is not set.
+func GetNamedLevel(name string) string {
+	n, ok := registry.lookup(name)
+	if !ok {
+		return ""
+	}
+	level, ok := n.getOwn()
+	if !ok {
+		return ""
+	}
+	return level.String()
+}
+
+// namedExists returns true if a logger with provided name is created with [Logger.Named]
+// or its level is set with [SetNamedLevel].
+func namedExists(name string) bool {
+	_, ok := registry.lookup(name)
+	return ok
+}
+
+// NamedLevels returns all levels set with [SetNamedLevel] by logger names.
+func NamedLevels() map[string]string {
+	names := registry.names()
+	out := make(map[string]string, len(names))
+	for _, name := range names {
+		if level := GetNamedLevel(name); level != "" {
+			out[name] = level
+		}
+	}
+	return out
+}
+
+// namedLevel is a level of all loggers with the same name, it can be unset.
+// Unset level follows the level of a parent name in the hierarchy.
+type namedLevel struct {
+	parent *namedLevel
+	v      atomic.Int32
+}
+
+const namedLevelUnset = math.MinInt32
+
+func newNamedLevel(parent *namedLevel) *namedLevel {
+	n := &namedLevel{parent: parent}
+	n.reset()
+	return n
+}
+
+// get returns the level of the nearest name in the hierarchy that has a level set.
+func (n *namedLevel) get() (zerolog.Level, bool) {
+	for ; n != nil; n = n.parent {
+		if v := n.v.Load(); v != namedLevelUnset {
+			return zerolog.Level(v), true
+		}
+	}
+	return 0, false
+}
+
+// getOwn returns the level set for this name.
+func (n *namedLevel) getOwn() (zerolog.Level, bool) {
+	v := n.v.Load()
+	return zerolog.Level(v), v != namedLevelUnset
+}
+
+func (n *namedLevel) set(level zerolog.Level) {
+	n.v.Store(int32(level))
+}
+
+func (n *namedLevel) reset() {
+	n.v.Store(namedLevelUnset)
+}
+
+type namedRegistry struct {
+	mu     sync.RWMutex
+	levels map[string]*namedLevel
+}
+
+func newNamedRegistry() *namedRegistry {
+	return &namedRegistry{levels: make(map[string]*namedLevel)}
+}
+
+// get returns a level of loggers with provided name creating it and its parents if they don't exist.
+func (r *namedRegistry) get(name string) *namedLevel {
+	r.mu.RLock()
+	n, ok := r.levels[name]
+	r.mu.RUnlock()
+	if ok {
+		return n
+	}
+
+	var parent *namedLevel
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		parent = r.get(name[:i])
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.levels[name]; ok {
+		return n
+	}
+	n = newNamedLevel(parent)
+	r.levels[name] = n
+	return n
+}
+
+// lookup returns a level of loggers with provided name, unlike get it doesn't create it.
+func (r *namedRegistry) lookup(name string) (*namedLevel, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, ok := r.levels[name]
+	return n, ok
+}
+
+// lower sets provided level for names that have a higher level set and returns their previous levels.
+func (r *namedRegistry) lower(level zerolog.Level) map[string]zerolog.Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prev := make(map[string]zerolog.Level)
+	for name, n := range r.levels {
+		if own, ok := n.getOwn(); ok && own > level {
+			prev[name] = own
+			n.set(level)
+		}
+	}
+	return prev
+}
+
+// restore sets levels returned by [namedRegistry.lower] back.
+func (r *namedRegistry) restore(prev map[string]zerolog.Level) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, level := range prev {
+		if n, ok := r.levels[name]; ok {
+			n.set(level)
+		}
+	}
+}
+
+func (r *namedRegistry) names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.levels))
+	for name := range r.levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
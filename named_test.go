@@ -0,0 +1,93 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestNamed(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode())
+	db := logger.Named("test-db")
+	http := logger.Named("test-http")
+	defer logze.ResetNamedLevel("test-db")
+
+	db.Info("db message")
+	if !strings.Contains(b.String(), `"logger":"test-db"`) {
+		t.Errorf("expected logger field, got %s", b.String())
+	}
+
+	b.Reset()
+	db.Debug("db debug")
+	if b.Len() != 0 {
+		t.Errorf("expected no debug message, got %s", b.String())
+	}
+
+	if err := logze.SetNamedLevel("test-db", logze.LevelDebug); err != nil {
+		t.Fatal(err)
+	}
+	db.Debug("db debug")
+	db.WithFields("foo", "bar").Debug("db child debug")
+	logger.Named("test-db").Debug("db new debug")
+	http.Debug("http debug")
+	if got := strings.Count(b.String(), "\n"); got != 3 || strings.Contains(b.String(), "http debug") {
+		t.Errorf("expected 3 debug messages from db, got %s", b.String())
+	}
+	if logze.GetNamedLevel("test-db") != logze.LevelDebug || logze.NamedLevels()["test-db"] != logze.LevelDebug {
+		t.Errorf("expected named level %s, got %s", logze.LevelDebug, logze.GetNamedLevel("test-db"))
+	}
+
+	b.Reset()
+	logze.ResetNamedLevel("test-db")
+	db.Debug("db debug")
+	if b.Len() != 0 {
+		t.Errorf("expected no debug message after reset, got %s", b.String())
+	}
+	if logze.GetNamedLevel("test-db") != "" {
+		t.Errorf("expected empty named level, got %s", logze.GetNamedLevel("test-db"))
+	}
+
+	if err := logze.SetNamedLevel("test-db", "unknown"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestNamedHierarchy(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode())
+	defer logze.ResetNamedLevel("server")
+	defer logze.ResetNamedLevel("server.http.h2")
+
+	h2 := logger.Named("server").Named("http").Named("h2")
+	h2.Info("message")
+	if !strings.Contains(b.String(), `"logger":"server.http.h2"`) {
+		t.Errorf("expected dotted name, got %s", b.String())
+	}
+
+	b.Reset()
+	if err := logze.SetNamedLevel("server", logze.LevelDebug); err != nil {
+		t.Fatal(err)
+	}
+	h2.Debug("h2 debug")
+	logger.Named("server.http").Debug("http debug")
+	logger.Named("serverless").Debug("other debug")
+	if strings.Count(b.String(), "\n") != 2 || strings.Contains(b.String(), "other debug") {
+		t.Errorf("expected 2 debug messages from server hierarchy, got %s", b.String())
+	}
+
+	b.Reset()
+	if err := logze.SetNamedLevel("server.http.h2", logze.LevelWarn); err != nil {
+		t.Fatal(err)
+	}
+	h2.Info("h2 info")
+	logger.Named("server.http").Debug("http debug")
+	if strings.Contains(b.String(), "h2 info") || !strings.Contains(b.String(), "http debug") {
+		t.Errorf("expected overridden level for h2, got %s", b.String())
+	}
+	if logze.GetNamedLevel("server.http") != "" {
+		t.Errorf("expected empty own level, got %s", logze.GetNamedLevel("server.http"))
+	}
+}
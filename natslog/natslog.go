@@ -0,0 +1,312 @@
+// Package natslog provides an [io.Writer] that publishes every log entry to a NATS subject
+// (optionally a subject of a JetStream stream), for log fan-out inside NATS-based services.
+//
+// The package doesn't depend on a NATS client, a connection is plugged in with [Publisher].
+// For example, with github.com/nats-io/nats.go:
+//
+//	w := natslog.New(natslog.PublisherFunc(func(ctx context.Context, msg *natslog.Msg) error {
+//		return nc.PublishMsg(&nats.Msg{Subject: msg.Subject, Data: msg.Data, Header: nats.Header(msg.Header)})
+//	}), "logs.billing", natslog.WithLevelSubject())
+//	logger := logze.New(logze.NewConfig(w))
+//
+// Use js.PublishMsg(ctx, ...) of a JetStream context to get acknowledgments of a stream, [WithMsgIDField]
+// enables deduplication of retried entries by JetStream.
+//
+// NATS client buffers messages while it is reconnecting, but it returns errors when the buffer is exceeded
+// or the connection is closed. Such entries are kept in a queue and published again with the next entry or [Writer.Flush],
+// so logs are not lost during short outages. An adapter may implement IsConnected() bool (like *nats.Conn does)
+// to queue entries without publishing while the connection is down.
+package natslog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureNATS)
+}
+
+// Default values of options.
+const (
+	DefaultTimeout   = 5 * time.Second
+	DefaultQueueSize = 1000
+)
+
+// MsgIDHeader is a header of JetStream with an ID of a message for deduplication, see [WithMsgIDField].
+const MsgIDHeader = "Nats-Msg-Id"
+
+// ServiceFieldName is a name of a field of entries with a name of a service, it is added to headers by default.
+var ServiceFieldName = "service"
+
+// ErrDisconnected is returned from [Writer.Flush] if a publisher reports that its connection is down.
+var ErrDisconnected = errors.New("not connected")
+
+// Msg is a message that is published for an entry.
+type Msg struct {
+	Subject string
+	Data    []byte
+	Header  map[string][]string
+}
+
+// Publisher publishes messages to NATS, it is implemented by an adapter of a NATS connection or JetStream context.
+// It may implement IsConnected() bool to report a state of a connection and Flush(ctx) error to wait for
+// messages that are buffered by a client.
+type Publisher interface {
+	Publish(ctx context.Context, msg *Msg) error
+}
+
+// PublisherFunc is an adapter to use a function as [Publisher].
+type PublisherFunc func(ctx context.Context, msg *Msg) error
+
+// Publish calls f(ctx, msg).
+func (f PublisherFunc) Publish(ctx context.Context, msg *Msg) error {
+	return f(ctx, msg)
+}
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithLevelSubject adds a level of entries to a subject, e.g. logs.billing.error, so subscribers can
+// subscribe to a subset of levels. Entries without level are published to logs.billing.none.
+func WithLevelSubject() Option {
+	return func(o *options) {
+		o.levelSubject = true
+	}
+}
+
+// WithHeaderFields sets names of fields of entries which values are added to headers of messages,
+// default value is level and [ServiceFieldName]. Missing fields are skipped.
+func WithHeaderFields(fields ...string) Option {
+	return func(o *options) {
+		o.headerFields = fields
+	}
+}
+
+// WithMsgIDField sets a name of a field of entries which value is added to [MsgIDHeader] header,
+// so JetStream drops duplicates of entries that are published again after an error. Default value is empty.
+func WithMsgIDField(field string) Option {
+	return func(o *options) {
+		o.msgIDField = field
+	}
+}
+
+// WithQueueSize sets a maximum number of entries that are kept while NATS is not available,
+// the oldest entries are dropped when the queue is full. Default value is [DefaultQueueSize].
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithTimeout sets a timeout of publishing one message, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithOnDrop sets a function that is called with a number of entries dropped because the queue is full.
+func WithOnDrop(f func(n int)) Option {
+	return func(o *options) {
+		o.onDrop = f
+	}
+}
+
+type options struct {
+	levelSubject bool
+	headerFields []string
+	msgIDField   string
+	queueSize    int
+	timeout      time.Duration
+	onDrop       func(n int)
+}
+
+// Writer publishes every written entry as a message with the entry as data. It is safe for concurrent use,
+// entries are published in order of writing. Entries that are dropped from the queue are reported as errors,
+// so they are counted in errors of [logze.Logger.Close].
+type Writer struct {
+	p       Publisher
+	subject string
+	o       options
+
+	mu      sync.Mutex
+	queue   []*Msg
+	dropped atomic.Int64
+}
+
+// New returns [Writer] that publishes entries to provided subject using the publisher.
+func New(p Publisher, subject string, opts ...Option) *Writer {
+	o := options{
+		headerFields: []string{zerolog.LevelFieldName, ServiceFieldName},
+		queueSize:    DefaultQueueSize,
+		timeout:      DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = DefaultQueueSize
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+	return &Writer{p: p, subject: subject, o: o}
+}
+
+// Write publishes a copy of an entry, the trailing newline is removed. If NATS is not available,
+// the entry is queued and published later, an error is returned only if queued entries are dropped.
+func (w *Writer) Write(p []byte) (int, error) {
+	msg := w.message(p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.publishQueue(); err == nil {
+		if err = w.publish(msg); err == nil {
+			return len(p), nil
+		}
+	}
+	if n := w.enqueue(msg); n > 0 {
+		return 0, fmt.Errorf("nats: %d entries dropped, queue is full", n)
+	}
+	return len(p), nil
+}
+
+// Flush publishes queued entries and waits for messages buffered by a client
+// if the publisher implements Flush(ctx) error.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	err := w.publishQueue()
+	w.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+
+	f, ok := w.p.(interface{ Flush(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := f.Flush(ctx); err != nil {
+		return fmt.Errorf("nats: %w", err)
+	}
+	return nil
+}
+
+// Close publishes queued entries, it returns an error with a number of entries that are not published.
+// It doesn't close a connection of the publisher.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	err := w.publishQueue()
+	n := len(w.queue)
+	w.queue = nil
+	w.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("nats: %d entries dropped on close: %w", n, err)
+	}
+	return w.Flush()
+}
+
+// Queued returns a number of entries that are waiting for NATS.
+func (w *Writer) Queued() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.queue)
+}
+
+// Dropped returns a number of entries dropped because the queue was full.
+func (w *Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Name returns a name of the writer for errors of [logze.Logger.Close].
+func (w *Writer) Name() string {
+	return "nats " + w.subject
+}
+
+func (w *Writer) message(p []byte) *Msg {
+	data := p
+	if n := len(data); n > 0 && data[n-1] == '\n' {
+		data = data[:n-1]
+	}
+	msg := &Msg{
+		Subject: w.subject,
+		Data:    append([]byte(nil), data...),
+	}
+	if !w.o.levelSubject && w.o.msgIDField == "" && len(w.o.headerFields) == 0 {
+		return msg
+	}
+
+	// Entries of the logger are valid JSON, other data is published without headers
+	e, _ := logze.ParseEntry(data)
+	if w.o.levelSubject {
+		level := e.Level
+		if level == "" {
+			level = "none"
+		}
+		msg.Subject += "." + level
+	}
+	for _, name := range w.o.headerFields {
+		if v, ok := e.FieldString(name); ok {
+			w.setHeader(msg, name, v)
+		}
+	}
+	if v, ok := e.FieldString(w.o.msgIDField); ok {
+		w.setHeader(msg, MsgIDHeader, v)
+	}
+	return msg
+}
+
+func (w *Writer) setHeader(msg *Msg, key, value string) {
+	if msg.Header == nil {
+		msg.Header = make(map[string][]string, len(w.o.headerFields)+1)
+	}
+	msg.Header[key] = []string{value}
+}
+
+// publishQueue publishes queued messages in order, it stops on the first error. It should be called under lock.
+func (w *Writer) publishQueue() error {
+	for len(w.queue) > 0 {
+		if err := w.publish(w.queue[0]); err != nil {
+			return err
+		}
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+	}
+	return nil
+}
+
+func (w *Writer) publish(msg *Msg) error {
+	if c, ok := w.p.(interface{ IsConnected() bool }); ok && !c.IsConnected() {
+		return ErrDisconnected
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	return w.p.Publish(ctx, msg)
+}
+
+// enqueue adds a message to the queue and returns a number of dropped messages. It should be called under lock.
+func (w *Writer) enqueue(msg *Msg) int {
+	dropped := 0
+	if len(w.queue) >= w.o.queueSize {
+		dropped = len(w.queue) - w.o.queueSize + 1
+		for i := 0; i < dropped; i++ {
+			w.queue[i] = nil
+		}
+		w.queue = w.queue[dropped:]
+		w.dropped.Add(int64(dropped))
+		if w.o.onDrop != nil {
+			w.o.onDrop(dropped)
+		}
+	}
+	w.queue = append(w.queue, msg)
+	return dropped
+}
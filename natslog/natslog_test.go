@@ -0,0 +1,119 @@
+package natslog_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/natslog"
+)
+
+type fakeConn struct {
+	mu        sync.Mutex
+	msgs      []*natslog.Msg
+	err       error
+	connected bool
+	flushed   bool
+}
+
+func (c *fakeConn) Publish(_ context.Context, msg *natslog.Msg) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func (c *fakeConn) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+func (c *fakeConn) Flush(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushed = true
+	return nil
+}
+
+func (c *fakeConn) set(connected bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connected, c.err = connected, err
+}
+
+func TestWriter(t *testing.T) {
+	c := &fakeConn{connected: true}
+	w := natslog.New(c, "logs.billing", natslog.WithLevelSubject(), natslog.WithMsgIDField("id"))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode(), "service", "billing")
+
+	logger.Warn("slow request", "id", "e1")
+	logger.Info("started")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(c.msgs) != 2 || !c.flushed {
+		t.Fatalf("expected 2 messages and flush, got %d %v", len(c.msgs), c.flushed)
+	}
+	msg := c.msgs[0]
+	if msg.Subject != "logs.billing.warn" || c.msgs[1].Subject != "logs.billing.info" {
+		t.Errorf("expected subjects with levels, got %q %q", msg.Subject, c.msgs[1].Subject)
+	}
+	if !strings.Contains(string(msg.Data), `"message":"slow request"`) || strings.HasSuffix(string(msg.Data), "\n") {
+		t.Errorf("expected entry without newline, got %q", msg.Data)
+	}
+	if msg.Header["level"][0] != "warn" || msg.Header["service"][0] != "billing" || msg.Header[natslog.MsgIDHeader][0] != "e1" {
+		t.Errorf("expected headers, got %v", msg.Header)
+	}
+	if _, ok := c.msgs[1].Header[natslog.MsgIDHeader]; ok {
+		t.Errorf("expected no message ID without field, got %v", c.msgs[1].Header)
+	}
+}
+
+func TestWriterReconnect(t *testing.T) {
+	c := &fakeConn{}
+	var dropped int
+	w := natslog.New(c, "logs", natslog.WithQueueSize(2), natslog.WithOnDrop(func(n int) { dropped += n }))
+
+	for _, line := range []string{"1", "2", "3"} {
+		_, err := w.Write([]byte(line + "\n"))
+		if line == "3" && (err == nil || !strings.Contains(err.Error(), "1 entries dropped")) {
+			t.Errorf("expected error about dropped entry, got %v", err)
+		} else if line != "3" && err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if w.Queued() != 2 || w.Dropped() != 1 || dropped != 1 {
+		t.Errorf("expected 2 queued and 1 dropped entries, got %d %d %d", w.Queued(), w.Dropped(), dropped)
+	}
+	if err := w.Flush(); !errors.Is(err, natslog.ErrDisconnected) {
+		t.Errorf("expected disconnected error, got %v", err)
+	}
+
+	c.set(true, nil)
+	if _, err := w.Write([]byte("4\n")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, msg := range c.msgs {
+		got = append(got, string(msg.Data))
+	}
+	if strings.Join(got, ",") != "2,3,4" || w.Queued() != 0 {
+		t.Errorf("expected queued entries before the new one, got %v", got)
+	}
+
+	c.set(true, errors.New("nats: outbound buffer limit exceeded"))
+	if _, err := w.Write([]byte("5\n")); err != nil {
+		t.Errorf("expected queued entry without error, got %v", err)
+	}
+	if err := w.Close(); err == nil || !strings.Contains(err.Error(), "1 entries dropped on close") {
+		t.Errorf("expected error about dropped entries, got %v", err)
+	}
+}
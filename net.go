@@ -0,0 +1,189 @@
+package logze
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultNetDialTimeout is a default timeout of connecting to a collector by [NetWriter].
+	DefaultNetDialTimeout = 5 * time.Second
+	// DefaultNetWriteTimeout is a default timeout of writing an entry by [NetWriter].
+	DefaultNetWriteTimeout = 5 * time.Second
+	// DefaultNetReconnectInterval is a default min time between attempts to connect to a collector by [NetWriter].
+	DefaultNetReconnectInterval = time.Second
+)
+
+// NetOptions is using for configuring [NewNetWriter].
+type NetOptions struct {
+	// DialTimeout is a timeout of connecting to a collector. Default value is 5s.
+	DialTimeout time.Duration
+
+	// WriteTimeout is a timeout of writing an entry, so a stuck collector doesn't block logging calls.
+	// Default value is 5s.
+	WriteTimeout time.Duration
+
+	// ReconnectInterval is a min time between attempts to connect to a collector,
+	// entries are dropped without waiting until the next attempt. Default value is 1s.
+	ReconnectInterval time.Duration
+
+	// TLS is a configuration of TLS for tcp networks. Default value is nil, TLS is not used.
+	TLS *tls.Config
+
+	// OnDrop is called with an entry that is dropped and the reason, e.g. to write it to a local file.
+	// The entry must not be retained after the call. Default value is nil.
+	OnDrop func(p []byte, err error)
+}
+
+// NetWriter is an [io.Writer] that ships entries to a collector (e.g. Fluent Bit or Logstash) over TCP or UDP
+// without a local agent. It connects with the first entry and reconnects after failures, entries that can't be
+// written are dropped: the error is returned, so they are counted in errors of [Logger.Close], and passed to
+// [NetOptions.OnDrop]. Wrap it with [NewRetryWriter] to retry entries instead of dropping them.
+// It is safe for concurrent use. Use [NewNetWriter] to create it.
+type NetWriter struct {
+	network string
+	addr    string
+	opts    NetOptions
+
+	mu       sync.Mutex
+	conn     net.Conn
+	lastDial time.Time
+	dialErr  error
+	closed   bool
+
+	dropped atomic.Int64
+}
+
+// NewNetWriter returns [NetWriter] sending entries to provided address, network is "tcp", "udp" or "unix"
+// (and their variants like "tcp4"). Connection is established with the first entry.
+func NewNetWriter(network, addr string, opts NetOptions) *NetWriter {
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultNetDialTimeout
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DefaultNetWriteTimeout
+	}
+	if opts.ReconnectInterval <= 0 {
+		opts.ReconnectInterval = DefaultNetReconnectInterval
+	}
+	return &NetWriter{
+		network: network,
+		addr:    addr,
+		opts:    opts,
+	}
+}
+
+// NewUnixWriter returns [NetWriter] sending entries to a local collector listening on a unix domain socket,
+// e.g. /run/collector.sock. When the collector is restarted, writing fails with EPIPE and the entry is written
+// again to a new connection. Use [NewNetWriter] with "unixgram" network for datagram sockets.
+func NewUnixWriter(path string, opts NetOptions) *NetWriter {
+	return NewNetWriter("unix", path, opts)
+}
+
+// Write sends an entry to the collector. It returns [os.ErrClosed] after [NetWriter.Close].
+func (w *NetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	n, err := w.write(p)
+	if err != nil {
+		w.dropped.Add(1)
+		if w.opts.OnDrop != nil {
+			w.opts.OnDrop(p, err)
+		}
+		return 0, err
+	}
+	return n, nil
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *NetWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Name returns a name of the writer for errors of [Logger.Close].
+func (w *NetWriter) Name() string {
+	return w.network + "://" + w.addr
+}
+
+// Close closes connection to the collector.
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// write writes an entry reconnecting if there is no connection, it is called with the lock held.
+func (w *NetWriter) write(p []byte) (int, error) {
+	if w.conn != nil {
+		n, err := w.writeConn(p)
+		if err == nil {
+			return n, nil
+		}
+		// Collector may be restarted, so the entry is written again to a new connection
+		_ = w.conn.Close()
+		w.conn = nil
+		w.lastDial = time.Time{}
+	}
+	if err := w.dial(); err != nil {
+		return 0, err
+	}
+	n, err := w.writeConn(p)
+	if err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		// Entries are dropped until the next attempt to connect
+		w.dialErr = err
+		return 0, err
+	}
+	return n, nil
+}
+
+func (w *NetWriter) writeConn(p []byte) (int, error) {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.opts.WriteTimeout)); err != nil {
+		return 0, err
+	}
+	return w.conn.Write(p)
+}
+
+// dial connects to the collector if the previous attempt is older than the reconnect interval,
+// otherwise it returns the error of the previous attempt.
+func (w *NetWriter) dial() error {
+	if time.Since(w.lastDial) < w.opts.ReconnectInterval {
+		return w.dialErr
+	}
+	w.lastDial = time.Now()
+
+	dialer := &net.Dialer{Timeout: w.opts.DialTimeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if w.opts.TLS != nil {
+		conn, err = tls.DialWithDialer(dialer, w.network, w.addr, w.opts.TLS)
+	} else {
+		conn, err = dialer.Dial(w.network, w.addr)
+	}
+	if err != nil {
+		w.dialErr = fmt.Errorf("connect to %s: %w", w.addr, err)
+		return w.dialErr
+	}
+	w.conn = conn
+	w.dialErr = nil
+	return nil
+}
@@ -0,0 +1,202 @@
+package logze_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// acceptLines accepts connections and sends every received line to the channel.
+func acceptLines(t *testing.T, ln net.Listener) (<-chan string, <-chan net.Conn) {
+	t.Helper()
+	lines := make(chan string, 100)
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+			go func() {
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					lines <- sc.Text()
+				}
+			}()
+		}
+	}()
+	return lines, conns
+}
+
+func receive(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatalf("expected a line")
+		return ""
+	}
+}
+
+func TestNetWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ln.Close()
+	lines, conns := acceptLines(t, ln)
+
+	w := logze.NewNetWriter("tcp", ln.Addr().String(), logze.NetOptions{ReconnectInterval: time.Millisecond})
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+	defer logger.Close()
+
+	logger.Info("first")
+	if line := receive(t, lines); !strings.Contains(line, `"message":"first"`) {
+		t.Errorf("expected first message, got %s", line)
+	}
+
+	// Collector drops the connection, the writer should reconnect
+	(<-conns).Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		logger.Info("second")
+		select {
+		case line := <-lines:
+			if !strings.Contains(line, `"message":"second"`) {
+				t.Errorf("expected second message, got %s", line)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the writer to reconnect")
+		}
+	}
+}
+
+func TestNetWriterUDP(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	w := logze.NewNetWriter("udp", conn.LocalAddr().String(), logze.NetOptions{})
+	defer w.Close()
+	if _, err := w.Write([]byte("message\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 100)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf[:n]) != "message\n" {
+		t.Errorf("expected message, got %q", buf[:n])
+	}
+}
+
+func TestNetWriterTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", srv.TLS.Clone())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ln.Close()
+	lines, _ := acceptLines(t, ln)
+
+	clientTLS := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	w := logze.NewNetWriter("tcp", ln.Addr().String(), logze.NetOptions{TLS: clientTLS})
+	defer w.Close()
+	if _, err := w.Write([]byte("secret\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line := receive(t, lines); line != "secret" {
+		t.Errorf("expected secret, got %s", line)
+	}
+}
+
+func TestNetWriterDrop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var dropped []string
+	w := logze.NewNetWriter("tcp", addr, logze.NetOptions{
+		ReconnectInterval: time.Hour,
+		OnDrop: func(p []byte, err error) {
+			dropped = append(dropped, string(p))
+		},
+	})
+	for _, msg := range []string{"a", "b"} {
+		if _, err := w.Write([]byte(msg)); err == nil {
+			t.Errorf("expected error for unavailable collector")
+		}
+	}
+	if len(dropped) != 2 || dropped[1] != "b" || w.Dropped() != 2 {
+		t.Errorf("expected 2 dropped entries, got %q", dropped)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("c")); err != os.ErrClosed {
+		t.Errorf("expected os.ErrClosed, got %v", err)
+	}
+}
+
+func TestUnixWriterReconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix sockets are not supported: %v", err)
+	}
+	lines, conns := acceptLines(t, ln)
+
+	w := logze.NewUnixWriter(path, logze.NetOptions{})
+	defer w.Close()
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line := receive(t, lines); line != "first" {
+		t.Errorf("expected first, got %q", line)
+	}
+
+	// Collector is restarted, the old connection is broken
+	ln.Close()
+	(<-conns).Close()
+	ln, err = net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ln.Close()
+	lines, _ = acceptLines(t, ln)
+
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("expected entry written to a new connection, got %v", err)
+	}
+	if line := receive(t, lines); line != "second" {
+		t.Errorf("expected second, got %q", line)
+	}
+	if w.Name() != "unix://"+path {
+		t.Errorf("expected name with path, got %q", w.Name())
+	}
+}
@@ -0,0 +1,66 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/pkg/errors"
+)
+
+func TestNilErrors(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithNoDiode()
+
+	logze.New(cfg).Err(nil, "default")
+	if !strings.Contains(b.String(), `"level":"error"`) {
+		t.Errorf("expected error level by default, got %s", b.String())
+	}
+
+	b.Reset()
+	logger := logze.New(cfg.WithNilErrors(logze.NilErrorsInfo))
+	logger.Err(nil, "info")
+	logger.Errf(nil, "info %d", 1)
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.Contains(line, `"level":"info"`) || !strings.Contains(line, `"logze_warning":"nil error"`) {
+			t.Errorf("expected info level with warning, got %s", line)
+		}
+	}
+
+	b.Reset()
+	logger.Err(errors.New("some error"), "error")
+	if !strings.Contains(b.String(), `"level":"error"`) || strings.Contains(b.String(), "logze_warning") {
+		t.Errorf("expected error level without warning, got %s", b.String())
+	}
+
+	b.Reset()
+	counter := &logze.SimpleErrorCounter{}
+	logze.New(cfg.WithNilErrors(logze.NilErrorsSkip).WithStackTrace().WithErrorCounter(counter)).
+		Err(nil, "skip", "cause", errors.New("other error"))
+	if b.Len() != 0 {
+		t.Errorf("expected no message, got %s", b.String())
+	}
+	if counter.Count.Load() != 0 {
+		t.Errorf("expected no counted errors, got %d", counter.Count.Load())
+	}
+
+	b.Reset()
+	logze.New(cfg.WithNilErrors(logze.NilErrorsPanic)).Err(nil, "no panic")
+	if !strings.Contains(b.String(), `"level":"error"`) {
+		t.Errorf("expected error level without development mode, got %s", b.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	logze.New(cfg.WithNilErrors(logze.NilErrorsPanic).WithDevelopment()).Err(nil, "panic")
+}
+
+func TestValidateNilErrors(t *testing.T) {
+	if err := logze.NewConfig(&bytes.Buffer{}).WithNilErrors("unknown").Validate(); err == nil {
+		t.Error("expected error for invalid nil errors mode")
+	}
+}
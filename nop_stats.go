@@ -0,0 +1,116 @@
+package logze
+
+import (
+	"sync"
+)
+
+// NopStatsLastMessages is a number of last entries kept by [NopStats], it is read by [NopWithStats].
+var NopStatsLastMessages = 100
+
+// NopStats is a statistics of a logger created with [NopWithStats]: counters of entries per level
+// and last entries. It is safe for concurrent use.
+type NopStats struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	size   int
+	last   []Entry
+	next   int
+	full   bool
+}
+
+// NopWithStats returns [Logger] of trace level that discards output, but records per-level counters and last
+// [NopStatsLastMessages] entries, e.g. to check in tests of a library that no errors were logged
+// without a real writer:
+//
+//	lg, stats := logze.NopWithStats()
+//	run(lg)
+//	if stats.HasErrors() {
+//		t.Errorf("unexpected errors: %v", stats.Last())
+//	}
+func NopWithStats() (Logger, *NopStats) {
+	stats := &NopStats{counts: make(map[string]int64), size: NopStatsLastMessages}
+	return New(NewConfig(stats).WithLevel(LevelTrace).WithNoDiode()), stats
+}
+
+// Write parses an entry, counts it and discards it. Entries that cannot be parsed are counted
+// as entries without level.
+func (s *NopStats) Write(p []byte) (int, error) {
+	e, err := ParseEntry(p)
+	if err != nil {
+		e = Entry{Message: string(p)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[e.Level]++
+	if s.size <= 0 {
+		return len(p), nil
+	}
+	if len(s.last) < s.size {
+		s.last = append(s.last, e)
+		return len(p), nil
+	}
+	// Ring buffer is full, overwrite the oldest entry
+	s.last[s.next] = e
+	s.next = (s.next + 1) % len(s.last)
+	s.full = true
+	return len(p), nil
+}
+
+// Count returns a number of entries of provided level, use an empty level for entries without level.
+func (s *NopStats) Count(level string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[level]
+}
+
+// Counts returns numbers of entries per level.
+func (s *NopStats) Counts() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int64, len(s.counts))
+	for level, n := range s.counts {
+		out[level] = n
+	}
+	return out
+}
+
+// Total returns a number of all entries.
+func (s *NopStats) Total() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for _, n := range s.counts {
+		total += n
+	}
+	return total
+}
+
+// HasErrors returns true if there are entries of error, fatal or panic levels.
+func (s *NopStats) HasErrors() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[LevelError]+s.counts[LevelFatal]+s.counts["panic"] > 0
+}
+
+// Last returns last entries from the oldest to the newest one.
+func (s *NopStats) Last() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		return append([]Entry(nil), s.last...)
+	}
+	out := make([]Entry, 0, len(s.last))
+	out = append(out, s.last[s.next:]...)
+	return append(out, s.last[:s.next]...)
+}
+
+// Reset removes all counters and entries.
+func (s *NopStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts = make(map[string]int64)
+	s.last = nil
+	s.next = 0
+	s.full = false
+}
@@ -0,0 +1,62 @@
+package logze_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestNopWithStats(t *testing.T) {
+	lg, stats := logze.NopWithStats()
+
+	lg.Trace("trace message")
+	lg.Info("info message")
+	lg.Info("info message")
+	if stats.HasErrors() {
+		t.Error("expected no errors")
+	}
+	lg.Err(errors.New("some error"), "error message")
+
+	if n := stats.Count(logze.LevelInfo); n != 2 {
+		t.Errorf("expected 2 info entries, got %d", n)
+	}
+	if n := stats.Total(); n != 4 {
+		t.Errorf("expected 4 entries, got %d", n)
+	}
+	if !stats.HasErrors() {
+		t.Error("expected errors")
+	}
+	if counts := stats.Counts(); counts[logze.LevelTrace] != 1 || counts[logze.LevelError] != 1 {
+		t.Errorf("expected trace and error counters, got %v", counts)
+	}
+
+	stats.Reset()
+	for i := 0; i < logze.NopStatsLastMessages+5; i++ {
+		lg.Info(strconv.Itoa(i))
+	}
+	last := stats.Last()
+	if len(last) != logze.NopStatsLastMessages {
+		t.Fatalf("expected %d last entries, got %d", logze.NopStatsLastMessages, len(last))
+	}
+	if last[0].Message != "5" || last[len(last)-1].Message != strconv.Itoa(logze.NopStatsLastMessages+4) {
+		t.Errorf("expected entries from the oldest to the newest, got %q ... %q", last[0].Message, last[len(last)-1].Message)
+	}
+}
+
+func TestNopWithStatsCapacity(t *testing.T) {
+	defer func(n int) { logze.NopStatsLastMessages = n }(logze.NopStatsLastMessages)
+	logze.NopStatsLastMessages = 2
+	lg, stats := logze.NopWithStats()
+
+	// Capacity is captured when stats are created
+	logze.NopStatsLastMessages = 5
+	for i := 0; i < 4; i++ {
+		lg.Info(strconv.Itoa(i))
+	}
+	last := stats.Last()
+	if len(last) != 2 || last[0].Message != "2" || last[1].Message != "3" {
+		t.Errorf("expected last 2 entries, got %+v", last)
+	}
+}
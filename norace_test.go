@@ -0,0 +1,5 @@
+//go:build !race
+
+package logze_test
+
+const raceEnabled = false
@@ -0,0 +1,597 @@
+// Package otel provides an exporter that converts log entries into OpenTelemetry log records and ships them
+// over OTLP/gRPC or OTLP/HTTP, so logze fits into a stack based on OpenTelemetry Collector without scraping stdout:
+//
+//	conn, err := grpc.Dial("otel-collector:4317", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	if err != nil {
+//		return err
+//	}
+//	exp := otel.NewGRPC(conn, otel.WithResource(map[string]string{"service.name": "billing"}))
+//	logger := logze.New(logze.NewConfig(exp))
+//	defer logger.Close()
+//
+// The level of an entry becomes a severity, the message becomes a body, trace_id and span_id fields become
+// trace context of a record and other fields become attributes. Records are exported in background in batches.
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureOTel)
+}
+
+const (
+	// HTTPPath is a path of OTLP/HTTP logs endpoint.
+	HTTPPath = "/v1/logs"
+
+	// DefaultScope is a default name of an instrumentation scope of records.
+	DefaultScope = "github.com/maxbolgarin/logze/v2"
+
+	// DefaultBatchSize is a default max number of records in one export request.
+	DefaultBatchSize = 512
+	// DefaultBatchWait is a default max time a record waits in a batch before it is exported.
+	DefaultBatchWait = time.Second
+	// DefaultQueueSize is a default max number of records waiting to be exported.
+	DefaultQueueSize = 2048
+	// DefaultTimeout is a default timeout of one export request.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxRetries is a default max number of retries of a failed export request.
+	DefaultMaxRetries = 5
+
+	maxBackoff = 5 * time.Second
+)
+
+// Enumerating names of fields of entries that become trace context of records.
+var (
+	TraceIDFieldName = "trace_id"
+	SpanIDFieldName  = "span_id"
+)
+
+// ErrQueueFull is passed to the drop callback when records are dropped because the queue is full.
+var ErrQueueFull = errors.New("otel: queue is full")
+
+// errPermanent marks errors of export requests that can't be retried.
+var errPermanent = errors.New("permanent error")
+
+// Option changes a behaviour of [NewGRPC] and [NewHTTP].
+type Option func(o *options)
+
+// WithResource sets attributes of a resource of records, e.g. service.name and deployment.environment.
+func WithResource(attrs map[string]string) Option {
+	return func(o *options) {
+		for k, v := range attrs {
+			o.resource[k] = v
+		}
+	}
+}
+
+// WithScope sets a name and a version of an instrumentation scope of records, default name is [DefaultScope].
+func WithScope(name, version string) Option {
+	return func(o *options) {
+		o.scope = name
+		o.scopeVersion = version
+	}
+}
+
+// WithBatch sets a max number of records in one export request and a max time a record waits in a batch,
+// default values are [DefaultBatchSize] and [DefaultBatchWait].
+func WithBatch(size int, wait time.Duration) Option {
+	return func(o *options) {
+		o.batchSize = size
+		o.batchWait = wait
+	}
+}
+
+// WithQueueSize sets a max number of records waiting to be exported, default value is [DefaultQueueSize].
+// New records are dropped when the queue is full.
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithTimeout sets a timeout of one export request, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxRetries sets a max number of retries of a failed export request, default value is [DefaultMaxRetries].
+// Only transient errors (e.g. unavailable collector) are retried.
+func WithMaxRetries(retries int) Option {
+	return func(o *options) {
+		o.maxRetries = retries
+	}
+}
+
+// WithHeaders sets headers of OTLP/HTTP requests or metadata of OTLP/gRPC requests, e.g. an API key.
+func WithHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		for k, v := range headers {
+			o.headers[k] = v
+		}
+	}
+}
+
+// WithHTTPClient sets a client of OTLP/HTTP requests, default value is [http.DefaultClient].
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithOnDrop sets a function that is called with a number of dropped records and the reason,
+// default function prints a warning to stderr.
+func WithOnDrop(onDrop func(n int, err error)) Option {
+	return func(o *options) {
+		o.onDrop = onDrop
+	}
+}
+
+type options struct {
+	resource     map[string]string
+	scope        string
+	scopeVersion string
+	batchSize    int
+	batchWait    time.Duration
+	queueSize    int
+	timeout      time.Duration
+	maxRetries   int
+	headers      map[string]string
+	client       *http.Client
+	onDrop       func(n int, err error)
+}
+
+// Exporter is an [io.Writer] that converts entries into log records and exports them in background.
+// It is safe for concurrent use. [Exporter.Close] must be called to export the rest of records.
+type Exporter struct {
+	o      options
+	export func(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *logspb.LogRecord
+	flush  chan chan error
+	stop   chan struct{}
+	done   chan struct{}
+
+	dropped atomic.Int64
+}
+
+// NewGRPC returns [Exporter] that exports records over OTLP/gRPC using provided connection,
+// the connection is not closed by the exporter.
+func NewGRPC(conn grpc.ClientConnInterface, opts ...Option) *Exporter {
+	client := collogspb.NewLogsServiceClient(conn)
+	e := newExporter(opts)
+	e.export = func(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+		for k, v := range e.o.headers {
+			ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+		}
+		resp, err := client.Export(ctx, req)
+		if err != nil {
+			switch status.Code(err) {
+			case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Aborted:
+				return fmt.Errorf("otel: %w", err)
+			}
+			return fmt.Errorf("otel: %w: %w", errPermanent, err)
+		}
+		return partialError(resp.GetPartialSuccess())
+	}
+	go e.run()
+	return e
+}
+
+// NewHTTP returns [Exporter] that exports records over OTLP/HTTP in protobuf encoding to provided endpoint
+// of a collector, e.g. "http://otel-collector:4318".
+func NewHTTP(endpoint string, opts ...Option) *Exporter {
+	url := strings.TrimSuffix(endpoint, "/") + HTTPPath
+	e := newExporter(opts)
+	e.export = func(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+		body, err := proto.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("otel: %w: %w", errPermanent, err)
+		}
+		hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("otel: %w: %w", errPermanent, err)
+		}
+		hreq.Header.Set("Content-Type", "application/x-protobuf")
+		for k, v := range e.o.headers {
+			hreq.Header.Set(k, v)
+		}
+		resp, err := e.o.client.Do(hreq)
+		if err != nil {
+			return fmt.Errorf("otel: %w", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+		switch {
+		case resp.StatusCode/100 == 2:
+			var out collogspb.ExportLogsServiceResponse
+			if proto.Unmarshal(data, &out) != nil {
+				return nil
+			}
+			return partialError(out.GetPartialSuccess())
+		case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusBadGateway,
+			resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+			return fmt.Errorf("otel: %s", resp.Status)
+		default:
+			return fmt.Errorf("otel: %w: %s", errPermanent, resp.Status)
+		}
+	}
+	go e.run()
+	return e
+}
+
+func newExporter(opts []Option) *Exporter {
+	o := options{
+		resource:   make(map[string]string),
+		scope:      DefaultScope,
+		batchSize:  DefaultBatchSize,
+		batchWait:  DefaultBatchWait,
+		queueSize:  DefaultQueueSize,
+		timeout:    DefaultTimeout,
+		maxRetries: DefaultMaxRetries,
+		headers:    make(map[string]string),
+		client:     http.DefaultClient,
+		onDrop: func(n int, err error) {
+			fmt.Fprintf(os.Stderr, "WRN: otel dropped %d log records: %v\n", n, err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = DefaultBatchSize
+	}
+	if o.batchWait <= 0 {
+		o.batchWait = DefaultBatchWait
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = DefaultQueueSize
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+	return &Exporter{
+		o:     o,
+		queue: make(chan *logspb.LogRecord, o.queueSize),
+		flush: make(chan chan error),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Write converts an entry into a log record and adds it to the queue, it never blocks.
+// If the queue is full, the record is dropped. It returns an error if the entry is not valid JSON
+// and [os.ErrClosed] after [Exporter.Close].
+func (e *Exporter) Write(p []byte) (int, error) {
+	rec, err := NewRecord(p)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return 0, os.ErrClosed
+	}
+	select {
+	case e.queue <- rec:
+	default:
+		e.drop(1, ErrQueueFull)
+	}
+	return len(p), nil
+}
+
+// ParsesEntries marks the exporter as [logze.EntryParser], so it gets entries with standard field names.
+func (e *Exporter) ParsesEntries() {}
+
+// Flush exports records that are written before the call and returns an error of the export.
+func (e *Exporter) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case e.flush <- ch:
+		return <-ch
+	case <-e.done:
+		return nil
+	}
+}
+
+// Dropped returns a number of records dropped since the exporter is created.
+func (e *Exporter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+// Close stops retrying, exports the rest of records once and stops the background goroutine.
+func (e *Exporter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.queue)
+	close(e.stop)
+	e.mu.Unlock()
+
+	before := e.dropped.Load()
+	<-e.done
+	if n := e.dropped.Load() - before; n > 0 {
+		return fmt.Errorf("otel: %d log records dropped on close", n)
+	}
+	return nil
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+
+	var batch []*logspb.LogRecord
+	timer := time.NewTimer(e.o.batchWait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case rec, ok := <-e.queue:
+			if !ok {
+				e.exportBatch(batch)
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= e.o.batchSize {
+				e.exportBatch(batch)
+				batch = nil
+			}
+		case <-timer.C:
+			e.exportBatch(batch)
+			batch = nil
+			timer.Reset(e.o.batchWait)
+		case ch := <-e.flush:
+			// Records written before Flush are in the queue already
+			for n := len(e.queue); n > 0; n-- {
+				batch = append(batch, <-e.queue)
+			}
+			ch <- e.exportBatch(batch)
+			batch = nil
+		}
+	}
+}
+
+// exportBatch exports records retrying transient errors, the batch is dropped if it can't be exported.
+func (e *Exporter) exportBatch(batch []*logspb.LogRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	req := e.request(batch)
+
+	backoff := 100 * time.Millisecond
+	for retry := 0; ; retry++ {
+		ctx, cancel := context.WithTimeout(context.Background(), e.o.timeout)
+		err := e.export(ctx, req)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		var rejected *rejectedError
+		if errors.As(err, &rejected) {
+			e.drop(int(rejected.n), err)
+			return err
+		}
+		if errors.Is(err, errPermanent) || retry >= e.o.maxRetries {
+			e.drop(len(batch), err)
+			return err
+		}
+
+		// Full jitter spreads retries of many instances exporting to the same collector
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		select {
+		case <-timer.C:
+		case <-e.stop:
+			timer.Stop()
+			e.drop(len(batch), err)
+			return err
+		}
+	}
+}
+
+func (e *Exporter) request(batch []*logspb.LogRecord) *collogspb.ExportLogsServiceRequest {
+	keys := make([]string, 0, len(e.o.resource))
+	for k := range e.o.resource {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	resource := &resourcepb.Resource{}
+	for _, k := range keys {
+		resource.Attributes = append(resource.Attributes, &commonpb.KeyValue{
+			Key:   k,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: e.o.resource[k]}},
+		})
+	}
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: e.o.scope, Version: e.o.scopeVersion},
+				LogRecords: batch,
+			}},
+		}},
+	}
+}
+
+func (e *Exporter) drop(n int, err error) {
+	e.dropped.Add(int64(n))
+	if e.o.onDrop != nil {
+		e.o.onDrop(n, err)
+	}
+}
+
+// NewRecord converts a JSON entry into a log record. Time of a record is taken from the time field
+// if it is in RFC3339 format, otherwise the current time is used.
+func NewRecord(p []byte) (*logspb.LogRecord, error) {
+	fields := make(map[string]any)
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("otel: decode entry: %w", err)
+	}
+
+	now := time.Now()
+	rec := &logspb.LogRecord{
+		TimeUnixNano:         uint64(now.UnixNano()),
+		ObservedTimeUnixNano: uint64(now.UnixNano()),
+	}
+	if s, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			rec.TimeUnixNano = uint64(t.UnixNano())
+			delete(fields, zerolog.TimestampFieldName)
+		}
+	}
+	if s, ok := fields[zerolog.LevelFieldName].(string); ok {
+		rec.SeverityText = s
+		if level, err := zerolog.ParseLevel(s); err == nil {
+			rec.SeverityNumber = severity(level)
+		}
+		delete(fields, zerolog.LevelFieldName)
+	}
+	if s, ok := fields[zerolog.MessageFieldName].(string); ok {
+		rec.Body = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}}
+		delete(fields, zerolog.MessageFieldName)
+	}
+	if id, ok := hexID(fields[TraceIDFieldName], 16); ok {
+		rec.TraceId = id
+		delete(fields, TraceIDFieldName)
+	}
+	if id, ok := hexID(fields[SpanIDFieldName], 8); ok {
+		rec.SpanId = id
+		delete(fields, SpanIDFieldName)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rec.Attributes = append(rec.Attributes, &commonpb.KeyValue{Key: k, Value: anyValue(fields[k])})
+	}
+	return rec, nil
+}
+
+// severity maps a level to a severity number of OpenTelemetry.
+func severity(level zerolog.Level) logspb.SeverityNumber {
+	switch level {
+	case zerolog.TraceLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_TRACE
+	case zerolog.DebugLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG
+	case zerolog.InfoLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case zerolog.WarnLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case zerolog.ErrorLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	case zerolog.FatalLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	case zerolog.PanicLevel:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_FATAL4
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
+
+func hexID(v any, size int) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok || len(s) != size*2 {
+		return nil, false
+	}
+	id, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return id, true
+}
+
+func anyValue(v any) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		}
+		f, _ := v.Float64()
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case []any:
+		values := make([]*commonpb.AnyValue, len(v))
+		for i, item := range v {
+			values[i] = anyValue(item)
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kvs := make([]*commonpb.KeyValue, len(keys))
+		for i, k := range keys {
+			kvs[i] = &commonpb.KeyValue{Key: k, Value: anyValue(v[k])}
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: kvs}}}
+	default:
+		// JSON null is an empty value
+		return &commonpb.AnyValue{}
+	}
+}
+
+// rejectedError is an error of an export request which records are partially rejected by a collector,
+// rejected records are not retried, because the collector won't accept them.
+type rejectedError struct {
+	n   int64
+	msg string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("otel: %d log records rejected: %s", e.n, e.msg)
+}
+
+func partialError(ps *collogspb.ExportLogsPartialSuccess) error {
+	if ps == nil || ps.GetRejectedLogRecords() == 0 {
+		return nil
+	}
+	return &rejectedError{n: ps.GetRejectedLogRecords(), msg: ps.GetErrorMessage()}
+}
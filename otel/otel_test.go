@@ -0,0 +1,213 @@
+package otel_test
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/otel"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type collector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	apiKey   string
+	fails    int
+}
+
+func (c *collector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fails > 0 {
+		c.fails--
+		return nil, status.Error(codes.Unavailable, "collector is starting")
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("api-key")) > 0 {
+		c.apiKey = md.Get("api-key")[0]
+	}
+	c.requests = append(c.requests, req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (c *collector) records() []*logspb.LogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*logspb.LogRecord
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				out = append(out, sl.LogRecords...)
+			}
+		}
+	}
+	return out
+}
+
+func TestGRPC(t *testing.T) {
+	c := &collector{fails: 1}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, c)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	exp := otel.NewGRPC(conn, otel.WithResource(map[string]string{"service.name": "billing"}),
+		otel.WithHeaders(map[string]string{"api-key": "secret"}))
+	logger := logze.New(logze.NewConfig(exp).WithNoDiode())
+
+	traceID := "0102030405060708090a0b0c0d0e0f10"
+	logger.Warn("slow request", "trace_id", traceID, "span_id", "0102030405060708", "status", 200,
+		"ratio", 0.5, "ok", true, "user", map[string]any{"id": "u1"})
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := c.records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record after retry, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_WARN || rec.SeverityText != "warn" {
+		t.Errorf("expected warn severity, got %v %s", rec.SeverityNumber, rec.SeverityText)
+	}
+	if rec.Body.GetStringValue() != "slow request" {
+		t.Errorf("expected body, got %v", rec.Body)
+	}
+	if hex.EncodeToString(rec.TraceId) != traceID || len(rec.SpanId) != 8 {
+		t.Errorf("expected trace context, got %x %x", rec.TraceId, rec.SpanId)
+	}
+	if rec.TimeUnixNano == 0 || time.Since(time.Unix(0, int64(rec.TimeUnixNano))) > time.Minute {
+		t.Errorf("expected time of the entry, got %d", rec.TimeUnixNano)
+	}
+
+	attrs := make(map[string]any)
+	for _, kv := range rec.Attributes {
+		switch {
+		case kv.Value.GetKvlistValue() != nil:
+			attrs[kv.Key] = kv.Value.GetKvlistValue().Values[0].Value.GetStringValue()
+		default:
+			attrs[kv.Key] = kv.Value.GetValue()
+		}
+	}
+	if v, ok := attrs["status"].(*commonpb.AnyValue_IntValue); !ok || v.IntValue != 200 {
+		t.Errorf("expected int status, got %v", attrs["status"])
+	}
+	if v, ok := attrs["ratio"].(*commonpb.AnyValue_DoubleValue); !ok || v.DoubleValue != 0.5 {
+		t.Errorf("expected double ratio, got %v", attrs["ratio"])
+	}
+	if v, ok := attrs["ok"].(*commonpb.AnyValue_BoolValue); !ok || !v.BoolValue {
+		t.Errorf("expected bool ok, got %v", attrs["ok"])
+	}
+	if attrs["user"] != "u1" {
+		t.Errorf("expected nested user, got %v", attrs["user"])
+	}
+	for _, name := range []string{"level", "message", "time", "trace_id", "span_id"} {
+		if _, ok := attrs[name]; ok {
+			t.Errorf("expected %s not to be an attribute", name)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.apiKey != "secret" {
+		t.Errorf("expected api key metadata, got %q", c.apiKey)
+	}
+	if name := c.requests[0].ResourceLogs[0].Resource.Attributes[0]; name.Key != "service.name" || name.Value.GetStringValue() != "billing" {
+		t.Errorf("expected service.name resource, got %v", name)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		records []*logspb.LogRecord
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otel.HTTPPath || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var req collogspb.ExportLogsServiceRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		records = append(records, req.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	exp := otel.NewHTTP(srv.URL)
+	defer exp.Close()
+	logger := logze.New(logze.NewConfig(exp).WithNoDiode())
+	logger.Info("first")
+	logger.Error("second")
+	if err := exp.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 2 || records[1].SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_ERROR {
+		t.Errorf("expected 2 records, got %v", records)
+	}
+}
+
+func TestHTTPPermanentError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var dropped int
+	exp := otel.NewHTTP(srv.URL, otel.WithOnDrop(func(n int, err error) { dropped += n }))
+	defer exp.Close()
+	if _, err := exp.Write([]byte(`{"message":"message"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := exp.Flush(); err == nil {
+		t.Errorf("expected error for bad request")
+	}
+	if dropped != 1 || exp.Dropped() != 1 {
+		t.Errorf("expected 1 dropped record without retries, got %d", dropped)
+	}
+	if _, err := exp.Write([]byte("not json")); err == nil {
+		t.Errorf("expected error for invalid entry")
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureOTel) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureOTel, logze.Features())
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package logze_test
+
+// raceEnabled is true when tests are run with the race detector, it adds allocations to instrumented code.
+const raceEnabled = true
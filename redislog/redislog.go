@@ -0,0 +1,187 @@
+// Package redislog provides an [io.Writer] that adds every log entry to a Redis stream with XADD
+// and caps a length of the stream, so the stream is a cheap short-term buffer of logs that other services tail
+// with XREAD or consumer groups.
+//
+// The package doesn't depend on a Redis client, a client is plugged in with [Client].
+// For example, with github.com/redis/go-redis:
+//
+//	w := redislog.New(redislog.ClientFunc(func(ctx context.Context, args *redislog.XAddArgs) error {
+//		return rdb.XAdd(ctx, &redis.XAddArgs{
+//			Stream: args.Stream, MaxLen: args.MaxLen, Approx: args.Approx, Values: args.Values,
+//		}).Err()
+//	}), "logs:billing", redislog.WithMaxLen(100000))
+//	logger := logze.New(logze.NewConfig(w))
+package redislog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureRedis)
+}
+
+// Default values of options.
+const (
+	DefaultTimeout = 5 * time.Second
+	DefaultMaxLen  = 10000
+)
+
+// EntryFieldName is a name of a field of stream entries with a log entry.
+var EntryFieldName = "entry"
+
+// XAddArgs are arguments of XADD command for an entry.
+type XAddArgs struct {
+	// Stream is a key of a stream.
+	Stream string
+
+	// MaxLen is a maximum length of a stream (MAXLEN), 0 means no limit.
+	MaxLen int64
+
+	// Approx if true, will trim a stream approximately (MAXLEN ~), that is much more efficient.
+	Approx bool
+
+	// Values are pairs of names and values of fields of a stream entry: [EntryFieldName] with a log entry
+	// and fields set by [WithFields].
+	Values []any
+}
+
+// Client adds entries to Redis streams, it is implemented by an adapter of a Redis client.
+// It may add entries asynchronously (e.g. in a pipeline), in that case it should implement Flush(ctx) error.
+type Client interface {
+	XAdd(ctx context.Context, args *XAddArgs) error
+}
+
+// ClientFunc is an adapter to use a function as [Client].
+type ClientFunc func(ctx context.Context, args *XAddArgs) error
+
+// XAdd calls f(ctx, args).
+func (f ClientFunc) XAdd(ctx context.Context, args *XAddArgs) error {
+	return f(ctx, args)
+}
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithMaxLen sets a maximum length of a stream, older entries are trimmed. Default value is [DefaultMaxLen],
+// 0 disables trimming.
+func WithMaxLen(n int64) Option {
+	return func(o *options) {
+		o.maxLen = n
+	}
+}
+
+// WithExactMaxLen makes a stream trimmed to the exact length. By default it is trimmed approximately,
+// so Redis removes whole macro nodes and a stream may be a bit longer than the limit.
+func WithExactMaxLen() Option {
+	return func(o *options) {
+		o.exact = true
+	}
+}
+
+// WithFields sets names of fields of log entries that are copied to separate fields of stream entries,
+// so consumers can filter entries without parsing them. Default value is level. Missing fields are skipped.
+func WithFields(fields ...string) Option {
+	return func(o *options) {
+		o.fields = fields
+	}
+}
+
+// WithTimeout sets a timeout of adding one entry, default value is [DefaultTimeout].
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+type options struct {
+	maxLen  int64
+	exact   bool
+	fields  []string
+	timeout time.Duration
+}
+
+// Writer adds every written entry to a stream. It is safe for concurrent use if the client is.
+// Errors of the client are returned, so they are counted in errors of [logze.Logger.Close].
+type Writer struct {
+	c      Client
+	stream string
+	o      options
+}
+
+// New returns [Writer] that adds entries to provided stream using the client.
+func New(c Client, stream string, opts ...Option) *Writer {
+	o := options{
+		maxLen:  DefaultMaxLen,
+		fields:  []string{zerolog.LevelFieldName},
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxLen < 0 {
+		o.maxLen = 0
+	}
+	if o.timeout <= 0 {
+		o.timeout = DefaultTimeout
+	}
+	return &Writer{c: c, stream: stream, o: o}
+}
+
+// Write adds a copy of an entry to the stream, the trailing newline is removed.
+func (w *Writer) Write(p []byte) (int, error) {
+	value := p
+	if n := len(value); n > 0 && value[n-1] == '\n' {
+		value = value[:n-1]
+	}
+	args := &XAddArgs{
+		Stream: w.stream,
+		MaxLen: w.o.maxLen,
+		Approx: w.o.maxLen > 0 && !w.o.exact,
+		Values: make([]any, 0, 2+2*len(w.o.fields)),
+	}
+	args.Values = append(args.Values, EntryFieldName, string(value))
+	if len(w.o.fields) > 0 {
+		// Entries of the logger are valid JSON, other data is added without fields
+		e, _ := logze.ParseEntry(value)
+		for _, name := range w.o.fields {
+			if name == EntryFieldName {
+				continue
+			}
+			if v, ok := e.FieldString(name); ok {
+				args.Values = append(args.Values, name, v)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := w.c.XAdd(ctx, args); err != nil {
+		return 0, fmt.Errorf("redis: %w", err)
+	}
+	return len(p), nil
+}
+
+// Flush waits for entries added asynchronously if the client implements Flush(ctx) error.
+func (w *Writer) Flush() error {
+	f, ok := w.c.(interface{ Flush(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.o.timeout)
+	defer cancel()
+	if err := f.Flush(ctx); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+// Name returns a name of the writer for errors of [logze.Logger.Close].
+func (w *Writer) Name() string {
+	return "redis " + w.stream
+}
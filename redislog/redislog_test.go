@@ -0,0 +1,83 @@
+package redislog_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/redislog"
+)
+
+type fakeClient struct {
+	mu   sync.Mutex
+	args []*redislog.XAddArgs
+	err  error
+}
+
+func (c *fakeClient) XAdd(_ context.Context, args *redislog.XAddArgs) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.args = append(c.args, args)
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	c := &fakeClient{}
+	w := redislog.New(c, "logs:billing", redislog.WithFields("level", "request_id"))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Warn("slow request", "request_id", 42)
+	logger.Info("started")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(c.args) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(c.args))
+	}
+	args := c.args[0]
+	if args.Stream != "logs:billing" || args.MaxLen != redislog.DefaultMaxLen || !args.Approx {
+		t.Errorf("expected stream with approximate default max length, got %+v", args)
+	}
+	if len(args.Values) != 6 || args.Values[0] != redislog.EntryFieldName || args.Values[3] != "warn" || args.Values[5] != "42" {
+		t.Fatalf("expected entry, level and request ID values, got %v", args.Values)
+	}
+	if entry, _ := args.Values[1].(string); !strings.Contains(entry, `"message":"slow request"`) || strings.HasSuffix(entry, "\n") {
+		t.Errorf("expected entry without newline, got %q", entry)
+	}
+	if len(c.args[1].Values) != 4 {
+		t.Errorf("expected no missing request ID field, got %v", c.args[1].Values)
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	c := &fakeClient{}
+	w := redislog.New(c, "logs", redislog.WithMaxLen(100), redislog.WithExactMaxLen(), redislog.WithFields())
+
+	if _, err := w.Write([]byte("raw line\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args := c.args[0]; args.MaxLen != 100 || args.Approx || len(args.Values) != 2 || args.Values[1] != "raw line" {
+		t.Errorf("expected exact max length and only entry, got %+v", args)
+	}
+
+	c.err = errors.New("connection refused")
+	if _, err := w.Write([]byte("{}\n")); err == nil || !strings.Contains(err.Error(), "redis: connection refused") {
+		t.Errorf("expected error of the client, got %v", err)
+	}
+
+	c.err = nil
+	w = redislog.New(c, "logs", redislog.WithMaxLen(0))
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args := c.args[1]; args.MaxLen != 0 || args.Approx {
+		t.Errorf("expected no trimming, got %+v", args)
+	}
+}
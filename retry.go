@@ -0,0 +1,191 @@
+package logze
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultRetryQueueSize is a default max number of entries waiting to be written by [RetryWriter].
+	DefaultRetryQueueSize = 1000
+	// DefaultRetryMinBackoff is a default delay before the first retry of [RetryWriter].
+	DefaultRetryMinBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is a default max delay between retries of [RetryWriter].
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// ErrRetryQueueFull is passed to [RetryOptions.OnDrop] when an entry is dropped because the queue is full.
+var ErrRetryQueueFull = errors.New("retry queue is full")
+
+// RetryOptions is using for configuring [NewRetryWriter].
+type RetryOptions struct {
+	// QueueSize is a max number of entries waiting to be written, new entries are dropped when the queue is full.
+	// Default value is 1000.
+	QueueSize int
+
+	// MinBackoff is a delay before the first retry, it is doubled after every failed retry.
+	// Default value is 100ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff is a max delay between retries. Default value is 10s.
+	MaxBackoff time.Duration
+
+	// MaxRetries is a max number of retries of an entry, then the entry is dropped.
+	// Default value is 0, it means that an entry is retried until it is written or the writer is closed.
+	MaxRetries int
+
+	// IsTransient returns true if a write can be retried after the error.
+	// Default value is nil, in that case all errors are transient.
+	IsTransient func(err error) bool
+
+	// OnDrop is called with an entry that is dropped and the reason: [ErrRetryQueueFull] or the last write error.
+	// The entry must not be retained after the call.
+	// Default value is a function that prints a warning to stderr.
+	OnDrop func(p []byte, err error)
+}
+
+// RetryWriter is an [io.Writer] that writes entries to the underlying writer in a background goroutine
+// and retries transient errors with exponential backoff and jitter, so a flaky target (e.g. syslog or TCP collector)
+// doesn't lose entries. Entries are kept in a bounded queue, see [RetryOptions]. It is safe for concurrent use.
+// Use [NewRetryWriter] to create it and [RetryWriter.Close] to flush the queue.
+type RetryWriter struct {
+	w    io.Writer
+	opts RetryOptions
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	stop   chan struct{}
+	done   chan struct{}
+
+	dropped atomic.Int64
+}
+
+// NewRetryWriter returns [RetryWriter] writing to w and starts its background goroutine.
+func NewRetryWriter(w io.Writer, opts RetryOptions) *RetryWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultRetryQueueSize
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultRetryMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = opts.MinBackoff
+	}
+	if opts.IsTransient == nil {
+		opts.IsTransient = func(error) bool { return true }
+	}
+	if opts.OnDrop == nil {
+		opts.OnDrop = func(_ []byte, err error) {
+			fmt.Fprintf(os.Stderr, "WRN: logger dropped a message after retries: %v\n", err)
+		}
+	}
+	rw := &RetryWriter{
+		w:     w,
+		opts:  opts,
+		queue: make(chan []byte, opts.QueueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go rw.run()
+	return rw
+}
+
+// Write adds a copy of p to the queue, it never blocks. If the queue is full, the entry is dropped.
+// It returns [os.ErrClosed] after [RetryWriter.Close].
+func (w *RetryWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	select {
+	case w.queue <- append([]byte(nil), p...):
+	default:
+		w.drop(p, ErrRetryQueueFull)
+	}
+	return len(p), nil
+}
+
+// Len returns a number of entries waiting to be written.
+func (w *RetryWriter) Len() int {
+	return len(w.queue)
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *RetryWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close stops retrying, tries to write every entry left in the queue once and closes the underlying writer
+// if it implements [io.Closer], except [os.Stdout] and [os.Stderr]. It returns an error if some entries are dropped.
+func (w *RetryWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	close(w.stop)
+	w.mu.Unlock()
+
+	before := w.dropped.Load()
+	<-w.done
+
+	var errs []error
+	if n := w.dropped.Load() - before; n > 0 {
+		errs = append(errs, fmt.Errorf("%d entries dropped on close", n))
+	}
+	errs = append(errs, closeWriter("underlying", w.w))
+	return joinErrors(errs...)
+}
+
+func (w *RetryWriter) run() {
+	defer close(w.done)
+	for p := range w.queue {
+		w.write(p)
+	}
+}
+
+// write writes an entry retrying transient errors until it is written, dropped or the writer is closed.
+func (w *RetryWriter) write(p []byte) {
+	backoff := w.opts.MinBackoff
+	for retry := 0; ; retry++ {
+		_, err := w.w.Write(p)
+		if err == nil {
+			return
+		}
+		if !w.opts.IsTransient(err) || w.opts.MaxRetries > 0 && retry >= w.opts.MaxRetries {
+			w.drop(p, err)
+			return
+		}
+
+		// Full jitter spreads retries of many writers hitting the same target
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+		select {
+		case <-timer.C:
+		case <-w.stop:
+			timer.Stop()
+			w.drop(p, err)
+			return
+		}
+		if backoff *= 2; backoff > w.opts.MaxBackoff {
+			backoff = w.opts.MaxBackoff
+		}
+	}
+}
+
+func (w *RetryWriter) drop(p []byte, err error) {
+	w.dropped.Add(1)
+	w.opts.OnDrop(p, err)
+}
@@ -0,0 +1,120 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+// failingWriter fails first n writes.
+type failingWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	fails int
+	calls int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+	if w.fails > 0 {
+		w.fails--
+		return 0, errors.New("temporary failure")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *failingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestRetryWriter(t *testing.T) {
+	target := &failingWriter{fails: 3}
+	w := logze.NewRetryWriter(target, logze.RetryOptions{MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for target.String() != "first\nsecond\n" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if target.String() != "first\nsecond\n" {
+		t.Errorf("expected %q, got %q", "first\nsecond\n", target.String())
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if w.Dropped() != 0 {
+		t.Errorf("expected 0, got %d", w.Dropped())
+	}
+	if _, err := w.Write([]byte("closed\n")); err == nil {
+		t.Errorf("expected error after close")
+	}
+}
+
+func TestRetryWriterDrop(t *testing.T) {
+	target := &failingWriter{fails: 1000}
+	var mu sync.Mutex
+	var dropped []string
+	var reasons []error
+	w := logze.NewRetryWriter(target, logze.RetryOptions{
+		QueueSize:  1,
+		MinBackoff: time.Hour,
+		MaxRetries: 1,
+		OnDrop: func(p []byte, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped = append(dropped, string(p))
+			reasons = append(reasons, err)
+		},
+	})
+
+	w.Write([]byte("a"))
+	// Wait for the first entry to be taken from the queue
+	deadline := time.Now().Add(time.Second)
+	for w.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	w.Write([]byte("b"))
+	w.Write([]byte("c"))
+
+	if err := w.Close(); err == nil {
+		t.Errorf("expected error about dropped entries")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dropped) != 3 || w.Dropped() != 3 {
+		t.Fatalf("expected 3 dropped entries, got %v", dropped)
+	}
+	if dropped[0] != "c" || !errors.Is(reasons[0], logze.ErrRetryQueueFull) {
+		t.Errorf("expected c to be dropped because of full queue, got %s: %v", dropped[0], reasons[0])
+	}
+}
+
+func TestRetryWriterNotTransient(t *testing.T) {
+	target := &failingWriter{fails: 1}
+	done := make(chan error, 1)
+	w := logze.NewRetryWriter(target, logze.RetryOptions{
+		IsTransient: func(error) bool { return false },
+		OnDrop:      func(_ []byte, err error) { done <- err },
+	})
+	defer w.Close()
+
+	w.Write([]byte("a"))
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "temporary failure" {
+			t.Errorf("expected write error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected entry to be dropped")
+	}
+}
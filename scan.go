@@ -0,0 +1,123 @@
+package logze
+
+// topLevelField is a field of the top level object of a JSON entry found by [scanTopLevel].
+type topLevelField struct {
+	// key is a raw key without quotes, escape sequences are not decoded.
+	key []byte
+	// keyStart and keyEnd are offsets of the quoted key.
+	keyStart, keyEnd int
+	// valueStart and valueEnd are offsets of the raw value.
+	valueStart, valueEnd int
+}
+
+// scanTopLevel calls f for every field of the top level object of a JSON entry until f returns false,
+// fields of nested objects and arrays are skipped. It doesn't allocate and doesn't validate values,
+// so it is cheap enough to be called for every entry. It returns false if p is not a JSON object,
+// e.g. raw bytes written with Logger.Write.
+func scanTopLevel(p []byte, f func(field topLevelField) bool) bool {
+	i := skipSpaces(p, 0)
+	if i >= len(p) || p[i] != '{' {
+		return false
+	}
+	i = skipSpaces(p, i+1)
+	if i < len(p) && p[i] == '}' {
+		return true
+	}
+	for i < len(p) {
+		if p[i] != '"' {
+			return false
+		}
+		keyEnd := skipString(p, i)
+		if keyEnd < 0 {
+			return false
+		}
+		field := topLevelField{key: p[i+1 : keyEnd-1], keyStart: i, keyEnd: keyEnd}
+
+		i = skipSpaces(p, keyEnd)
+		if i >= len(p) || p[i] != ':' {
+			return false
+		}
+		i = skipSpaces(p, i+1)
+		valueEnd := skipValue(p, i)
+		if valueEnd < 0 {
+			return false
+		}
+		field.valueStart, field.valueEnd = i, valueEnd
+		if !f(field) {
+			return true
+		}
+
+		i = skipSpaces(p, valueEnd)
+		if i >= len(p) {
+			return false
+		}
+		switch p[i] {
+		case ',':
+			i = skipSpaces(p, i+1)
+		case '}':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// skipString returns an offset after a string starting at p[i], or -1 if it is not terminated.
+func skipString(p []byte, i int) int {
+	for i++; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// skipValue returns an offset after a JSON value starting at p[i], or -1 if it is not terminated.
+func skipValue(p []byte, i int) int {
+	if i >= len(p) {
+		return -1
+	}
+	switch p[i] {
+	case '"':
+		return skipString(p, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(p); i++ {
+			switch p[i] {
+			case '"':
+				end := skipString(p, i)
+				if end < 0 {
+					return -1
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return -1
+	}
+	// Number, true, false or null
+	for ; i < len(p); i++ {
+		switch p[i] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return i
+		}
+	}
+	return i
+}
+
+func skipSpaces(p []byte, i int) int {
+	for i < len(p) && (p[i] == ' ' || p[i] == '\t' || p[i] == '\n' || p[i] == '\r') {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,194 @@
+// Package sentrylog forwards error entries of [logze.Logger] to Sentry as events with a fingerprint,
+// a stack trace and fields as extra context.
+//
+// The package doesn't depend on the Sentry SDK, events are passed to [Client] that is implemented
+// by a small adapter of sentry-go:
+//
+//	client := sentrylog.ClientFunc(func(ev *sentrylog.Event) {
+//		event := sentry.NewEvent()
+//		event.Level = sentry.Level(ev.Level)
+//		event.Message = ev.Message
+//		event.Fingerprint = ev.Fingerprint
+//		event.Tags = ev.Tags
+//		event.Extra = ev.Extra
+//		// Convert ev.Error and ev.Stacktrace to event.Exception
+//		sentry.CaptureEvent(event)
+//	})
+//	logger := logze.New(logze.NewConfig(w).WithStackTrace().WithEntryHooks(sentrylog.Hook(client)))
+//
+// Stack traces are taken from the stack field of entries, so enable them with [logze.Config.WithStackTrace].
+package sentrylog
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureSentry)
+}
+
+// Event is an event of Sentry built from an entry.
+type Event struct {
+	// Level is a level of Sentry: error or fatal.
+	Level string
+
+	// Message is a message of an entry.
+	Message string
+
+	// Error is a text of a logged error, it is empty if an entry has no error.
+	Error string
+
+	// Fingerprint is a list of strings that Sentry uses to group events.
+	Fingerprint []string
+
+	// Stacktrace is a stack trace of a logged error from the oldest frame to the newest one, as Sentry expects.
+	Stacktrace []logze.Frame
+
+	// Tags are values of tag fields, see [WithTagFields].
+	Tags map[string]string
+
+	// Extra are all other fields of an entry.
+	Extra map[string]any
+
+	// Timestamp is a time of an entry or a time of the hook call if an entry has no time field.
+	Timestamp time.Time
+}
+
+// Client sends events to Sentry, it is implemented by an adapter of the Sentry SDK.
+// It is called in a goroutine that writes entries, so it shouldn't block (sentry-go sends events in background).
+type Client interface {
+	CaptureEvent(ev *Event)
+}
+
+// ClientFunc is an adapter to use a function as [Client].
+type ClientFunc func(ev *Event)
+
+// CaptureEvent calls f(ev).
+func (f ClientFunc) CaptureEvent(ev *Event) {
+	f(ev)
+}
+
+// Option changes a behaviour of [Hook].
+type Option func(o *options)
+
+// WithLevels sets levels of entries that are forwarded to Sentry, default levels are error, fatal and panic.
+func WithLevels(levels ...string) Option {
+	return func(o *options) {
+		o.levels = make(map[string]struct{}, len(levels))
+		for _, level := range levels {
+			o.levels[level] = struct{}{}
+		}
+	}
+}
+
+// WithSampleRate sets a share of entries that are forwarded to Sentry from 0 to 1, default value is 1.
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithFingerprint sets a function that returns a fingerprint of an event. Default fingerprint is the message
+// of an entry, so errors logged in the same place are grouped even if their texts contain variable data.
+func WithFingerprint(fingerprint func(e logze.Entry) []string) Option {
+	return func(o *options) {
+		o.fingerprint = fingerprint
+	}
+}
+
+// WithTagFields sets names of fields of entries that become tags of events, e.g. service and request_id.
+func WithTagFields(fields ...string) Option {
+	return func(o *options) {
+		o.tagFields = append(o.tagFields, fields...)
+	}
+}
+
+type options struct {
+	levels      map[string]struct{}
+	sampleRate  float64
+	fingerprint func(e logze.Entry) []string
+	tagFields   []string
+}
+
+// Hook returns [logze.EntryHook] that forwards entries of error and higher levels to Sentry using the client.
+func Hook(client Client, opts ...Option) logze.EntryHook {
+	o := options{
+		sampleRate: 1,
+		fingerprint: func(e logze.Entry) []string {
+			return []string{e.Message}
+		},
+	}
+	WithLevels(logze.LevelError, logze.LevelFatal, zerolog.LevelPanicValue)(&o)
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(e logze.Entry) {
+		if _, ok := o.levels[e.Level]; !ok {
+			return
+		}
+		if o.sampleRate < 1 && rand.Float64() >= o.sampleRate {
+			return
+		}
+		client.CaptureEvent(NewEvent(e, o.fingerprint(e), o.tagFields...))
+	}
+}
+
+// NewEvent returns [Event] built from the entry with provided fingerprint and names of tag fields.
+func NewEvent(e logze.Entry, fingerprint []string, tagFields ...string) *Event {
+	ev := &Event{
+		Level:       "error",
+		Message:     e.Message,
+		Fingerprint: fingerprint,
+		Extra:       make(map[string]any, len(e.Fields)),
+		Timestamp:   time.Now(),
+	}
+	if e.Level == logze.LevelFatal || e.Level == zerolog.LevelPanicValue {
+		ev.Level = "fatal"
+	}
+	for _, name := range tagFields {
+		v, ok := e.FieldString(name)
+		if !ok {
+			continue
+		}
+		if ev.Tags == nil {
+			ev.Tags = make(map[string]string, len(tagFields))
+		}
+		ev.Tags[name] = v
+	}
+
+	for k, v := range e.Fields {
+		switch k {
+		case zerolog.ErrorFieldName:
+			ev.Error, _ = e.FieldString(k)
+		case zerolog.ErrorStackFieldName:
+			ev.Stacktrace = stacktrace(e)
+		case zerolog.TimestampFieldName:
+			if s, ok := v.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					ev.Timestamp = t
+					continue
+				}
+			}
+			ev.Extra[k] = v
+		default:
+			if _, ok := ev.Tags[k]; !ok {
+				ev.Extra[k] = v
+			}
+		}
+	}
+	return ev
+}
+
+// stacktrace returns frames of a stack trace of an entry from the oldest one to the newest one.
+func stacktrace(e logze.Entry) []logze.Frame {
+	frames := e.Stack()
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
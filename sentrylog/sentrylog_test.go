@@ -0,0 +1,98 @@
+package sentrylog_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/sentrylog"
+	"github.com/pkg/errors"
+)
+
+func TestHook(t *testing.T) {
+	var events []*sentrylog.Event
+	client := sentrylog.ClientFunc(func(ev *sentrylog.Event) {
+		events = append(events, ev)
+	})
+	logger := logze.New(logze.NewConfig(io.Discard).WithNoDiode().WithStackTrace().
+		WithEntryHooks(sentrylog.Hook(client, sentrylog.WithTagFields("service"))))
+
+	logger.Info("started", "service", "api")
+	logger.Warn("slow request", "service", "api")
+	logger.Err(errors.New("connection refused"), "cannot connect", "service", "api", "attempt", 3)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.Level != "error" {
+		t.Errorf("expected level error, got %q", ev.Level)
+	}
+	if ev.Message != "cannot connect" {
+		t.Errorf("expected message %q, got %q", "cannot connect", ev.Message)
+	}
+	if ev.Error != "connection refused" {
+		t.Errorf("expected error %q, got %q", "connection refused", ev.Error)
+	}
+	if len(ev.Fingerprint) != 1 || ev.Fingerprint[0] != "cannot connect" {
+		t.Errorf("expected fingerprint [cannot connect], got %v", ev.Fingerprint)
+	}
+	if ev.Tags["service"] != "api" {
+		t.Errorf("expected tag service=api, got %v", ev.Tags)
+	}
+	if _, ok := ev.Extra["service"]; ok {
+		t.Errorf("expected tag field not in extra, got %v", ev.Extra)
+	}
+	if ev.Extra["attempt"] == nil {
+		t.Errorf("expected attempt in extra, got %v", ev.Extra)
+	}
+	if ev.Timestamp.IsZero() {
+		t.Error("expected timestamp")
+	}
+
+	if len(ev.Stacktrace) == 0 {
+		t.Fatal("expected stack trace")
+	}
+	var found bool
+	for _, f := range ev.Stacktrace {
+		if f.Function == "TestHook" {
+			found = f.Line > 0 && f.File != ""
+		}
+	}
+	if !found {
+		t.Errorf("expected TestHook frame with file and line, got %+v", ev.Stacktrace)
+	}
+}
+
+func TestHookOptions(t *testing.T) {
+	var events []*sentrylog.Event
+	client := sentrylog.ClientFunc(func(ev *sentrylog.Event) {
+		events = append(events, ev)
+	})
+
+	logger := logze.New(logze.NewConfig(io.Discard).WithNoDiode().
+		WithEntryHooks(sentrylog.Hook(client, sentrylog.WithSampleRate(0))))
+	logger.Error("dropped")
+	if len(events) != 0 {
+		t.Errorf("expected no events with zero sample rate, got %d", len(events))
+	}
+
+	logger = logze.New(logze.NewConfig(io.Discard).WithNoDiode().
+		WithEntryHooks(sentrylog.Hook(client,
+			sentrylog.WithLevels(logze.LevelWarn),
+			sentrylog.WithFingerprint(func(e logze.Entry) []string {
+				return []string{"{{ default }}", e.Level}
+			}),
+		)))
+	logger.Error("skipped")
+	logger.Warn("forwarded")
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Message != "forwarded" {
+		t.Errorf("expected message forwarded, got %q", events[0].Message)
+	}
+	if len(events[0].Fingerprint) != 2 || events[0].Fingerprint[1] != logze.LevelWarn {
+		t.Errorf("expected custom fingerprint, got %v", events[0].Fingerprint)
+	}
+}
@@ -0,0 +1,85 @@
+package logze
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// EnableSignalToggle starts listening for the provided signal (e.g. SIGUSR1) and flips the level
+// of a global logger between its configured level and debug every time the process receives it.
+// Children of a global logger following its level are toggled too, as well as named loggers with a level
+// above debug set by [SetNamedLevel], their levels are restored when the level is toggled back.
+// It is useful to debug live incidents without restarts:
+//
+//	stop := logze.EnableSignalToggle(syscall.SIGUSR1)
+//	defer stop()
+//
+//	// kill -USR1 <pid>
+//
+// Call returned function to stop listening, it restores the configured level if it is toggled.
+func EnableSignalToggle(sig os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig)
+
+	var (
+		mu        sync.Mutex
+		prev      string
+		prevNamed map[string]zerolog.Level
+		toggled   bool
+	)
+	toggle := func(restore bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !toggled && restore {
+			return
+		}
+		level := LevelDebug
+		if toggled {
+			level = prev
+		} else {
+			prev = GetLevel()
+		}
+		// Named levels are changed before the global one, so the new global level means that all levels are toggled
+		if toggled {
+			registry.restore(prevNamed)
+		} else {
+			prevNamed = registry.lower(zerolog.DebugLevel)
+		}
+		if err := SetLevel(level); err != nil {
+			if !toggled {
+				registry.restore(prevNamed)
+			}
+			return
+		}
+		if toggled {
+			prevNamed = nil
+		}
+		toggled = !toggled
+		log.log(log.newEvent(zerolog.NoLevel), "log level is toggled by signal", []any{"signal", sig.String(), "level", level})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				toggle(false)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+			toggle(true)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+//go:build !windows
+
+package logze_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestEnableSignalToggle(t *testing.T) {
+	var b bytes.Buffer
+	setupGlobalLogger(&b, logze.LevelWarn)
+
+	stop := logze.EnableSignalToggle(syscall.SIGUSR1)
+	defer stop()
+
+	child := logze.WithFields("foo", "bar")
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelDebug)
+	if child.GetLevel() != logze.LevelDebug {
+		t.Errorf("expected child level %s, got %s", logze.LevelDebug, child.GetLevel())
+	}
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelWarn)
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelDebug)
+
+	stop()
+	if logze.GetLevel() != logze.LevelWarn {
+		t.Errorf("expected restored level %s, got %s", logze.LevelWarn, logze.GetLevel())
+	}
+}
+
+func TestEnableSignalToggleNamed(t *testing.T) {
+	var b, dbOut bytes.Buffer
+	setupGlobalLogger(&b, logze.LevelWarn)
+
+	// Toggle message is written to the global logger concurrently, so the named logger has its own output
+	db := logze.New(logze.NewConfig(&dbOut).WithLevel(logze.LevelWarn).WithNoDiode()).Named("signal-db")
+	defer logze.ResetNamedLevel("signal-db")
+	if err := logze.SetNamedLevel("signal-db", logze.LevelError); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := logze.EnableSignalToggle(syscall.SIGUSR1)
+	defer stop()
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelDebug)
+	if got := logze.GetNamedLevel("signal-db"); got != logze.LevelDebug {
+		t.Errorf("expected named level %s, got %s", logze.LevelDebug, got)
+	}
+	db.Debug("db debug")
+	if !strings.Contains(dbOut.String(), "db debug") {
+		t.Errorf("expected debug message from named logger, got %s", dbOut.String())
+	}
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelWarn)
+	if got := logze.GetNamedLevel("signal-db"); got != logze.LevelError {
+		t.Errorf("expected restored named level %s, got %s", logze.LevelError, got)
+	}
+
+	sendSignal(t)
+	waitLevel(t, logze.LevelDebug)
+	stop()
+	if got := logze.GetNamedLevel("signal-db"); got != logze.LevelError {
+		t.Errorf("expected restored named level %s, got %s", logze.LevelError, got)
+	}
+}
+
+func sendSignal(t *testing.T) {
+	t.Helper()
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Signal(syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitLevel(t *testing.T, level string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for logze.GetLevel() != level {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected level %s, got %s", level, logze.GetLevel())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
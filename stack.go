@@ -0,0 +1,289 @@
+package logze
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Names of fields of a stack frame in a stack trace, they are the same as in github.com/rs/zerolog/pkgerrors.
+var (
+	StackSourceFileName     = "source"
+	StackSourceLineName     = "line"
+	StackSourceFunctionName = "func"
+)
+
+// maxStackDepth is a maximum number of captured stack frames.
+const maxStackDepth = 32
+
+// Frame represents a single frame of a captured stack trace.
+type Frame struct {
+	// Function is a full name of a function including its package path,
+	// e.g. github.com/maxbolgarin/logze/v2.Logger.Err.
+	Function string
+
+	// File is a full path to a source file.
+	File string
+
+	// Line is a line number in a source file.
+	Line int
+}
+
+// SkipVendorFrames is a frame filter for [Config.WithStackFrameFilter] that omits frames
+// from the standard library (including runtime), vendored and third-party packages.
+func SkipVendorFrames(frame Frame) bool {
+	return !isVendorFrame(frame.Function, frame.File)
+}
+
+// WithStackFrameFilter returns [Config] with a filter of stack frames. Only frames for which filter
+// returns true will be included in a stack trace. Use [SkipVendorFrames] to omit runtime and vendor frames.
+func (c Config) WithStackFrameFilter(filter func(frame Frame) bool) Config {
+	c.StackFrameFilter = filter
+	return c
+}
+
+// WithStackHighlight returns [Config] with a list of modules (or package path prefixes) of an application.
+// Frames of these modules will be marked with "app":true field in a stack trace, so the relevant frame
+// is obvious in aggregated logs.
+func (c Config) WithStackHighlight(modules ...string) Config {
+	c.StackHighlight = modules
+	return c
+}
+
+// WithStackMarshaler returns [Config] with a function that marshals a stack trace of a logged error,
+// e.g. pkgerrors.MarshalStack from github.com/rs/zerolog/pkgerrors. The function gets the error as it was logged
+// and stack trace is not captured by logze. It is applied per logger, global [zerolog.ErrorStackMarshaler]
+// is not used and not changed. Nil result means that an error has no stack trace.
+func (c Config) WithStackMarshaler(marshaler func(err error) any) Config {
+	c.StackMarshaler = marshaler
+	return c
+}
+
+// WithStackDepth returns [Config] with a maximum number of frames in a stack trace.
+// Default value is 32, zero or negative value means default.
+func (c Config) WithStackDepth(depth int) Config {
+	c.StackDepth = depth
+	return c
+}
+
+// WithStackSkip returns [Config] with a number of first frames of a stack trace to omit,
+// e.g. frames of middleware and logging helpers, so the stack starts where it is relevant.
+func (c Config) WithStackSkip(skip int) Config {
+	c.StackSkip = skip
+	return c
+}
+
+// WithStackTraceLevel returns [Config] with stack traces for all messages of provided level and above,
+// e.g. warn, captured where a message is logged. It replaces stack traces of logged errors enabled with
+// [Config.WithStackTrace], so every message of these levels has a stack and other messages don't have it.
+func (c Config) WithStackTraceLevel(level string) Config {
+	c.StackTraceLevel = level
+	return c
+}
+
+// errCallSite is an error that marks a stack trace captured for a message without an error.
+var errCallSite = errors.New("call site")
+
+type stackOptions struct {
+	filter    func(Frame) bool
+	highlight []string
+	marshaler func(err error) any
+	depth     int
+	skip      int
+}
+
+func newStackOptions(cfg Config) *stackOptions {
+	if cfg.StackFrameFilter == nil && len(cfg.StackHighlight) == 0 && cfg.StackMarshaler == nil &&
+		cfg.StackDepth <= 0 && cfg.StackSkip <= 0 {
+		return nil
+	}
+	return &stackOptions{
+		filter:    cfg.StackFrameFilter,
+		highlight: cfg.StackHighlight,
+		marshaler: cfg.StackMarshaler,
+		depth:     cfg.StackDepth,
+		skip:      cfg.StackSkip,
+	}
+}
+
+// captureSize returns a number of frames that should be captured to get a stack trace of the configured depth.
+func (o *stackOptions) captureSize() int {
+	if o == nil {
+		return maxStackDepth
+	}
+	depth := o.depth
+	if depth <= 0 {
+		depth = maxStackDepth
+	}
+	return depth + o.skip
+}
+
+func (o *stackOptions) hasMarshaler() bool {
+	return o != nil && o.marshaler != nil
+}
+
+// marshal adds a stack trace of the error using a custom marshaler.
+func (o *stackOptions) marshal(ev *zerolog.Event, err error) *zerolog.Event {
+	if stack := o.marshaler(err); stack != nil {
+		ev = ev.Interface(zerolog.ErrorStackFieldName, stack)
+	}
+	return ev
+}
+
+// setStack adds a stack trace of the error to the event. All frames are added if there are no stack options.
+func (o *stackOptions) setStack(ev *zerolog.Event, err error) *zerolog.Event {
+	frames := stackFrames(err)
+	if frames == nil {
+		return ev
+	}
+
+	depth := maxStackDepth
+	if o != nil {
+		if o.skip >= len(frames) {
+			return ev
+		}
+		frames = frames[o.skip:]
+		if o.depth > 0 {
+			depth = o.depth
+		}
+	}
+
+	arr := zerolog.Arr()
+	n := 0
+	for _, f := range frames {
+		if n == depth {
+			break
+		}
+		if o != nil && o.filter != nil && !o.filter(f) {
+			continue
+		}
+		n++
+		d := zerolog.Dict().
+			Str(StackSourceFileName, filepath.Base(f.File)).
+			Str(StackSourceLineName, strconv.Itoa(f.Line)).
+			Str(StackSourceFunctionName, shortFuncName(f.Function))
+		if o.isHighlighted(f) {
+			d = d.Bool("app", true)
+		} else if isVendorFrame(f.Function, f.File) {
+			// Source is a base name, so a full path is checked here for ConsoleOptions.CollapseVendor
+			d = d.Bool("vendor", true)
+		}
+		arr = arr.Dict(d)
+	}
+
+	return ev.Array(zerolog.ErrorStackFieldName, arr)
+}
+
+func (o *stackOptions) isHighlighted(f Frame) bool {
+	if o == nil {
+		return false
+	}
+	for _, module := range o.highlight {
+		if strings.HasPrefix(f.Function, module) {
+			return true
+		}
+	}
+	return false
+}
+
+// stackError is an error with a stack trace captured where it was created. It is used instead of
+// github.com/pkg/errors to keep the core package free of dependencies.
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// withStack returns the error with a stack trace of provided size starting from a caller of withStack.
+func withStack(err error, size int) error {
+	if err == nil {
+		return nil
+	}
+	pcs := make([]uintptr, size)
+	// Skip runtime.Callers and withStack
+	n := runtime.Callers(2, pcs)
+	return &stackError{err: err, pcs: pcs[:n]}
+}
+
+// callSiteStack returns an error with a stack trace that starts at a caller of the function that called
+// callSiteStack with skip equal to 0.
+func callSiteStack(skip, size int) error {
+	pcs := make([]uintptr, size)
+	// Skip runtime.Callers, callSiteStack and its caller
+	n := runtime.Callers(3+skip, pcs)
+	return &stackError{err: errCallSite, pcs: pcs[:n]}
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// Format prints the error with its stack trace for %+v verb in the same way as github.com/pkg/errors does.
+func (e *stackError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%+v", e.err)
+		for _, f := range e.frames() {
+			fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = io.WriteString(s, e.Error())
+	}
+}
+
+func (e *stackError) frames() []Frame {
+	if len(e.pcs) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(e.pcs))
+	iter := runtime.CallersFrames(e.pcs)
+	for {
+		f, more := iter.Next()
+		frame := Frame{Function: "unknown", File: f.File, Line: f.Line}
+		if f.Function != "" {
+			frame.Function = f.Function
+		}
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// stackFrames returns frames of the first error in the chain that has a stack trace captured by [Logger].
+func stackFrames(err error) []Frame {
+	for err != nil {
+		if se, ok := err.(*stackError); ok {
+			return se.frames()
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+// shortFuncName returns a function name without package path as github.com/pkg/errors does.
+func shortFuncName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,181 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/pkgerrors"
+)
+
+type stackEntry struct {
+	Stack []map[string]any `json:"stack"`
+}
+
+func TestStackFrameFilter(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithStackTrace().WithNoDiode().WithStackFrameFilter(logze.SkipVendorFrames)
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("some error"), "message")
+
+	var entry stackEntry
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Stack) == 0 {
+		t.Fatalf("expected stack, got %s", b.String())
+	}
+	found := false
+	for _, frame := range entry.Stack {
+		if frame["func"] == "goexit" || frame["func"] == "tRunner" {
+			t.Errorf("expected runtime frames to be filtered, got %v", frame)
+		}
+		if frame["func"] == "TestStackFrameFilter" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected test frame in stack, got %s", b.String())
+	}
+}
+
+func TestStackHighlight(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig(&b).WithStackTrace().WithNoDiode().WithStackHighlight("github.com/maxbolgarin/logze/v2_test")
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("some error"), "message")
+
+	var entry stackEntry
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, frame := range entry.Stack {
+		isApp := frame["func"] == "TestStackHighlight"
+		if app, _ := frame["app"].(bool); app != isApp {
+			t.Errorf("unexpected highlighting of frame %v", frame)
+		}
+	}
+}
+
+func TestStackHighlightConsole(t *testing.T) {
+	var b bytes.Buffer
+	cfg := logze.NewConfig().WithConsoleOptions(logze.ConsoleOptions{Out: &b, NoColor: true}).
+		WithStackTrace().WithNoDiode().WithStackHighlight("github.com/maxbolgarin/logze/v2_test")
+	logger := logze.New(cfg)
+
+	logger.Err(errors.New("some error"), "message")
+
+	if !strings.Contains(b.String(), "> TestStackHighlightConsole") {
+		t.Errorf("expected highlighted frame, got %s", b.String())
+	}
+}
+
+func TestStackMarshaler(t *testing.T) {
+	var b, other bytes.Buffer
+	globalMarshaler := zerolog.ErrorStackMarshaler
+
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithStackTrace().WithStackMarshaler(pkgerrors.MarshalStack))
+	otherLogger := logze.New(logze.NewConfig(&other).WithNoDiode().WithStackTrace())
+
+	if fmt.Sprintf("%p", zerolog.ErrorStackMarshaler) != fmt.Sprintf("%p", globalMarshaler) {
+		t.Errorf("expected global stack marshaler not to be changed")
+	}
+
+	logger.Err(errors.New("with stack"), "message")
+	if !strings.Contains(b.String(), `"func":"TestStackMarshaler"`) {
+		t.Errorf("expected stack from custom marshaler, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Err(stderrors.New("without stack"), "message")
+	if strings.Contains(b.String(), `"stack"`) {
+		t.Errorf("expected no stack for error without stack trace, got %s", b.String())
+	}
+
+	otherLogger.Err(stderrors.New("default stack"), "message")
+	if !strings.Contains(other.String(), `"stack":[`) {
+		t.Errorf("expected default stack in other logger, got %s", other.String())
+	}
+}
+
+func TestStackDepthAndSkip(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithStackTrace().WithNoDiode().WithStackSkip(3).WithStackDepth(1))
+
+	logger.Err(stderrors.New("some error"), "message")
+
+	var entry stackEntry
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Stack) != 1 || entry.Stack[0]["func"] != "TestStackDepthAndSkip" {
+		t.Errorf("expected one frame of the test, got %s", b.String())
+	}
+
+	b.Reset()
+	logger = logze.New(logze.NewConfig(&b).WithStackTrace().WithNoDiode().WithStackSkip(100))
+	logger.Err(stderrors.New("some error"), "message")
+	if strings.Contains(b.String(), `"stack"`) {
+		t.Errorf("expected no stack if all frames are skipped, got %s", b.String())
+	}
+}
+
+func TestStackTraceLevel(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithStackTraceLevel("warn").WithNoDiode())
+
+	logger.Info("info")
+	if strings.Contains(b.String(), `"stack"`) {
+		t.Errorf("expected no stack for info, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Warn("warn")
+	var entry stackEntry
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Stack) == 0 || entry.Stack[0]["func"] != "TestStackTraceLevel" {
+		t.Errorf("expected stack starting in the test, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Err(stderrors.New("some error"), "error")
+	entry = stackEntry{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entry.Stack) == 0 || entry.Stack[0]["func"] != "TestStackTraceLevel" {
+		t.Errorf("expected stack starting in the test, got %s", b.String())
+	}
+
+	if _, err := logze.TryNew(logze.NewConfig(&b).WithStackTraceLevel("loud")); err == nil {
+		t.Error("expected error for invalid stack trace level")
+	}
+}
+
+func TestSkipVendorFrames(t *testing.T) {
+	for _, c := range []struct {
+		frame    logze.Frame
+		expected bool
+	}{
+		{logze.Frame{Function: "main.main", File: "/src/app/main.go"}, true},
+		{logze.Frame{Function: "github.com/acme/app/handlers.Serve", File: "/src/app/handlers/serve.go"}, true},
+		{logze.Frame{Function: "runtime.goexit", File: "/opt/go/src/runtime/asm_amd64.s"}, false},
+		{logze.Frame{Function: "net/http.(*conn).serve", File: "/opt/go/src/net/http/server.go"}, false},
+		{logze.Frame{Function: "github.com/acme/lib.Do", File: "/home/user/go/pkg/mod/github.com/acme/lib@v1.0.0/lib.go"}, false},
+		{logze.Frame{Function: "github.com/acme/lib.Do", File: "/src/app/vendor/github.com/acme/lib/lib.go"}, false},
+	} {
+		if got := logze.SkipVendorFrames(c.frame); got != c.expected {
+			t.Errorf("expected %v for %s, got %v", c.expected, c.frame.Function, got)
+		}
+	}
+}
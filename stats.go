@@ -0,0 +1,72 @@
+package logze
+
+import "sync/atomic"
+
+// OutputStats contains counters of the logger output, they can be exported to metrics to alert on loss of logs.
+type OutputStats struct {
+	// Dropped is a number of entries dropped by diode or async writer because its buffer was full.
+	// It is cumulative, it is not reset by [Logger.Reload].
+	Dropped int64
+
+	// Queued is a number of entries waiting to be written by async writer, see [Config.WithAsync].
+	// It is 0 for diode.
+	Queued int
+
+	// QueueSize is a max number of entries in the queue of async writer. It is 0 for diode.
+	QueueSize int
+
+	// Lost is a number of entries that current writers failed to write.
+	Lost int64
+
+	// Throttled is a number of entries dropped because of [Config.WithMaxThroughput] limit
+	// since the output is created.
+	Throttled int64
+}
+
+// Stats returns counters of dropped and lost entries of the logger output. It is safe for concurrent use.
+func (l Logger) Stats() OutputStats {
+	if l.out == nil {
+		return OutputStats{}
+	}
+	out := l.out.load()
+
+	var s OutputStats
+	if out.dropped != nil {
+		s.Dropped = out.dropped.n.Load()
+	}
+	if aw, ok := out.buffer.(*AsyncWriter); ok {
+		s.Queued = aw.Len()
+		s.QueueSize = aw.Cap()
+	}
+	if out.throttle != nil {
+		s.Throttled = out.throttle.throttled.Load()
+	}
+	for _, w := range out.writers {
+		s.Lost += w.lost.Load()
+	}
+	return s
+}
+
+// DropCounter is an optional interface of [ErrorCounter] to count entries dropped by diode or async writer.
+// [SimpleErrorCounter] implements it.
+type DropCounter interface {
+	IncDropped(n int)
+}
+
+// dropCounter counts entries dropped by diode or async writer and reports them to [DropCounter] if it is set.
+type dropCounter struct {
+	n  atomic.Int64
+	dc DropCounter
+}
+
+func newDropCounter(ec ErrorCounter) *dropCounter {
+	dc, _ := ec.(DropCounter)
+	return &dropCounter{dc: dc}
+}
+
+func (c *dropCounter) add(n int) {
+	c.n.Add(int64(n))
+	if c.dc != nil {
+		c.dc.IncDropped(n)
+	}
+}
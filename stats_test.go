@@ -0,0 +1,57 @@
+package logze_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestStatsDropped(t *testing.T) {
+	target := newBlockingWriter()
+	var ec logze.SimpleErrorCounter
+	logger := logze.New(logze.NewConfig(target).WithErrorCounter(&ec).
+		WithAsync(logze.AsyncOptions{Policy: logze.AsyncDropNewest, Size: 2}))
+
+	logger.Info("first")
+	<-target.started
+	for i := 0; i < 5; i++ {
+		logger.Info("message")
+	}
+
+	stats := logger.Stats()
+	if stats.Dropped != 3 || stats.Queued != 2 || stats.QueueSize != 2 {
+		t.Errorf("expected 3 dropped and 2 of 2 queued, got %+v", stats)
+	}
+	if ec.Dropped.Load() != 3 {
+		t.Errorf("expected 3, got %d", ec.Dropped.Load())
+	}
+
+	close(target.release)
+	// Counter is cumulative after reload
+	if err := logger.Reload(logze.NewConfig(&bytes.Buffer{})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stats := logger.Stats(); stats.Dropped != 3 || stats.Queued != 0 {
+		t.Errorf("expected 3 dropped and empty queue after reload, got %+v", stats)
+	}
+	if err := logger.Close(); err == nil {
+		t.Errorf("expected error about dropped entries")
+	}
+}
+
+func TestStatsLost(t *testing.T) {
+	target := &flakyWriter{}
+	target.fail.Store(true)
+	logger := logze.New(logze.NewConfig(target).WithNoDiode())
+
+	logger.Info("message")
+	logger.Info("message")
+
+	if stats := logger.Stats(); stats.Lost != 2 || stats.Dropped != 0 {
+		t.Errorf("expected 2 lost entries, got %+v", stats)
+	}
+	if stats := logze.Nop().Stats(); stats != (logze.OutputStats{}) {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+}
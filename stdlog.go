@@ -0,0 +1,114 @@
+package logze
+
+import (
+	stdlog "log"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// StdLogAt returns [log.Logger] that writes every message as an entry of provided level, so the logger
+// can be used in places that accept only a standard logger, e.g. ErrorLog of [net/http.Server] or
+// [net/http/httputil.ReverseProxy]. Unlike [Logger.Write] it honors the level and the list of messages to ignore.
+// Unknown level is logged without level.
+func (l Logger) StdLogAt(level string) *stdlog.Logger {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		lvl = zerolog.NoLevel
+	}
+	return stdlog.New(stdLevelWriter{l: l, level: lvl}, "", 0)
+}
+
+// DefaultStdPrefixLevels is a default map of prefixes of messages to levels for [Logger.StdLogLevels].
+// It has common prefixes like "ERROR:", "[WARN]" or "debug " and prefixes of libraries, e.g. "[ERR]" of HashiCorp tools.
+var DefaultStdPrefixLevels = map[string]string{
+	"TRACE":     LevelTrace,
+	"[TRACE]":   LevelTrace,
+	"DEBUG":     LevelDebug,
+	"[DEBUG]":   LevelDebug,
+	"INFO":      LevelInfo,
+	"[INFO]":    LevelInfo,
+	"WARN":      LevelWarn,
+	"[WARN]":    LevelWarn,
+	"WARNING":   LevelWarn,
+	"[WARNING]": LevelWarn,
+	"ERROR":     LevelError,
+	"[ERROR]":   LevelError,
+	"[ERR]":     LevelError,
+	"FATAL":     LevelFatal,
+	"[FATAL]":   LevelFatal,
+	"PANIC":     LevelFatal,
+	"[PANIC]":   LevelFatal,
+}
+
+// StdLogLevels returns [log.Logger] that detects a level of every message by its prefix using provided map
+// of prefixes to levels, e.g. "ERROR:" to "error". Prefixes are matched ignoring case, the longest one wins
+// and it is removed from a message with a following colon and spaces. Messages without known prefixes
+// are logged in default level. Nil map means [DefaultStdPrefixLevels].
+func (l Logger) StdLogLevels(defaultLevel string, prefixes map[string]string) *stdlog.Logger {
+	lvl, err := zerolog.ParseLevel(defaultLevel)
+	if err != nil {
+		lvl = zerolog.NoLevel
+	}
+	if prefixes == nil {
+		prefixes = DefaultStdPrefixLevels
+	}
+	w := stdLevelWriter{l: l, level: lvl}
+	for prefix, level := range prefixes {
+		if prefixLvl, err := zerolog.ParseLevel(level); err == nil && prefix != "" {
+			w.prefixes = append(w.prefixes, stdPrefix{prefix: prefix, level: prefixLvl})
+		}
+	}
+	// Longest prefixes go first, so the first match is the most specific one
+	sort.Slice(w.prefixes, func(i, j int) bool {
+		if len(w.prefixes[i].prefix) != len(w.prefixes[j].prefix) {
+			return len(w.prefixes[i].prefix) > len(w.prefixes[j].prefix)
+		}
+		return w.prefixes[i].prefix < w.prefixes[j].prefix
+	})
+	return stdlog.New(w, "", 0)
+}
+
+type stdPrefix struct {
+	prefix string
+	level  zerolog.Level
+}
+
+// stdLevelWriter logs every written message in its level or in a level detected by a prefix of the message,
+// standard logger calls Write once per message.
+type stdLevelWriter struct {
+	l        Logger
+	level    zerolog.Level
+	prefixes []stdPrefix
+}
+
+func (w stdLevelWriter) Write(p []byte) (int, error) {
+	msg := string(p)
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	level := w.level
+	for _, pr := range w.prefixes {
+		if len(msg) >= len(pr.prefix) && strings.EqualFold(msg[:len(pr.prefix)], pr.prefix) && isPrefixEnd(pr.prefix, msg[len(pr.prefix):]) {
+			level = pr.level
+			msg = strings.TrimLeft(msg[len(pr.prefix):], ": ")
+			break
+		}
+	}
+	w.l.log(w.l.newEvent(level), msg, nil)
+	return len(p), nil
+}
+
+// isPrefixEnd returns true if the rest of a message starts after a whole prefix, e.g. "INFO: x" but not "INFORMATION".
+// Prefixes that end with a separator, e.g. "[WARN]" or "E ", match any rest.
+func isPrefixEnd(prefix, rest string) bool {
+	if !isWordByte(prefix[len(prefix)-1]) {
+		return true
+	}
+	return rest == "" || !isWordByte(rest[0])
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
@@ -0,0 +1,70 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestStdLogAt(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelWarn).WithToIgnore("ignored"))
+
+	logger.StdLogAt(logze.LevelInfo).Print("info message")
+	if b.Len() != 0 {
+		t.Errorf("expected no entry below the level, got %s", b.String())
+	}
+
+	logger.StdLogAt(logze.LevelError).Print("ignored message")
+	if b.Len() != 0 {
+		t.Errorf("expected ignored message, got %s", b.String())
+	}
+
+	logger.StdLogAt(logze.LevelError).Printf("http: TLS handshake error from %s", "127.0.0.1")
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry["level"] != "error" || entry["message"] != "http: TLS handshake error from 127.0.0.1" {
+		t.Errorf("expected error entry without newline, got %s", b.String())
+	}
+}
+
+func TestStdLogLevels(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithLevel(logze.LevelDebug))
+	std := logger.StdLogLevels(logze.LevelInfo, nil)
+
+	tests := []struct {
+		line  string
+		level string
+		msg   string
+	}{
+		{"ERROR: connection refused", "error", "connection refused"},
+		{"[WARN] retrying", "warn", "retrying"},
+		{"debug cache miss", "debug", "cache miss"},
+		{"[ERR] raft: failed", "error", "raft: failed"},
+		{"INFORMATION is not a prefix", "info", "INFORMATION is not a prefix"},
+		{"plain message", "info", "plain message"},
+	}
+	for _, tt := range tests {
+		b.Reset()
+		std.Print(tt.line)
+		var entry map[string]any
+		if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry["level"] != tt.level || entry["message"] != tt.msg {
+			t.Errorf("%q: expected %s %q, got %s", tt.line, tt.level, tt.msg, b.String())
+		}
+	}
+
+	b.Reset()
+	logger.StdLogLevels(logze.LevelWarn, map[string]string{"E ": logze.LevelError}).Print("E disk full")
+	if !strings.Contains(b.String(), `"level":"error"`) {
+		t.Errorf("expected custom prefix, got %s", b.String())
+	}
+}
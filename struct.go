@@ -0,0 +1,141 @@
+package logze
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ObjField is a field that expands exported fields of a struct into separate fields, use [Obj] to create it.
+type ObjField struct {
+	v any
+}
+
+// Obj returns a field that expands exported fields of provided struct (or pointer to a struct) into separate fields.
+// It should be used in place of a (key, value) pair in logging methods and [Logger.WithFields]:
+//
+//	type Request struct {
+//		ID       string `log:"request_id"`
+//		Method   string `json:"method"`
+//		Password string `log:"-"`
+//	}
+//	logger.Info("request", logze.Obj(req))
+//	// {"level":"info","request_id":"..","method":"GET","message":"request"}
+//
+// Name of a field is taken from log tag, then from json tag, then the name of a struct field is used.
+// Fields with "-" tag are skipped, fields of embedded structs are promoted. Nil pointer adds no fields.
+func Obj(v any) ObjField {
+	return ObjField{v: v}
+}
+
+// WithStruct returns [Logger] with applied exported fields of provided struct, see [Obj] for details.
+func (l Logger) WithStruct(v any) Logger {
+	return l.WithFields(Obj(v))
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFieldsCache is a cache of fields of struct types, map[reflect.Type][]structField.
+var structFieldsCache sync.Map
+
+// appendStruct appends (key, value) pairs of exported fields of a struct.
+func appendStruct(out []any, v any) []any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return out
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return out
+	}
+
+	for _, f := range cachedStructFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			continue
+		}
+		out = append(out, f.name, expandValue(fv.Interface()))
+	}
+	return out
+}
+
+func cachedStructFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields := structFields(t, nil, map[reflect.Type]bool{t: true})
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// structFields returns fields of a struct type and fields of its embedded structs. Embedded structs
+// of types that are already being visited are skipped like in encoding/json, so recursive types are supported.
+func structFields(t reflect.Type, index []int, visiting map[reflect.Type]bool) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, skip := structFieldName(f)
+		if skip {
+			continue
+		}
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if !visiting[ft] {
+				visiting[ft] = true
+				fields = append(fields, structFields(ft, fieldIndex, visiting)...)
+				delete(visiting, ft)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{name: name, index: fieldIndex})
+	}
+	return fields
+}
+
+// structFieldName returns a name of a field from log or json tag and true if the field should be skipped.
+func structFieldName(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"log", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return "", true
+		}
+		if name != "" {
+			return name, false
+		}
+	}
+	return "", false
+}
+
+// fieldByIndex returns a nested field, it returns false if there is a nil embedded pointer on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
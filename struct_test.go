@@ -0,0 +1,77 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+type testMeta struct {
+	Service string `log:"service"`
+}
+
+type testRequest struct {
+	testMeta
+	ID       string `log:"request_id" json:"id"`
+	Method   string `json:"method,omitempty"`
+	Path     string
+	Password string `log:"-"`
+	Token    string `json:"-"`
+	internal string
+}
+
+func TestObj(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	req := testRequest{testMeta: testMeta{Service: "api"}, ID: "abc", Method: "GET", Path: "/x", Password: "secret", Token: "token", internal: "internal"}
+	logger.Info("request", logze.Obj(&req), "status", 200)
+	if !strings.Contains(b.String(), `"service":"api","request_id":"abc","method":"GET","Path":"/x","status":200`) {
+		t.Errorf("expected struct fields, got %s", b.String())
+	}
+	for _, s := range []string{"secret", "token", "internal"} {
+		if strings.Contains(b.String(), s) {
+			t.Errorf("expected no %s, got %s", s, b.String())
+		}
+	}
+
+	b.Reset()
+	logger.WithStruct(req).Info("message")
+	if !strings.Contains(b.String(), `"request_id":"abc"`) {
+		t.Errorf("expected struct fields in logger, got %s", b.String())
+	}
+
+	b.Reset()
+	var nilReq *testRequest
+	logger.Info("message", logze.Obj(nilReq))
+	if strings.Contains(b.String(), "request_id") {
+		t.Errorf("expected no fields for nil pointer, got %s", b.String())
+	}
+}
+
+type testNode struct {
+	*testNode
+	Name string
+}
+
+func TestObjRecursive(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("node", logze.Obj(testNode{testNode: &testNode{Name: "parent"}, Name: "child"}))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recursive struct is not logged")
+	}
+	if !strings.Contains(b.String(), `"Name":"child"`) || strings.Contains(b.String(), "parent") {
+		t.Errorf("expected fields of the top level struct, got %s", b.String())
+	}
+}
@@ -0,0 +1,146 @@
+package logze
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// SummaryFieldName is a field name for a human-friendly summary of an entry, see [Config.WithSummary].
+var SummaryFieldName = "summary"
+
+// WithSummary returns [Config] with a template of a "summary" field that is computed from other fields
+// of an entry at encode time. Placeholders are field names in curly braces, e.g. template
+// "{method} {path} → {status} in {duration}" gives "GET /api/users → 200 in 12ms".
+// Summary is added only to entries having all fields from the template,
+// so structured fields are kept for machines and the summary helps to scan console output.
+func (c Config) WithSummary(template string) Config {
+	c.Summary = template
+	return c
+}
+
+// summaryPart is either a text or a field placeholder of a summary template.
+type summaryPart struct {
+	text  string
+	field string
+}
+
+type summaryTemplate []summaryPart
+
+func parseSummaryTemplate(template string) (summaryTemplate, error) {
+	var t summaryTemplate
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			t = append(t, summaryPart{text: template})
+			break
+		}
+		if start > 0 {
+			t = append(t, summaryPart{text: template[:start]})
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in summary template at %d", start)
+		}
+		field := strings.TrimSpace(template[start+1 : start+end])
+		if field == "" {
+			return nil, fmt.Errorf("empty placeholder in summary template at %d", start)
+		}
+		t = append(t, summaryPart{field: field})
+		template = template[start+end+1:]
+	}
+	return t, nil
+}
+
+// render returns a summary built from provided fields and false if some of the fields are missing.
+func (t summaryTemplate) render(fields map[string]any) (string, bool) {
+	var b strings.Builder
+	for _, part := range t {
+		if part.field == "" {
+			b.WriteString(part.text)
+			continue
+		}
+		v, ok := fields[part.field]
+		if !ok {
+			return "", false
+		}
+		switch v := v.(type) {
+		case string:
+			b.WriteString(v)
+		case json.Number:
+			b.WriteString(v.String())
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", false
+			}
+			b.Write(data)
+		}
+	}
+	return b.String(), true
+}
+
+// summaryWriter adds a summary field to every JSON entry before writing it to the underlying writer.
+type summaryWriter struct {
+	w        io.Writer
+	template summaryTemplate
+}
+
+func (w summaryWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(w.addSummary(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w summaryWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.w.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+	if _, err := lw.WriteLevel(level, w.addSummary(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w summaryWriter) addSummary(p []byte) []byte {
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		// Not a JSON entry, e.g. raw bytes written with Logger.Write
+		return p
+	}
+	if _, ok := fields[SummaryFieldName]; ok {
+		return p
+	}
+	summary, ok := w.template.render(fields)
+	if !ok {
+		return p
+	}
+
+	end := bytes.LastIndexByte(p, '}')
+	if end < 0 {
+		return p
+	}
+	value, err := json.Marshal(summary)
+	if err != nil {
+		return p
+	}
+	out := make([]byte, 0, len(p)+len(SummaryFieldName)+len(value)+4)
+	out = append(out, p[:end]...)
+	if len(fields) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, '"')
+	out = append(out, SummaryFieldName...)
+	out = append(out, '"', ':')
+	out = append(out, value...)
+	return append(out, p[end:]...)
+}
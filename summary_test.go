@@ -0,0 +1,59 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestSummary(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithSummary("{method} {path} → {status} in {duration}"))
+
+	logger.Info("request", "method", "GET", "path", "/api/users", "status", 200, "duration", "12ms")
+	if !strings.Contains(b.String(), `"summary":"GET /api/users → 200 in 12ms"`) {
+		t.Errorf("expected summary field, got %s", b.String())
+	}
+	if !strings.Contains(b.String(), `"status":200`) {
+		t.Errorf("expected structured fields to be kept, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Info("no request fields", "method", "GET")
+	if strings.Contains(b.String(), `"summary"`) {
+		t.Errorf("expected no summary for entry without all fields, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Info("own summary", "method", "GET", "path", "/", "status", 200, "duration", "1ms", "summary", "custom")
+	if strings.Count(b.String(), `"summary"`) != 1 || !strings.Contains(b.String(), `"summary":"custom"`) {
+		t.Errorf("expected existing summary to be kept, got %s", b.String())
+	}
+}
+
+func TestSummaryEntryHooks(t *testing.T) {
+	var summary any
+	logger := logze.New(logze.NewConfig(&bytes.Buffer{}).WithNoDiode().WithSummary("{level}: {message}").
+		WithEntryHooks(func(e logze.Entry) {
+			summary = e.Fields[logze.SummaryFieldName]
+		}))
+
+	logger.Warn("disk is full")
+	if summary != "warn: disk is full" {
+		t.Errorf("expected summary in entry hook, got %v", summary)
+	}
+}
+
+func TestSummaryInvalidTemplate(t *testing.T) {
+	for _, tmpl := range []string{"{method", "{} {path}"} {
+		cfg := logze.NewConfig(&bytes.Buffer{}).WithSummary(tmpl)
+		if _, err := logze.TryNew(cfg); err == nil {
+			t.Errorf("expected error for template %q, got nil", tmpl)
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for template %q, got nil", tmpl)
+		}
+	}
+}
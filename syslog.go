@@ -0,0 +1,283 @@
+package logze
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Enumerating formats of messages of [SyslogWriter].
+const (
+	// SyslogRFC3164 is a BSD syslog format, it is understood by every syslog daemon.
+	SyslogRFC3164 = "rfc3164"
+	// SyslogRFC5424 is a structured syslog format with a full timestamp, application name and process ID.
+	SyslogRFC5424 = "rfc5424"
+)
+
+// syslogFacilities maps names of syslog facilities to their codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogLocalPaths is a list of paths of a local syslog socket on Unix systems.
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogOptions is using for configuring [NewSyslogWriter] and [Config.WithSyslog].
+type SyslogOptions struct {
+	// Network is a network of a syslog server: "udp", "tcp" or "unix".
+	// Default value is empty, it means a local syslog socket (e.g. /dev/log) and Addr is ignored.
+	Network string
+
+	// Addr is an address of a syslog server, e.g. "localhost:514".
+	Addr string
+
+	// Facility is a name of a syslog facility: kern, user, mail, daemon, auth, syslog, lpr, news, uucp,
+	// cron, authpriv, ftp or local0-local7. Default value is user.
+	Facility string
+
+	// Tag is a name of an application in messages. Default value is a name of the executable.
+	Tag string
+
+	// Format is a format of messages: rfc3164 or rfc5424. Default value is rfc3164.
+	Format string
+}
+
+// SyslogWriter is an [io.Writer] that sends entries to a syslog server (e.g. rsyslog) with severities
+// mapped from levels: trace and debug are debug, info is info, warn is warning, error is err,
+// fatal is emerg and panic is alert. An entry is sent as a message of syslog as is, in JSON.
+// It reconnects after a failed write. It is safe for concurrent use. Use [NewSyslogWriter] to create it.
+type SyslogWriter struct {
+	opts     SyslogOptions
+	facility int
+	hostname string
+	pid      string
+	// err is an error of options, it is returned by every write
+	err error
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// WithSyslog returns [Config] with added [SyslogWriter] sending entries to a syslog server with provided
+// facility and tag. Empty network and address mean a local syslog socket (e.g. /dev/log on Linux):
+//
+//	cfg := logze.NewConfig().WithSyslog("", "", "local0", "billing")
+//
+// Connection is established with the first entry, an invalid facility is reported by [Config.Validate].
+// Use [NewSyslogWriter] and [Config.WithWriter] to set other options or to check connection at startup.
+func (c Config) WithSyslog(network, addr, facility, tag string) Config {
+	w, err := newSyslogWriter(SyslogOptions{Network: network, Addr: addr, Facility: facility, Tag: tag})
+	w.err = err
+	return c.WithWriter(w)
+}
+
+// NewSyslogWriter returns [SyslogWriter] connected to a syslog server with provided options.
+// It returns an error if options are invalid or the server is unavailable.
+func NewSyslogWriter(opts SyslogOptions) (*SyslogWriter, error) {
+	w, err := newSyslogWriter(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// validate returns an error of options, it is reported by [Config.Validate].
+func (w *SyslogWriter) validate() error {
+	return w.err
+}
+
+func newSyslogWriter(opts SyslogOptions) (*SyslogWriter, error) {
+	if opts.Facility == "" {
+		opts.Facility = "user"
+	}
+	if opts.Tag == "" {
+		opts.Tag = filepath.Base(os.Args[0])
+	}
+	if opts.Format == "" {
+		opts.Format = SyslogRFC3164
+	}
+	w := &SyslogWriter{opts: opts, pid: strconv.Itoa(os.Getpid())}
+	w.hostname, _ = os.Hostname()
+	if w.hostname == "" {
+		w.hostname = "localhost"
+	}
+
+	facility, ok := syslogFacilities[opts.Facility]
+	if !ok {
+		return w, fmt.Errorf("syslog: unknown facility %q", opts.Facility)
+	}
+	w.facility = facility
+	switch opts.Format {
+	case SyslogRFC3164, SyslogRFC5424:
+	default:
+		return w, fmt.Errorf("syslog: unknown format %q", opts.Format)
+	}
+	return w, nil
+}
+
+// Write sends an entry with a severity of its level field, entries without level are sent with info severity.
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *SyslogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	msg := w.format(syslogSeverity(level), bytes.TrimRight(p, "\n"))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		if _, err := w.conn.Write(msg); err == nil {
+			return len(p), nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	// Syslog server may be restarted, so try to reconnect once
+	if err := w.dial(); err != nil {
+		return 0, err
+	}
+	if _, err := w.conn.Write(msg); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Name returns a name of the writer for errors of [Logger.Close].
+func (w *SyslogWriter) Name() string {
+	if w.opts.Network == "" {
+		return "syslog"
+	}
+	return "syslog " + w.opts.Network + "://" + w.opts.Addr
+}
+
+// Close closes connection to a syslog server.
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *SyslogWriter) connect() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dial()
+}
+
+// dial connects to a syslog server, it is called with the lock held.
+func (w *SyslogWriter) dial() error {
+	if w.opts.Network != "" {
+		conn, err := net.Dial(w.opts.Network, w.opts.Addr)
+		if err != nil {
+			return fmt.Errorf("syslog: %w", err)
+		}
+		w.conn = conn
+		return nil
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range syslogLocalPaths {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("syslog: local syslog socket is not found")
+}
+
+// format returns a syslog message, it ends with a newline to delimit messages in stream connections.
+func (w *SyslogWriter) format(severity int, msg []byte) []byte {
+	out := make([]byte, 0, len(msg)+64)
+	out = append(out, '<')
+	out = strconv.AppendInt(out, int64(w.facility*8+severity), 10)
+	out = append(out, '>')
+
+	now := time.Now()
+	switch w.opts.Format {
+	case SyslogRFC5424:
+		out = append(out, "1 "...)
+		out = now.AppendFormat(out, "2006-01-02T15:04:05.000000Z07:00")
+		out = append(out, ' ')
+		out = append(out, w.hostname...)
+		out = append(out, ' ')
+		out = append(out, w.opts.Tag...)
+		out = append(out, ' ')
+		out = append(out, w.pid...)
+		out = append(out, " - - "...)
+	default:
+		out = now.AppendFormat(out, time.Stamp)
+		out = append(out, ' ')
+		if w.opts.Network != "" {
+			// Local daemon adds a hostname itself
+			out = append(out, w.hostname...)
+			out = append(out, ' ')
+		}
+		out = append(out, w.opts.Tag...)
+		out = append(out, '[')
+		out = append(out, w.pid...)
+		out = append(out, "]: "...)
+	}
+	out = append(out, msg...)
+	return append(out, '\n')
+}
+
+// syslogSeverity maps a level to a syslog severity.
+func syslogSeverity(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 7
+	case zerolog.WarnLevel:
+		return 4
+	case zerolog.ErrorLevel:
+		return 3
+	case zerolog.FatalLevel:
+		return 0
+	case zerolog.PanicLevel:
+		return 1
+	default:
+		return 6
+	}
+}
+
+// entryLevel returns a level of a JSON entry or [zerolog.NoLevel] if it is not found.
+func entryLevel(p []byte) zerolog.Level {
+	key := []byte(`"` + zerolog.LevelFieldName + `":"`)
+	start := bytes.Index(p, key)
+	if start < 0 {
+		return zerolog.NoLevel
+	}
+	value := p[start+len(key):]
+	end := bytes.IndexByte(value, '"')
+	if end < 0 {
+		return zerolog.NoLevel
+	}
+	level, err := zerolog.ParseLevel(string(value[:end]))
+	if err != nil {
+		return zerolog.NoLevel
+	}
+	return level
+}
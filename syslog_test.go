@@ -0,0 +1,94 @@
+package logze_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func listenSyslog(t *testing.T) (*net.UDPConn, func() string) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	buf := make([]byte, 4096)
+	return conn, func() string {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(buf[:n])
+	}
+}
+
+func TestSyslogRFC3164(t *testing.T) {
+	conn, read := listenSyslog(t)
+	cfg := logze.NewConfig().WithNoDiode().WithSyslog("udp", conn.LocalAddr().String(), "local0", "billing")
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logze.New(cfg)
+	defer logger.Close()
+
+	logger.Info("started", "port", 8080)
+	msg := read()
+	// local0 is 16, info is 6
+	if !strings.HasPrefix(msg, "<134>") {
+		t.Errorf("expected <134> priority, got %s", msg)
+	}
+	if !strings.Contains(msg, " billing[") || !strings.Contains(msg, `"message":"started"`) {
+		t.Errorf("expected tag and entry, got %s", msg)
+	}
+
+	logger.Error("failed")
+	if msg := read(); !strings.HasPrefix(msg, "<131>") {
+		t.Errorf("expected <131> priority, got %s", msg)
+	}
+}
+
+func TestSyslogRFC5424WithDiode(t *testing.T) {
+	conn, read := listenSyslog(t)
+	w, err := logze.NewSyslogWriter(logze.SyslogOptions{
+		Network:  "udp",
+		Addr:     conn.LocalAddr().String(),
+		Facility: "daemon",
+		Tag:      "api",
+		Format:   logze.SyslogRFC5424,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger := logze.New(logze.NewConfig(w).WithFieldNames(logze.FieldNames{Level: "severity"}))
+	defer logger.Close()
+
+	// Diode loses levels of entries, so the writer finds them in entries
+	logger.Warn("slow request")
+	msg := read()
+	// daemon is 3, warning is 4
+	if !strings.HasPrefix(msg, "<28>1 ") {
+		t.Errorf("expected <28>1 prefix, got %s", msg)
+	}
+	if !strings.Contains(msg, " api ") || !strings.Contains(msg, " - - {") {
+		t.Errorf("expected RFC 5424 header, got %s", msg)
+	}
+}
+
+func TestSyslogInvalid(t *testing.T) {
+	cfg := logze.NewConfig().WithSyslog("udp", "127.0.0.1:514", "unknown", "")
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unknown facility") {
+		t.Errorf("expected unknown facility error, got %v", err)
+	}
+	if _, err := logze.NewSyslogWriter(logze.SyslogOptions{Network: "udp", Addr: "127.0.0.1:514", Format: "rfc1"}); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+	if _, err := logze.NewSyslogWriter(logze.SyslogOptions{Network: "tcp", Addr: "127.0.0.1:1"}); err == nil {
+		t.Errorf("expected error for unavailable server")
+	}
+}
@@ -0,0 +1,79 @@
+package logze
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithMaxThroughput returns [Config] that limits output of the logger to provided number of bytes per second,
+// it protects shared disks and per-pod log quotas from a logging storm. Bursts up to one second budget are allowed.
+// Entries over the budget are dropped and counted in [OutputStats.Throttled], except messages of error level
+// and above: they are always written and consume the budget of the next entries. Zero disables the limit.
+func (c Config) WithMaxThroughput(bytesPerSec int) Config {
+	c.MaxThroughput = bytesPerSec
+	return c
+}
+
+// throttleWriter is a token bucket that drops entries exceeding a budget of bytes per second.
+type throttleWriter struct {
+	w     io.Writer
+	limit float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	throttled atomic.Int64
+}
+
+func newThrottleWriter(w io.Writer, bytesPerSec int) *throttleWriter {
+	return &throttleWriter{
+		w:      w,
+		limit:  float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+func (w *throttleWriter) Write(p []byte) (int, error) {
+	if !w.allow(len(p), false) {
+		return len(p), nil
+	}
+	return w.w.Write(p)
+}
+
+// WriteLevel implements [zerolog.LevelWriter].
+func (w *throttleWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !w.allow(len(p), level >= zerolog.ErrorLevel && level != zerolog.NoLevel) {
+		return len(p), nil
+	}
+	if lw, ok := w.w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.w.Write(p)
+}
+
+// allow takes n tokens from the bucket and returns true if the entry should be written.
+// Forced entries are always written, the budget can become negative because of them.
+func (w *throttleWriter) allow(n int, force bool) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.tokens += now.Sub(w.last).Seconds() * w.limit
+	if w.tokens > w.limit {
+		w.tokens = w.limit
+	}
+	w.last = now
+
+	if !force && w.tokens < float64(n) {
+		w.throttled.Add(1)
+		return false
+	}
+	w.tokens -= float64(n)
+	return true
+}
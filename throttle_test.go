@@ -0,0 +1,48 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestMaxThroughput(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithNoTimestamp().WithMaxThroughput(200))
+
+	// Every entry is about 40 bytes, so only several of them fit in the budget
+	for i := 0; i < 20; i++ {
+		logger.Info("message", "i", i)
+	}
+	logger.Error("important")
+
+	infos := strings.Count(b.String(), `"level":"info"`)
+	if infos == 0 || infos >= 20 {
+		t.Errorf("expected some info messages to be throttled, got %d", infos)
+	}
+	if !strings.Contains(b.String(), "important") {
+		t.Errorf("expected error message not to be throttled, got %s", b.String())
+	}
+	if stats := logger.Stats(); stats.Throttled != int64(20-infos) {
+		t.Errorf("expected %d throttled, got %d", 20-infos, stats.Throttled)
+	}
+}
+
+func TestMaxThroughputWithDiode(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithMaxThroughput(100))
+
+	for i := 0; i < 10; i++ {
+		logger.Info("message", "i", i)
+	}
+	logger.Close()
+
+	if stats := logger.Stats(); stats.Throttled == 0 {
+		t.Errorf("expected throttled entries, got %+v", stats)
+	}
+	if err := logze.NewConfig(&b).WithMaxThroughput(-1).Validate(); err == nil {
+		t.Errorf("expected error for negative throughput")
+	}
+}
@@ -0,0 +1,117 @@
+package logze
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithWriterTimeFormat returns [Config] with added [io.Writer] that gets time field in its own format and location,
+// e.g. UTC [time.RFC3339Nano] for shipped logs while other writers use [Config.TimeFieldFormat].
+// Nil location keeps the location of the logger. Use [ConsoleOptions] to set time format of a console writer.
+func (c Config) WithWriterTimeFormat(w io.Writer, format string, loc *time.Location) Config {
+	return c.WithWriter(NewTimeFormatWriter(w, format, loc))
+}
+
+// NewTimeFormatWriter returns [io.Writer] that rewrites time field of every JSON entry to provided format
+// and location before writing it to w. Format can be a layout for [time.Time.Format] or one of
+// [zerolog.TimeFormatUnix], [zerolog.TimeFormatUnixMs], [zerolog.TimeFormatUnixMicro], [zerolog.TimeFormatUnixNano].
+// Nil location keeps the location of the entry.
+//
+// When a writer from [NewTimeFormatWriter] is added to [Config], [Logger] encodes time in [time.RFC3339Nano]
+// to keep precision and other writers get time in [Config.TimeFieldFormat].
+func NewTimeFormatWriter(w io.Writer, format string, loc *time.Location) io.Writer {
+	return convertWriter{w: w, convert: timeFormat{format: format, loc: loc}.rewrite, rewritesTime: true}
+}
+
+// timeFormat rewrites time field of entries, that are encoded in RFC3339 with any precision.
+type timeFormat struct {
+	format string
+	loc    *time.Location
+}
+
+func (f timeFormat) rewrite(p []byte) []byte {
+	valueStart, valueEnd, ok := timeValue(p)
+	if !ok {
+		return p
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(p[valueStart+1:valueEnd-1]))
+	if err != nil {
+		return p
+	}
+	if f.loc != nil {
+		t = t.In(f.loc)
+	}
+
+	out := make([]byte, 0, len(p)+16)
+	out = append(out, p[:valueStart]...)
+	out = appendTime(out, t, f.format)
+	return append(out, p[valueEnd:]...)
+}
+
+// timeValue returns offsets of a quoted string value of the time field of the top level object of an entry,
+// fields with the same name in nested objects are skipped.
+func timeValue(p []byte) (start, end int, ok bool) {
+	scanTopLevel(p, func(f topLevelField) bool {
+		if string(f.key) != zerolog.TimestampFieldName {
+			return true
+		}
+		// Time encoded as a number is not rewritten
+		if p[f.valueStart] == '"' {
+			start, end, ok = f.valueStart, f.valueEnd, true
+		}
+		return false
+	})
+	return start, end, ok
+}
+
+// appendTime appends time as a JSON value in provided format, Unix formats are encoded as numbers.
+func appendTime(dst []byte, t time.Time, format string) []byte {
+	switch format {
+	case zerolog.TimeFormatUnix:
+		return strconv.AppendInt(dst, t.Unix(), 10)
+	case zerolog.TimeFormatUnixMs:
+		return strconv.AppendInt(dst, t.UnixMilli(), 10)
+	case zerolog.TimeFormatUnixMicro:
+		return strconv.AppendInt(dst, t.UnixMicro(), 10)
+	case zerolog.TimeFormatUnixNano:
+		return strconv.AppendInt(dst, t.UnixNano(), 10)
+	}
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, format)
+	return append(dst, '"')
+}
+
+// withWriterTimeFormats returns writers where all JSON writers without their own time format get time
+// in provided format. It returns false if there are no writers with their own time format,
+// in that case writers are not changed.
+func withWriterTimeFormats(writers []io.Writer, format string) ([]io.Writer, bool) {
+	found := false
+	for _, w := range writers {
+		if cw, ok := w.(convertWriter); ok && cw.rewritesTime {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return writers, false
+	}
+
+	out := make([]io.Writer, len(writers))
+	for i, w := range writers {
+		if cw, ok := w.(convertWriter); ok && cw.rewritesTime {
+			out[i] = w
+			continue
+		}
+		switch w.(type) {
+		case zerolog.ConsoleWriter, *zerolog.ConsoleWriter:
+			// Console writer parses time itself and formats it using its own TimeFormat
+			out[i] = w
+		default:
+			out[i] = convertWriter{w: w, convert: timeFormat{format: format}.rewrite, rewritesTime: true}
+		}
+	}
+	return out, true
+}
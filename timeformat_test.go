@@ -0,0 +1,70 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func TestWriterTimeFormat(t *testing.T) {
+	var plain, utc, unix, console bytes.Buffer
+	loc := time.FixedZone("UTC+3", 3*60*60)
+
+	cfg := logze.NewConfig(&plain).
+		WithNoDiode().
+		WithTimeFieldFormat(time.RFC3339).
+		WithWriterTimeFormat(&utc, time.RFC3339Nano, time.UTC).
+		WithWriterTimeFormat(&unix, zerolog.TimeFormatUnixMs, nil).
+		WithConsoleOptions(logze.ConsoleOptions{Out: &console, NoColor: true, TimeFormat: "15:04:05", TimeLocation: loc})
+	logze.New(cfg).Info("message")
+
+	var entry map[string]any
+	if err := json.Unmarshal(plain.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts, err := time.Parse(time.RFC3339, entry["time"].(string))
+	if err != nil {
+		t.Fatalf("expected time in RFC3339, got %v", entry["time"])
+	}
+	if strings.Contains(entry["time"].(string), ".") {
+		t.Errorf("expected time without fractional seconds, got %v", entry["time"])
+	}
+
+	if err := json.Unmarshal(utc.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(entry["time"].(string), "Z") {
+		t.Errorf("expected time in UTC, got %v", entry["time"])
+	}
+
+	if err := json.Unmarshal(unix.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ms, ok := entry["time"].(float64)
+	if !ok || time.UnixMilli(int64(ms)).Truncate(time.Second).Unix() != ts.Unix() {
+		t.Errorf("expected unix milliseconds time, got %v", entry["time"])
+	}
+
+	if !strings.HasPrefix(console.String(), ts.In(loc).Format("15:04:05")) {
+		t.Errorf("expected console time in its own format, got %s", console.String())
+	}
+}
+
+func TestWriterTimeFormatNestedTime(t *testing.T) {
+	var b bytes.Buffer
+	w := logze.NewTimeFormatWriter(&b, zerolog.TimeFormatUnix, nil)
+
+	entry := `{"event":{"time":"2020-01-01T00:00:00Z"},"items":[{"time":"2020-01-02T00:00:00Z"}],"time":"2021-02-03T04:05:06Z","message":"m"}` + "\n"
+	if _, err := w.Write([]byte(entry)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"event":{"time":"2020-01-01T00:00:00Z"},"items":[{"time":"2020-01-02T00:00:00Z"}],"time":1612325106,"message":"m"}` + "\n"
+	if b.String() != expected {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+}
@@ -0,0 +1,129 @@
+package logze
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TimeFormatHighRes is a fixed width RFC3339 format with nanoseconds, it is used by default
+// with [Config.WithHighResTimestamps], so timestamps are sorted correctly as strings.
+const TimeFormatHighRes = "2006-01-02T15:04:05.000000000Z07:00"
+
+// WithHighResTimestamps returns [Config] with enabled high resolution timestamps. They have nanosecond precision,
+// derive from a monotonic clock and strictly increase within the logger, so entries emitted within the same
+// millisecond from concurrent goroutines keep their order. Time format is [TimeFormatHighRes]
+// if [Config.TimeFieldFormat] is not set.
+func (c Config) WithHighResTimestamps() Config {
+	c.HighResTimestamps = true
+	return c
+}
+
+// WithNoTimestamp returns [Config] with disabled time field, it is useful for deterministic output
+// (e.g. golden tests) or for systems that stamp their own time.
+func (c Config) WithNoTimestamp() Config {
+	c.NoTimestamp = true
+	return c
+}
+
+// WithClock returns [Config] with a function that returns current time for the time field,
+// so unit tests and replay tooling can produce stable and reproducible output.
+// It takes precedence over [Config.WithHighResTimestamps].
+func (c Config) WithClock(now func() time.Time) Config {
+	c.Clock = now
+	return c
+}
+
+// timestampHook adds the time of an entry using provided clock and format. It is used instead of
+// [zerolog.Context.Timestamp] to keep time format per logger without changing [zerolog.TimeFieldFormat].
+type timestampHook struct {
+	now    func() time.Time
+	format string
+	// escape is true if layout has characters that should be escaped in JSON
+	escape bool
+}
+
+func newTimestampHook(now func() time.Time, format string) timestampHook {
+	return timestampHook{now: now, format: format, escape: strings.ContainsAny(format, "\"\\")}
+}
+
+func (h timestampHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	var t time.Time
+	if h.now != nil {
+		t = h.now()
+	} else {
+		t = zerolog.TimestampFunc()
+	}
+
+	switch h.format {
+	case zerolog.TimeFormatUnix:
+		e.Int64(zerolog.TimestampFieldName, t.Unix())
+	case zerolog.TimeFormatUnixMs:
+		e.Int64(zerolog.TimestampFieldName, t.UnixMilli())
+	case zerolog.TimeFormatUnixMicro:
+		e.Int64(zerolog.TimestampFieldName, t.UnixMicro())
+	case zerolog.TimeFormatUnixNano:
+		e.Int64(zerolog.TimestampFieldName, t.UnixNano())
+	default:
+		if h.escape {
+			e.Str(zerolog.TimestampFieldName, t.Format(h.format))
+			return
+		}
+		// Time is appended to a buffer on stack to avoid allocation of a formatted string
+		var buf [64]byte
+		e.RawJSON(zerolog.TimestampFieldName, appendTime(buf[:0], t, h.format))
+	}
+}
+
+// parseTime parses time of an entry encoded by [timestampHook] with provided format.
+func parseTime(v any, format string, loc *time.Location) (time.Time, bool) {
+	switch v := v.(type) {
+	case string:
+		t, err := time.ParseInLocation(format, v, loc)
+		return t, err == nil
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch format {
+		case zerolog.TimeFormatUnix:
+			return time.Unix(i, 0), true
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(i), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(i), true
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, i), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// highResClock returns strictly increasing timestamps with nanosecond precision. Wall time is read once,
+// then it is advanced with a monotonic clock, so timestamps are not affected by wall clock adjustments.
+type highResClock struct {
+	start time.Time
+	last  atomic.Int64
+}
+
+func newHighResClock() *highResClock {
+	return &highResClock{start: time.Now()}
+}
+
+func (c *highResClock) now() time.Time {
+	base := c.start.UnixNano()
+	for {
+		ts := base + int64(time.Since(c.start))
+		last := c.last.Load()
+		if ts <= last {
+			ts = last + 1
+		}
+		if c.last.CompareAndSwap(last, ts) {
+			return time.Unix(0, ts)
+		}
+	}
+}
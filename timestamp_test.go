@@ -0,0 +1,107 @@
+package logze_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/rs/zerolog"
+)
+
+func TestHighResTimestamps(t *testing.T) {
+	var b syncBuffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithHighResTimestamps())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(b.String()))
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ts, _ := entry["time"].(string)
+		if _, err := time.Parse(logze.TimeFormatHighRes, ts); err != nil {
+			t.Fatalf("expected time in high res format, got %s", ts)
+		}
+		if _, ok := seen[ts]; ok {
+			t.Fatalf("expected unique timestamps, got %s twice", ts)
+		}
+		seen[ts] = struct{}{}
+	}
+	if len(seen) != 400 {
+		t.Errorf("expected 400 entries, got %d", len(seen))
+	}
+}
+
+func TestTimeFormatPerLogger(t *testing.T) {
+	var unix, date, console bytes.Buffer
+	globalFormat := zerolog.TimeFieldFormat
+
+	unixLogger := logze.New(logze.NewConfig(&unix).WithNoDiode().WithTimeFieldFormat(zerolog.TimeFormatUnixMs))
+	dateLogger := logze.New(logze.NewConfig(&date).WithNoDiode().WithTimeFieldFormat("2006-01-02 15:04:05").
+		WithConsoleOptions(logze.ConsoleOptions{Out: &console, NoColor: true, TimeFormat: "15:04"}))
+
+	if zerolog.TimeFieldFormat != globalFormat {
+		t.Errorf("expected global time format %q not to be changed, got %q", globalFormat, zerolog.TimeFieldFormat)
+	}
+
+	unixLogger.Info("unix")
+	dateLogger.Info("date")
+
+	var entry map[string]any
+	if err := json.Unmarshal(unix.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := entry["time"].(float64); !ok {
+		t.Errorf("expected unix time, got %v", entry["time"])
+	}
+
+	if err := json.Unmarshal(date.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", entry["time"].(string), time.Local)
+	if err != nil {
+		t.Fatalf("expected time in custom format, got %v", entry["time"])
+	}
+	if !strings.HasPrefix(console.String(), ts.Format("15:04")+" ") {
+		t.Errorf("expected console to parse custom time format, got %s", console.String())
+	}
+}
+
+func TestNoTimestamp(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithNoTimestamp())
+
+	logger.Info("message", "key", "value")
+	if expected := `{"level":"info","key":"value","message":"message"}` + "\n"; b.String() != expected {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+}
+
+func TestClock(t *testing.T) {
+	var b bytes.Buffer
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithClock(func() time.Time { return now }))
+
+	logger.Info("message")
+	if expected := `{"level":"info","time":"2024-01-02T03:04:05Z","message":"message"}` + "\n"; b.String() != expected {
+		t.Errorf("expected %s, got %s", expected, b.String())
+	}
+}
@@ -0,0 +1,143 @@
+package logze
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTraceSampleEvery is a default sampling of operations of [TracedReader] and [TracedWriter]:
+// the first operation and every 100th one are logged.
+const DefaultTraceSampleEvery = 100
+
+// TracedReader is an [io.Reader] that logs byte counts, durations, EOF and errors of reads in trace level.
+// Use [TraceReader] to create it.
+type TracedReader struct {
+	r io.Reader
+	t *ioTracer
+}
+
+// TraceReader returns a reader that logs reads from r in trace level with provided name of a stream,
+// it is useful for debugging of streaming pipelines. The first read and every 100th one are logged
+// with number of bytes and duration, EOF and errors are always logged with totals.
+// Nothing is measured if trace level is disabled.
+func TraceReader(r io.Reader, lg Logger, name string) *TracedReader {
+	return &TracedReader{r: r, t: newIOTracer(lg, name, "read")}
+}
+
+// WithSampling sets logging of every n-th read, n <= 1 means logging of every read.
+func (r *TracedReader) WithSampling(n int) *TracedReader {
+	r.t.setSampling(n)
+	return r
+}
+
+// Read implements [io.Reader].
+func (r *TracedReader) Read(p []byte) (int, error) {
+	if !r.t.enabled() {
+		return r.r.Read(p)
+	}
+	start := time.Now()
+	n, err := r.r.Read(p)
+	r.t.observe(n, err, time.Since(start))
+	return n, err
+}
+
+// Close closes the underlying reader if it implements [io.Closer] and logs totals.
+func (r *TracedReader) Close() error {
+	return r.t.close(r.r)
+}
+
+// TracedWriter is an [io.Writer] that logs byte counts, durations and errors of writes in trace level.
+// Use [TraceWriter] to create it.
+type TracedWriter struct {
+	w io.Writer
+	t *ioTracer
+}
+
+// TraceWriter returns a writer that logs writes to w in trace level with provided name of a stream,
+// see [TraceReader] for details.
+func TraceWriter(w io.Writer, lg Logger, name string) *TracedWriter {
+	return &TracedWriter{w: w, t: newIOTracer(lg, name, "write")}
+}
+
+// WithSampling sets logging of every n-th write, n <= 1 means logging of every write.
+func (w *TracedWriter) WithSampling(n int) *TracedWriter {
+	w.t.setSampling(n)
+	return w
+}
+
+// Write implements [io.Writer].
+func (w *TracedWriter) Write(p []byte) (int, error) {
+	if !w.t.enabled() {
+		return w.w.Write(p)
+	}
+	start := time.Now()
+	n, err := w.w.Write(p)
+	w.t.observe(n, err, time.Since(start))
+	return n, err
+}
+
+// Close closes the underlying writer if it implements [io.Closer] and logs totals.
+func (w *TracedWriter) Close() error {
+	return w.t.close(w.w)
+}
+
+type ioTracer struct {
+	lg       Logger
+	name     string
+	op       string
+	every    atomic.Int64
+	ops      atomic.Int64
+	total    atomic.Int64
+	busy     atomic.Int64
+	start    time.Time
+	finished atomic.Bool
+}
+
+func newIOTracer(lg Logger, name, op string) *ioTracer {
+	t := &ioTracer{lg: lg, name: name, op: op, start: time.Now()}
+	t.setSampling(DefaultTraceSampleEvery)
+	return t
+}
+
+func (t *ioTracer) setSampling(n int) {
+	if n < 1 {
+		n = 1
+	}
+	t.every.Store(int64(n))
+}
+
+func (t *ioTracer) enabled() bool {
+	return t.lg.IsTrace()
+}
+
+func (t *ioTracer) observe(n int, err error, dur time.Duration) {
+	ops := t.ops.Add(1)
+	total := t.total.Add(int64(n))
+	busy := t.busy.Add(int64(dur))
+
+	switch {
+	case errors.Is(err, io.EOF):
+		if t.finished.CompareAndSwap(false, true) {
+			t.lg.Trace("stream is finished", "stream", t.name, "op", t.op, "total", total, "ops", ops,
+				"busy", time.Duration(busy), "elapsed", time.Since(t.start))
+		}
+	case err != nil:
+		t.lg.Trace("stream failed", "stream", t.name, "op", t.op, "error", err, "bytes", n, "total", total, "ops", ops)
+	case ops == 1 || ops%t.every.Load() == 0:
+		t.lg.Trace("stream "+t.op, "stream", t.name, "bytes", n, "duration", dur, "total", total, "ops", ops)
+	}
+}
+
+func (t *ioTracer) close(v any) error {
+	var err error
+	if c, ok := v.(io.Closer); ok {
+		err = c.Close()
+	}
+	if t.enabled() && t.finished.CompareAndSwap(false, true) {
+		t.lg.Trace("stream is closed", "stream", t.name, "op", t.op, "total", t.total.Load(), "ops", t.ops.Load(),
+			"busy", time.Duration(t.busy.Load()), "elapsed", time.Since(t.start), "error", err)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package logze_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestTraceReader(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+
+	r := logze.TraceReader(strings.NewReader(strings.Repeat("x", 10)), logger, "input").WithSampling(2)
+	buf := make([]byte, 3)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			break
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	// Reads 1, 2 and 4 are sampled, EOF is returned by the 5th read
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %s", b.String())
+	}
+	if !strings.Contains(lines[0], `"message":"stream read"`) || !strings.Contains(lines[0], `"stream":"input","bytes":3`) {
+		t.Errorf("expected read message, got %s", lines[0])
+	}
+	if !strings.Contains(lines[3], `"message":"stream is finished"`) || !strings.Contains(lines[3], `"total":10,"ops":5`) {
+		t.Errorf("expected finished message, got %s", lines[3])
+	}
+
+	b.Reset()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected no message after finish, got %s", b.String())
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestTraceWriter(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelTrace).WithNoDiode())
+
+	w := logze.TraceWriter(failWriter{}, logger, "output")
+	if _, err := w.Write([]byte("data")); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(b.String(), `"message":"stream failed"`) || !strings.Contains(b.String(), `"error":"write failed"`) {
+		t.Errorf("expected failed message, got %s", b.String())
+	}
+
+	b.Reset()
+	w = logze.TraceWriter(io.Discard, logger.WithLevel(logze.LevelDebug), "output")
+	if _, err := w.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if b.Len() != 0 {
+		t.Errorf("expected no messages for disabled trace level, got %s", b.String())
+	}
+}
@@ -0,0 +1,116 @@
+package logze
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// Validate checks [Config] for errors and returns all found problems joined in one error.
+// It is useful to catch misconfiguration at startup, because [New] silently uses default values
+// (e.g. [io.Discard] if there are no writers) and panics only in case of invalid level.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Level != "" {
+		if _, err := zerolog.ParseLevel(c.Level); err != nil {
+			errs = append(errs, fmt.Errorf("invalid level %q", c.Level))
+		}
+	}
+
+	if c.StackTraceLevel != "" {
+		if _, err := zerolog.ParseLevel(c.StackTraceLevel); err != nil {
+			errs = append(errs, fmt.Errorf("invalid stack trace level %q", c.StackTraceLevel))
+		}
+	}
+
+	for _, level := range c.CallerLevels {
+		if _, err := zerolog.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("invalid caller level %q", level))
+		}
+	}
+
+	if c.CallerSkip < 0 {
+		errs = append(errs, fmt.Errorf("negative caller skip %d", c.CallerSkip))
+	}
+
+	if c.StackSkip < 0 {
+		errs = append(errs, fmt.Errorf("negative stack skip %d", c.StackSkip))
+	}
+
+	switch c.NilErrors {
+	case "", NilErrorsError, NilErrorsInfo, NilErrorsSkip, NilErrorsPanic:
+	default:
+		errs = append(errs, fmt.Errorf("invalid nil errors mode %q", c.NilErrors))
+	}
+
+	if _, err := parseSummaryTemplate(c.Summary); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch c.NoWriters {
+	case "", NoWritersDiscard, NoWritersWarn, NoWritersStderr, NoWritersError:
+	default:
+		errs = append(errs, fmt.Errorf("invalid no writers mode %q", c.NoWriters))
+	}
+	// Writers are checked like New uses them, explicit discard and warn modes are not problems
+	if len(c.outputWriters()) == 0 && c.Level != LevelDisabled {
+		switch c.NoWriters {
+		case "":
+			errs = append(errs, fmt.Errorf("no writers provided, all logs will be discarded"))
+		case NoWritersError:
+			errs = append(errs, fmt.Errorf("no writers provided"))
+		}
+	}
+	for i, w := range c.Writers {
+		if w == nil {
+			errs = append(errs, fmt.Errorf("writer #%d is nil", i))
+			continue
+		}
+		// Writers created by config methods report invalid options here instead of failing every write
+		if v, ok := w.(interface{ validate() error }); ok {
+			if err := v.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("writer #%d: %w", i, err))
+			}
+		}
+	}
+
+	if c.DiodeSize < 0 {
+		errs = append(errs, fmt.Errorf("negative diode size %d", c.DiodeSize))
+	}
+	if c.DiodePollingInterval < 0 {
+		errs = append(errs, fmt.Errorf("negative diode polling interval %s", c.DiodePollingInterval))
+	}
+	if c.NoDiode {
+		if c.UseDiodeWaiter {
+			errs = append(errs, fmt.Errorf("diode waiter is enabled, but diode is disabled"))
+		}
+		if c.DiodeSize != 0 || c.DiodePollingInterval != 0 || c.DiodeAlertFunc != nil {
+			errs = append(errs, fmt.Errorf("diode parameters are set, but diode is disabled"))
+		}
+	}
+	if c.UseDiodeWaiter && c.DiodePollingInterval != 0 {
+		errs = append(errs, fmt.Errorf("diode polling interval is set, but diode waiter is enabled"))
+	}
+
+	if c.MaxThroughput < 0 {
+		errs = append(errs, fmt.Errorf("negative max throughput %d", c.MaxThroughput))
+	}
+
+	switch c.Async.Policy {
+	case "", AsyncDropNewest, AsyncDropOldest, AsyncBlock:
+	default:
+		errs = append(errs, fmt.Errorf("invalid async policy %q", c.Async.Policy))
+	}
+	if c.Async.Size < 0 {
+		errs = append(errs, fmt.Errorf("negative async queue size %d", c.Async.Size))
+	}
+	if c.Async.BlockTimeout < 0 {
+		errs = append(errs, fmt.Errorf("negative async block timeout %s", c.Async.BlockTimeout))
+	}
+	if c.Async.Policy == "" && (c.Async.Size != 0 || c.Async.BlockTimeout != 0) {
+		errs = append(errs, fmt.Errorf("async parameters are set, but async policy is empty"))
+	}
+
+	return joinErrors(errs...)
+}
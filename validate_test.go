@@ -0,0 +1,58 @@
+package logze_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestValidateOK(t *testing.T) {
+	cfgs := []logze.Config{
+		logze.NewConfig(io.Discard),
+		logze.NewConfig(io.Discard).WithLevel(logze.LevelDebug).WithNoDiode(),
+		logze.NewConfig(io.Discard).WithDiodeWaiter().WithDiodeSize(100),
+		logze.NewConfig().WithLevel(logze.LevelDisabled),
+		logze.NewConfig().WithDevelopment(),
+		logze.NewConfig().WithNoWriters(logze.NoWritersDiscard),
+		logze.NewConfig().WithNoWriters(logze.NoWritersWarn),
+		logze.NewConfig().WithNoWriters(logze.NoWritersStderr),
+	}
+	for i, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("config #%d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestValidateAggregated(t *testing.T) {
+	cfg := logze.NewConfig(nil).
+		WithLevel("verbose").
+		WithDiodeSize(-1).
+		WithDiodeWaiter().
+		WithNoDiode()
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, problem := range []string{"invalid level", "writer #0 is nil", "negative diode size", "diode waiter is enabled"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected %q in error, got %s", problem, err)
+		}
+	}
+}
+
+func TestValidateNoWriters(t *testing.T) {
+	err := logze.NewConfig().Validate()
+	if err == nil || !strings.Contains(err.Error(), "no writers") {
+		t.Errorf("expected no writers error, got %v", err)
+	}
+
+	err = logze.NewConfig().WithDevelopment().WithNoWriters(logze.NoWritersError).Validate()
+	if err == nil || !strings.Contains(err.Error(), "no writers") {
+		t.Errorf("expected no writers error, got %v", err)
+	}
+}
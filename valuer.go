@@ -0,0 +1,60 @@
+package logze
+
+// maxValuerDepth limits resolving of values that return other [Valuer] to prevent infinite loops.
+const maxValuerDepth = 100
+
+// Valuer is a value that is resolved to its log representation only when a message is going to be written,
+// so expensive representations are not computed for messages of disabled levels:
+//
+//	type users []User
+//
+//	func (u users) LogValue() any { return u.names() } // called only if debug level is enabled
+//
+//	logger.Debug("loaded", "users", users(list))
+//
+// Values of func() any type are resolved the same way:
+//
+//	logger.Debug("state", "goroutines", func() any { return runtime.NumGoroutine() })
+//
+// Values added with [Logger.WithFields] are resolved immediately.
+type Valuer interface {
+	LogValue() any
+}
+
+// resolveValues returns fields with resolved values of [Valuer] and func() any types.
+// It returns provided slice without allocations if there are no such values.
+func resolveValues(fields []any) []any {
+	var out []any
+	for i := 1; i < len(fields); i += 2 {
+		v, ok := resolveValue(fields[i])
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]any, len(fields))
+			copy(out, fields)
+		}
+		out[i] = v
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// resolveValue returns a resolved value and true if the value is a [Valuer] or func() any.
+func resolveValue(v any) (any, bool) {
+	resolved := false
+	for i := 0; i < maxValuerDepth; i++ {
+		switch val := v.(type) {
+		case Valuer:
+			v = val.LogValue()
+		case func() any:
+			v = val()
+		default:
+			return v, resolved
+		}
+		resolved = true
+	}
+	return v, resolved
+}
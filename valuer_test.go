@@ -0,0 +1,74 @@
+package logze_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+type testValuer struct {
+	calls *int
+}
+
+func (v testValuer) LogValue() any {
+	*v.calls++
+	return "resolved"
+}
+
+type testLoopValuer struct{}
+
+func (v testLoopValuer) LogValue() any {
+	return v
+}
+
+func TestValuer(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode())
+	calls := 0
+	v := testValuer{calls: &calls}
+
+	logger.Debug("debug message", "value", v)
+	logger.Debugf("debug message %d", 1, "value", v)
+	if calls != 0 {
+		t.Errorf("expected no calls for disabled level, got %d", calls)
+	}
+
+	logger.Info("info message", "value", v, logze.Dict("dict", "value", v))
+	if calls != 2 || !strings.Contains(b.String(), `"value":"resolved","dict":{"value":"resolved"}`) {
+		t.Errorf("expected resolved values, got %d calls and %s", calls, b.String())
+	}
+
+	b.Reset()
+	logger.WithGroup("group").Info("message", "value", v)
+	if !strings.Contains(b.String(), `"group":{"value":"resolved"}`) {
+		t.Errorf("expected resolved value in group, got %s", b.String())
+	}
+
+	b.Reset()
+	logger.Info("message", "loop", testLoopValuer{})
+	if !strings.Contains(b.String(), `"loop":{}`) {
+		t.Errorf("expected stopped resolving, got %s", b.String())
+	}
+}
+
+func TestLazyFunc(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithLevel(logze.LevelInfo).WithNoDiode())
+	calls := 0
+	f := func() any {
+		calls++
+		return 42
+	}
+
+	logger.Debug("debug message", "value", f)
+	if calls != 0 {
+		t.Errorf("expected no calls for disabled level, got %d", calls)
+	}
+
+	logger.Infof("info %s", "message", "value", f, "valuer", func() any { return testValuer{calls: &calls} })
+	if calls != 2 || !strings.Contains(b.String(), `"value":42,"valuer":"resolved"`) {
+		t.Errorf("expected resolved values, got %d calls and %s", calls, b.String())
+	}
+}
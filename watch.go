@@ -0,0 +1,204 @@
+package logze
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is a default time to wait for subsequent changes of a config file before reloading it.
+const DefaultWatchDebounce = 100 * time.Millisecond
+
+// WatchOptions is using for configuring [WatchConfig].
+type WatchOptions struct {
+	// Logger is a logger to apply config changes to. Default value is a global logger.
+	Logger *Logger
+
+	// OnReload is called after every successful reload with the applied config.
+	// Default value is nil.
+	OnReload func(cfg Config)
+
+	// OnError is called when config cannot be loaded or applied.
+	// Default value is a function that logs an error using the target logger.
+	OnError func(err error)
+
+	// Debounce is a time to wait for subsequent changes of a file before reloading it,
+	// editors often write a file in several steps. Default value is 100ms.
+	Debounce time.Duration
+}
+
+// ConfigWatcher watches a config file and applies its changes to a logger. Use [WatchConfig] to create it.
+type ConfigWatcher struct {
+	path    string
+	opts    WatchOptions
+	watcher *fsnotify.Watcher
+	writers []string
+	// owned is true if writers of the logger are opened by the watcher, so they are closed on replacing
+	owned bool
+	// target is a file the config path resolves to, k8s config maps change it by swapping a symlink
+	target string
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// WatchConfig loads a JSON or YAML config file (see [LoadConfig]), applies it to the global logger
+// and watches the file for changes. Every change of level, list of messages to ignore or writers
+// is atomically applied using [Logger.Reload], so it is possible to raise verbosity in production without restarts.
+// Files from the writers list are reopened only if the list is changed, previously opened ones are closed then
+// (except stdout and stderr). Writers the logger had before watching are not closed.
+// Call [ConfigWatcher.Close] to stop watching.
+func WatchConfig(path string, opts WatchOptions) (*ConfigWatcher, error) {
+	if opts.Logger == nil {
+		opts.Logger = DefaultPtr()
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) {
+			opts.Logger.Err(err, "cannot reload log config", "path", path)
+		}
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("get absolute path: %w", err)
+	}
+
+	w := &ConfigWatcher{
+		path: path,
+		opts: opts,
+		done: make(chan struct{}),
+	}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+
+	w.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+	// Watch a directory to handle editors and k8s config maps replacing a file instead of writing it
+	if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+		w.watcher.Close()
+		return nil, fmt.Errorf("watch config: %w", err)
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+// Close stops watching a config file. It is safe to call it several times.
+func (w *ConfigWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.closeErr = w.watcher.Close()
+		w.wg.Wait()
+	})
+	return w.closeErr
+}
+
+func (w *ConfigWatcher) run() {
+	defer w.wg.Done()
+
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Has(fsnotify.Chmod) && !ev.Has(fsnotify.Write) {
+				continue
+			}
+			// Config maps update files by swapping a symlink of a directory, so events don't name the file
+			if filepath.Clean(ev.Name) != w.path && !w.targetChanged() {
+				continue
+			}
+			timer.Reset(w.opts.Debounce)
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.opts.OnError(fmt.Errorf("watch config: %w", err))
+
+		case <-timer.C:
+			if err := w.reload(); err != nil {
+				w.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// targetChanged returns true if the config path resolves to another file than the loaded one.
+func (w *ConfigWatcher) targetChanged() bool {
+	target, err := filepath.EvalSymlinks(w.path)
+	return err == nil && target != w.target
+}
+
+func (w *ConfigWatcher) reload() error {
+	// Error is ignored, reading the file reports it
+	w.target, _ = filepath.EvalSymlinks(w.path)
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	fc, err := parseFileConfig(data)
+	if err != nil {
+		return err
+	}
+
+	writers := fc.Writers
+	if w.writers != nil && reflect.DeepEqual(writers, w.writers) {
+		// Keep current writers to avoid reopening files
+		fc.Writers = nil
+	}
+
+	cfg, err := fc.Config()
+	if err != nil {
+		return err
+	}
+	old, err := w.opts.Logger.reload(cfg)
+	if err != nil {
+		_ = closeWriters(trackWriters(cfg.Writers))
+		return err
+	}
+	if old != nil {
+		if w.owned {
+			if err := closeWriters(old.writers); err != nil {
+				w.opts.OnError(fmt.Errorf("close previous writers: %w", err))
+			}
+		}
+		w.owned = len(cfg.Writers) > 0
+	}
+	if w.owned {
+		w.writers = writers
+	} else {
+		// Writers are opened again with the next reload even if the list is not changed
+		w.writers = nil
+	}
+
+	if w.opts.OnReload != nil {
+		w.opts.OnReload(cfg)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package logze_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestLoggerReload(t *testing.T) {
+	var b1, b2 bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b1).WithNoDiode())
+	child := logger.WithFields("foo", "bar")
+
+	child.Debug("debug before reload")
+	if b1.Len() != 0 {
+		t.Errorf("expected no debug logs, got %s", b1.String())
+	}
+
+	err := logger.Reload(logze.NewConfig(&b2).WithLevel(logze.LevelDebug).WithToIgnore("ignore me").WithNoDiode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	child.Debug("debug after reload")
+	child.Info("ignore me")
+	if !strings.Contains(b2.String(), "debug after reload") || !strings.Contains(b2.String(), "foo\":\"bar") {
+		t.Errorf("expected debug log in a new writer, got %s", b2.String())
+	}
+	if strings.Contains(b2.String(), "ignore me") {
+		t.Errorf("expected ignored message, got %s", b2.String())
+	}
+
+	if err := logger.Reload(logze.NewConfig().WithLevel("verbose")); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "log.yaml")
+	logPath := filepath.Join(dir, "app.log")
+
+	writeConfig := func(level string) {
+		data := "level: " + level + "\nwriters: [\"" + logPath + "\"]\ndiode: {disabled: true}\n"
+		if err := os.WriteFile(cfgPath, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(logze.LevelInfo)
+
+	var errBuf syncBuffer
+	logger := logze.New(logze.NewConfig(&errBuf).WithNoDiode())
+	reloaded := make(chan logze.Config, 10)
+
+	w, err := logze.WatchConfig(cfgPath, logze.WatchOptions{
+		Logger:   &logger,
+		Debounce: 10 * time.Millisecond,
+		OnReload: func(cfg logze.Config) { reloaded <- cfg },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+	<-reloaded
+
+	logger.Debug("first debug")
+	writeConfig(logze.LevelDebug)
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Level != logze.LevelDebug {
+			t.Errorf("expected %s, got %s", logze.LevelDebug, cfg.Level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+
+	logger.Debug("second debug")
+
+	output, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(output), "first debug") || !strings.Contains(string(output), "second debug") {
+		t.Errorf("expected only second debug message, got %s", output)
+	}
+	if errBuf.String() != "" {
+		t.Errorf("expected no errors, got %s", errBuf.String())
+	}
+}
+
+func TestWatchConfigClosesWriters(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("open files are not listed in /proc")
+	}
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "log.yaml")
+	firstPath := filepath.Join(dir, "first.log")
+	secondPath := filepath.Join(dir, "second.log")
+
+	writeConfig := func(path string) {
+		data := "writers: [\"" + path + "\", stderr]\ndiode: {disabled: true}\n"
+		if err := os.WriteFile(cfgPath, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(firstPath)
+
+	logger := logze.New(logze.NewConfig(io.Discard).WithNoDiode())
+	reloaded := make(chan logze.Config, 10)
+	w, err := logze.WatchConfig(cfgPath, logze.WatchOptions{
+		Logger:   &logger,
+		Debounce: 10 * time.Millisecond,
+		OnReload: func(cfg logze.Config) { reloaded <- cfg },
+		OnError:  func(err error) { t.Errorf("unexpected error: %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+	<-reloaded
+	if !isFileOpen(t, firstPath) {
+		t.Fatalf("expected opened %s", firstPath)
+	}
+
+	writeConfig(secondPath)
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+	if isFileOpen(t, firstPath) {
+		t.Errorf("expected closed %s after reload", firstPath)
+	}
+	if !isFileOpen(t, secondPath) {
+		t.Errorf("expected opened %s", secondPath)
+	}
+	if _, err := os.Stderr.Write(nil); err != nil {
+		t.Errorf("expected open stderr, got %v", err)
+	}
+}
+
+func isFileOpen(t *testing.T, path string) bool {
+	t.Helper()
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", e.Name())); err == nil && target == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWatchConfigSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+	writeData := func(name, level string) {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		data := "level: " + level + "\ndiode: {disabled: true}\n"
+		if err := os.WriteFile(filepath.Join(dir, name, "log.yaml"), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Layout of a mounted k8s config map: log.yaml -> ..data/log.yaml, ..data -> ..v1
+	writeData("..v1", logze.LevelInfo)
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	cfgPath := filepath.Join(dir, "log.yaml")
+	if err := os.Symlink(filepath.Join("..data", "log.yaml"), cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logze.New(logze.NewConfig(io.Discard).WithNoDiode())
+	reloaded := make(chan logze.Config, 10)
+	w, err := logze.WatchConfig(cfgPath, logze.WatchOptions{
+		Logger:   &logger,
+		Debounce: 10 * time.Millisecond,
+		OnReload: func(cfg logze.Config) { reloaded <- cfg },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-reloaded
+
+	// Kubelet writes a new directory and atomically replaces ..data symlink
+	writeData("..v2", logze.LevelDebug)
+	if err := os.Symlink("..v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Level != logze.LevelDebug {
+			t.Errorf("expected %s, got %s", logze.LevelDebug, cfg.Level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded after symlink swap")
+	}
+
+	// Close can be called concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWatchConfigInvalid(t *testing.T) {
+	if _, err := logze.WatchConfig(filepath.Join(t.TempDir(), "missing.yaml"), logze.WatchOptions{}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
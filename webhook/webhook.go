@@ -0,0 +1,291 @@
+// Package webhook provides an [io.Writer] that batches log entries and posts them as a JSON array to an HTTP
+// endpoint, a building block for shipping logs to custom ingestion services:
+//
+//	w := webhook.New("https://logs.example.com/ingest", webhook.WithBearerToken(token), webhook.WithGzip(gzip.BestSpeed))
+//	logger := logze.New(logze.NewConfig(w))
+//	defer logger.Close()
+//
+// Entries are sent in background. Failed requests are retried with exponential backoff, entries are kept
+// in a bounded queue and new entries are dropped when it is full, so a slow endpoint doesn't block logging calls.
+package webhook
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/internal/batcher"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureWebhook)
+}
+
+const (
+	// DefaultBatchSize is a default max number of entries in one request.
+	DefaultBatchSize = 500
+	// DefaultBatchWait is a default max time an entry waits in a batch before it is sent.
+	DefaultBatchWait = time.Second
+	// DefaultQueueSize is a default max number of entries waiting to be sent.
+	DefaultQueueSize = 10000
+	// DefaultMinBackoff is a default delay before the first retry of a failed request.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is a default max delay between retries of a failed request.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultMaxRetries is a default max number of retries of a failed request.
+	DefaultMaxRetries = 10
+)
+
+// ErrQueueFull is passed to the drop callback when entries are dropped because the queue is full.
+var ErrQueueFull = errors.New("webhook: queue is full")
+
+// Option changes a behaviour of [New].
+type Option func(o *options)
+
+// WithBatch sets a max number of entries in one request and a max time an entry waits in a batch,
+// default values are [DefaultBatchSize] and [DefaultBatchWait].
+func WithBatch(size int, wait time.Duration) Option {
+	return func(o *options) {
+		o.batchSize = size
+		o.batchWait = wait
+	}
+}
+
+// WithQueueSize sets a max number of entries waiting to be sent, default value is [DefaultQueueSize].
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithRetry sets a delay before the first retry of a failed request, a max delay between retries and a max number
+// of retries, default values are [DefaultMinBackoff], [DefaultMaxBackoff] and [DefaultMaxRetries].
+// Responses with 408, 429 and 5xx codes and network errors are retried, other errors drop the batch.
+func WithRetry(minBackoff, maxBackoff time.Duration, maxRetries int) Option {
+	return func(o *options) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithGzip enables gzip compression of requests with provided level, e.g. [gzip.BestSpeed].
+func WithGzip(level int) Option {
+	return func(o *options) {
+		o.gzip = true
+		o.gzipLevel = level
+	}
+}
+
+// WithHeader adds a header to requests.
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithBearerToken sets Authorization header of requests with a bearer token.
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithBasicAuth sets Authorization header of requests with a username and a password.
+func WithBasicAuth(username, password string) Option {
+	return WithHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+}
+
+// WithHTTPClient sets a client of requests, default value is a client with 10s timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithOnDrop sets a function that is called with a number of dropped entries and the reason,
+// default function prints a warning to stderr.
+func WithOnDrop(onDrop func(n int, err error)) Option {
+	return func(o *options) {
+		o.onDrop = onDrop
+	}
+}
+
+type options struct {
+	batchSize  int
+	batchWait  time.Duration
+	queueSize  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	maxRetries int
+	gzip       bool
+	gzipLevel  int
+	headers    http.Header
+	client     *http.Client
+	onDrop     func(n int, err error)
+}
+
+// Writer sends entries to an endpoint in background, it is safe for concurrent use.
+// [Writer.Close] must be called to send the rest of entries.
+type Writer struct {
+	url string
+	o   options
+	b   *batcher.Batcher[[]byte]
+}
+
+// New returns [Writer] posting entries to provided URL and starts its background goroutine.
+func New(url string, opts ...Option) *Writer {
+	o := options{
+		batchSize:  DefaultBatchSize,
+		batchWait:  DefaultBatchWait,
+		queueSize:  DefaultQueueSize,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		maxRetries: DefaultMaxRetries,
+		headers:    make(http.Header),
+		client:     &http.Client{Timeout: 10 * time.Second},
+		onDrop: func(n int, err error) {
+			fmt.Fprintf(os.Stderr, "WRN: webhook dropped %d entries: %v\n", n, err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = DefaultBatchSize
+	}
+	if o.batchWait <= 0 {
+		o.batchWait = DefaultBatchWait
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = DefaultQueueSize
+	}
+
+	w := &Writer{url: url, o: o}
+	w.b = batcher.New(batcher.Config[[]byte]{
+		Size:         o.batchSize,
+		Wait:         o.batchWait,
+		QueueSize:    o.queueSize,
+		MinBackoff:   o.minBackoff,
+		MaxBackoff:   o.maxBackoff,
+		MaxRetries:   o.maxRetries,
+		ErrQueueFull: ErrQueueFull,
+		OnDrop: func(lines [][]byte, err error) {
+			if o.onDrop != nil {
+				o.onDrop(len(lines), err)
+			}
+		},
+	}, w.send)
+	return w
+}
+
+// Write adds a copy of an entry to the queue, it never blocks. If the queue is full, the entry is dropped.
+// It returns [os.ErrClosed] after [Writer.Close].
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.b.Add(bytes.TrimRight(append([]byte(nil), p...), "\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends entries that are written before the call and returns an error of the request.
+func (w *Writer) Flush() error {
+	return w.b.Flush()
+}
+
+// Dropped returns a number of entries dropped since the writer is created.
+func (w *Writer) Dropped() int64 {
+	return w.b.Dropped()
+}
+
+// Close stops retrying, sends the rest of entries once and stops the background goroutine.
+func (w *Writer) Close() error {
+	if n := w.b.Close(); n > 0 {
+		return fmt.Errorf("webhook: %d entries dropped on close", n)
+	}
+	return nil
+}
+
+// send makes a request, it returns the batch if the error can be retried.
+func (w *Writer) send(batch [][]byte) ([][]byte, error) {
+	body, err := w.encode(batch)
+	if err != nil {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	req.Header = w.o.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	if w.o.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := w.o.client.Do(req)
+	if err != nil {
+		return batch, fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 == 2 {
+		return nil, nil
+	}
+
+	err = fmt.Errorf("webhook: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode < 500 {
+		w.b.Drop(batch, err)
+		return nil, err
+	}
+	if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s > 0 {
+		return batch, batcher.RetryAfter(time.Duration(s)*time.Second, err)
+	}
+	return batch, err
+}
+
+// encode returns a JSON array of entries, lines that are not valid JSON are encoded as strings.
+func (w *Writer) encode(batch [][]byte) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, line := range batch {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if json.Valid(line) {
+			b.Write(line)
+			continue
+		}
+		s, err := json.Marshal(string(line))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(s)
+	}
+	b.WriteByte(']')
+	if !w.o.gzip {
+		return b.Bytes(), nil
+	}
+
+	var gz bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&gz, w.o.gzipLevel)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	if _, err := zw.Write(b.Bytes()); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	return gz.Bytes(), nil
+}
@@ -0,0 +1,140 @@
+package webhook_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/webhook"
+)
+
+type fakeEndpoint struct {
+	mu      sync.Mutex
+	batches [][]any
+	headers []http.Header
+	fails   atomic.Int32
+	status  int
+}
+
+func (f *fakeEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.fails.Add(-1) >= 0 {
+		w.WriteHeader(f.status)
+		return
+	}
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	var batch []any
+	if err := json.NewDecoder(body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, batch)
+	f.headers = append(f.headers, r.Header)
+	f.mu.Unlock()
+}
+
+func (f *fakeEndpoint) get() ([][]any, []http.Header) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.batches, f.headers
+}
+
+func TestWriter(t *testing.T) {
+	f := &fakeEndpoint{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	w := webhook.New(srv.URL, webhook.WithBearerToken("secret"), webhook.WithGzip(gzip.BestSpeed))
+	logger := logze.New(logze.NewConfig(w).WithNoDiode())
+
+	logger.Info("first")
+	logger.Warn("second")
+	w.Write([]byte("not json\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	batches, headers := f.get()
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("expected 1 batch of 3 entries, got %v", batches)
+	}
+	if e, ok := batches[0][1].(map[string]any); !ok || e["message"] != "second" || e["level"] != "warn" {
+		t.Errorf("expected the second entry as an object, got %v", batches[0][1])
+	}
+	if batches[0][2] != "not json" {
+		t.Errorf("expected invalid JSON as a string, got %v", batches[0][2])
+	}
+	if auth := headers[0].Get("Authorization"); auth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", auth)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("{}")); err == nil {
+		t.Error("expected error after close")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	f := &fakeEndpoint{status: http.StatusServiceUnavailable}
+	f.fails.Store(2)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	w := webhook.New(srv.URL, webhook.WithRetry(time.Millisecond, 5*time.Millisecond, 3))
+	defer w.Close()
+	w.Write([]byte(`{"message":"retried"}`))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if batches, _ := f.get(); len(batches) != 1 {
+		t.Errorf("expected 1 batch after retries, got %d", len(batches))
+	}
+}
+
+func TestDrop(t *testing.T) {
+	f := &fakeEndpoint{status: http.StatusBadRequest}
+	f.fails.Store(1)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	var dropped atomic.Int64
+	w := webhook.New(srv.URL, webhook.WithBasicAuth("user", "pass"), webhook.WithOnDrop(func(n int, err error) {
+		dropped.Add(int64(n))
+	}))
+	w.Write([]byte(`{"message":"rejected"}`))
+	if err := w.Flush(); err == nil {
+		t.Error("expected error of a rejected batch")
+	}
+	if dropped.Load() != 1 || w.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", w.Dropped())
+	}
+
+	w.Write([]byte(`{"message":"accepted"}`))
+	if err := w.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	batches, headers := f.get()
+	if len(batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(batches))
+	}
+	if user, pass, ok := (&http.Request{Header: headers[0]}).BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Errorf("expected basic auth, got %q", headers[0].Get("Authorization"))
+	}
+}
@@ -0,0 +1,96 @@
+package logze
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// StreamFieldName is a field name for a name of a stream of lines written with [Logger.WriterLevel].
+var StreamFieldName = "stream"
+
+// maxWriterLineSize is a size of a line without a newline after which it is logged as a separate entry.
+const maxWriterLineSize = 64 << 10
+
+// WriterLevel returns [io.WriteCloser] that splits written bytes into lines and logs every line as an entry
+// of provided level with a "stream" field, e.g. to wire output of a subprocess into structured logs:
+//
+//	cmd.Stdout = logger.WriterLevel(logze.LevelInfo)
+//	cmd.Stderr = logger.WriterLevel(logze.LevelWarn)
+//
+// Stream is "stderr" for warn and higher levels and "stdout" for others, use [Logger.WriterStream]
+// to set its name. Close logs the last line if it doesn't end with a newline. Unknown level is logged without level.
+func (l Logger) WriterLevel(level string) io.WriteCloser {
+	stream := "stdout"
+	if lvl, err := zerolog.ParseLevel(level); err == nil && lvl >= zerolog.WarnLevel && lvl != zerolog.NoLevel {
+		stream = "stderr"
+	}
+	return l.WriterStream(level, stream)
+}
+
+// WriterStream returns [io.WriteCloser] like [Logger.WriterLevel] with provided name of a stream.
+func (l Logger) WriterStream(level, stream string) io.WriteCloser {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		lvl = zerolog.NoLevel
+	}
+	return &lineWriter{l: l.WithFields(StreamFieldName, stream), level: lvl}
+}
+
+// lineWriter logs every written line as a separate entry, it is safe for concurrent use.
+type lineWriter struct {
+	mu     sync.Mutex
+	l      Logger
+	level  zerolog.Level
+	buf    []byte
+	closed bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) >= maxWriterLineSize {
+		w.logLine(w.buf)
+		w.buf = w.buf[:0]
+	}
+	if len(w.buf) == 0 {
+		// Drop a reference to the consumed part of the buffer
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close logs the rest of written bytes that don't end with a newline.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.buf) > 0 {
+		w.logLine(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *lineWriter) logLine(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	w.l.log(w.l.newEvent(w.level), string(line), nil)
+}
@@ -0,0 +1,69 @@
+package logze_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func TestWriterLevel(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	w := logger.WriterLevel(logze.LevelWarn)
+	w.Write([]byte("first line\r\nsecond "))
+	w.Write([]byte("line\nlast"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("after close\n")); err == nil {
+		t.Error("expected error after close")
+	}
+
+	var entries []map[string]any
+	dec := json.NewDecoder(&b)
+	for dec.More() {
+		var entry map[string]any
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	expected := []string{"first line", "second line", "last"}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, msg := range expected {
+		if entries[i]["message"] != msg || entries[i]["level"] != "warn" || entries[i]["stream"] != "stderr" {
+			t.Errorf("expected warn entry %q from stderr, got %v", msg, entries[i])
+		}
+	}
+}
+
+func TestWriterStreamCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not found")
+	}
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode())
+
+	out := logger.WriterStream(logze.LevelInfo, "build")
+	cmd := exec.Command(sh, "-c", "echo hello")
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out.Close()
+
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry["message"] != "hello" || entry["stream"] != "build" || entry["level"] != "info" {
+		t.Errorf("expected info entry from build stream, got %s", b.String())
+	}
+}
@@ -0,0 +1,150 @@
+// Package zapcompat provides [zapcore.Core] backed by [logze.Logger] to migrate services from zap gradually:
+// libraries that still take [*zap.Logger] write to the same writers with the same settings as logze.
+//
+//	zl := zapcompat.New(logger)
+//	legacy.Run(zl)
+//
+// Levels of zap are mapped to logze levels, DPanic is logged in error level, Panic and Fatal are logged
+// in fatal level, zap panics or exits after writing them itself. Errors added with [zap.Error] are passed
+// to logze as errors, so they get stack traces if they are enabled in logze.
+//
+// It is a separate module, so the core logze package doesn't depend on zap.
+package zapcompat
+
+import (
+	"runtime"
+
+	"github.com/maxbolgarin/logze/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureZapCompat)
+}
+
+// New returns [*zap.Logger] that writes through provided logger. It adds [zap.AddCaller] before provided options,
+// so callers of logze point to a code that calls zap, use [logze.Config.WithCallerLevels] to choose levels
+// with callers.
+func New(l logze.Logger, opts ...zap.Option) *zap.Logger {
+	return zap.New(NewCore(l), append([]zap.Option{zap.AddCaller()}, opts...)...)
+}
+
+// NewCore returns [zapcore.Core] that writes through provided logger.
+func NewCore(l logze.Logger) zapcore.Core {
+	return core{l: l}
+}
+
+type core struct {
+	l logze.Logger
+}
+
+// Enabled implements [zapcore.LevelEnabler].
+func (c core) Enabled(level zapcore.Level) bool {
+	return c.l.Enabled(logzeLevel(level))
+}
+
+// With implements [zapcore.Core].
+func (c core) With(fields []zapcore.Field) zapcore.Core {
+	return core{l: c.l.WithFields(keysAndValues(fields)...)}
+}
+
+// Check implements [zapcore.Core].
+func (c core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements [zapcore.Core].
+func (c core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	kv := keysAndValues(fields)
+	if ent.LoggerName != "" {
+		kv = append(kv, logze.LoggerFieldName, ent.LoggerName)
+	}
+	c.l.WithCallerSkip(callerSkip(ent.Caller)).LogAttrs(logzeLevel(ent.Level), ent.Message, kv)
+	return nil
+}
+
+// Sync implements [zapcore.Core], logze writes entries without buffering in zap, so there is nothing to sync.
+func (c core) Sync() error {
+	return nil
+}
+
+// defaultCallerSkip skips frames of zap between Write and a code that calls a method of [*zap.Logger].
+const defaultCallerSkip = 3
+
+// callerSkip returns a number of frames between Write and a caller found by zap, so callers of logze match it
+// for sugared loggers and loggers with [zap.AddCallerSkip].
+func callerSkip(caller zapcore.EntryCaller) int {
+	if !caller.Defined {
+		return defaultCallerSkip
+	}
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers and callerSkip, so the first frame is Write
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for i := 0; ; i++ {
+		frame, more := frames.Next()
+		if frame.File == caller.File && frame.Line == caller.Line {
+			return i
+		}
+		if !more {
+			return defaultCallerSkip
+		}
+	}
+}
+
+// keysAndValues converts zap fields to (key, value) pairs of logze keeping their order.
+// Errors are kept as errors, other values are encoded using [zapcore.MapObjectEncoder].
+func keysAndValues(fields []zapcore.Field) []any {
+	if len(fields) == 0 {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	out := make([]any, 0, 2*len(fields))
+	for i, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok {
+				out = append(out, f.Key, err)
+				continue
+			}
+		}
+		if f.Type == zapcore.NamespaceType {
+			// Next fields are nested in the namespace, so they are added as a map when all fields are encoded
+			for _, rest := range fields[i:] {
+				rest.AddTo(enc)
+			}
+			for k, v := range enc.Fields {
+				out = append(out, k, v)
+			}
+			return out
+		}
+		f.AddTo(enc)
+		if v, ok := enc.Fields[f.Key]; ok {
+			out = append(out, f.Key, v)
+			delete(enc.Fields, f.Key)
+		}
+	}
+	return out
+}
+
+func logzeLevel(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return logze.LevelDebug
+	case zapcore.InfoLevel:
+		return logze.LevelInfo
+	case zapcore.WarnLevel:
+		return logze.LevelWarn
+	case zapcore.ErrorLevel, zapcore.DPanicLevel:
+		return logze.LevelError
+	case zapcore.PanicLevel, zapcore.FatalLevel:
+		return logze.LevelFatal
+	}
+	if level < zapcore.DebugLevel {
+		return logze.LevelTrace
+	}
+	return logze.LevelFatal
+}
@@ -0,0 +1,62 @@
+package zapcompat_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/zapcompat"
+	"go.uber.org/zap"
+)
+
+func TestLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := logze.New(logze.NewConfig(&b).WithNoDiode().WithCallerLevels(logze.LevelInfo, logze.LevelWarn))
+	zl := zapcompat.New(logger).Named("legacy").With(zap.String("service", "api"))
+
+	zl.Debug("debug message")
+	if b.Len() != 0 {
+		t.Errorf("expected no debug entry in info level, got %s", b.String())
+	}
+
+	zl.Info("info message", zap.Int("count", 3), zap.Error(errors.New("some error")), zap.Namespace("req"), zap.String("id", "req-1"))
+	var entry map[string]any
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]any{
+		"level":   "info",
+		"message": "info message",
+		"logger":  "legacy",
+		"service": "api",
+		"count":   float64(3),
+		"error":   "some error",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if req, _ := entry["req"].(map[string]any); req["id"] != "req-1" {
+		t.Errorf("expected namespace with id, got %v", entry["req"])
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "zapcompat_test.go") {
+		t.Errorf("expected caller in the test, got %q", caller)
+	}
+
+	b.Reset()
+	zl.Sugar().Warnw("sugared message", "key", "value")
+	entry = nil
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry["level"] != "warn" || entry["key"] != "value" {
+		t.Errorf("expected warn entry with key, got %s", b.String())
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "zapcompat_test.go") {
+		t.Errorf("expected caller of sugared logger in the test, got %q", caller)
+	}
+}
@@ -0,0 +1,169 @@
+// Package zstdlog provides a batching [io.Writer] that compresses log entries using zstd
+// with an optional shared dictionary trained on the application's common messages and keys.
+//
+// Logs are highly repetitive: the same keys, levels and messages appear in every entry.
+// A dictionary trained on sample logs lets even small batches compress well,
+// so it is a good fit for binary or batched outputs that ship logs over the network.
+package zstdlog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+	"github.com/maxbolgarin/logze/v2"
+)
+
+func init() {
+	logze.RegisterFeature(logze.FeatureZstd)
+}
+
+const (
+	// DefaultDictSize is a default maximum size of a trained dictionary.
+	DefaultDictSize = 16 << 10
+
+	// DefaultBatchSize is a default size of uncompressed data after which a batch is compressed and flushed.
+	DefaultBatchSize = 64 << 10
+)
+
+// ErrClosed is returned when writing to a closed [Writer].
+var ErrClosed = errors.New("zstdlog: writer is closed")
+
+// TrainDict builds a zstd dictionary from the provided samples, e.g. log lines produced by the application.
+// Max size is a maximum size of the dictionary, [DefaultDictSize] is used if it is zero.
+// The more samples are provided the better the dictionary will be, a few thousands of lines is a good start.
+func TrainDict(samples [][]byte, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultDictSize
+	}
+	return dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: maxSize,
+		HashBytes:   6,
+	})
+}
+
+// TrainDictFromReader reads newline separated log entries from the reader and builds a zstd dictionary from them.
+func TrainDictFromReader(r io.Reader, maxSize int) ([]byte, error) {
+	samples, err := ReadSamples(r)
+	if err != nil {
+		return nil, err
+	}
+	return TrainDict(samples, maxSize)
+}
+
+// ReadSamples reads newline separated log entries from the reader, skipping empty lines.
+func ReadSamples(r io.Reader) ([][]byte, error) {
+	var samples [][]byte
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		samples = append(samples, append([]byte(nil), line...))
+	}
+	return samples, sc.Err()
+}
+
+// Writer collects written entries into batches and writes every batch to the underlying [io.Writer]
+// as a separate zstd frame. It is safe for concurrent use.
+type Writer struct {
+	mu        sync.Mutex
+	w         io.Writer
+	enc       *zstd.Encoder
+	buf       []byte
+	out       []byte
+	batchSize int
+	closed    bool
+}
+
+// NewWriter returns a new [Writer] that compresses batches using the provided dictionary.
+// Dictionary can be nil, in that case plain zstd compression is used.
+// Batch size is a size of uncompressed data after which a batch is flushed, [DefaultBatchSize] is used if it is zero.
+func NewWriter(w io.Writer, dict []byte, batchSize int) (*Writer, error) {
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+	if len(dict) > 0 {
+		opts = append(opts, zstd.WithEncoderDict(dict))
+	}
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Writer{
+		w:         w,
+		enc:       enc,
+		buf:       make([]byte, 0, batchSize),
+		batchSize: batchSize,
+	}, nil
+}
+
+// Write adds an entry to the current batch and flushes it if it exceeds the batch size.
+// If the flush fails, the entry is already a part of the failed batch, so len(p) is returned with the error.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.batchSize {
+		if err := w.flush(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush compresses the current batch and writes it to the underlying [io.Writer].
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// Close flushes the current batch and releases resources of the encoder.
+// It doesn't close the underlying [io.Writer].
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	err := w.flush()
+	w.closed = true
+	w.enc.Close()
+	return err
+}
+
+func (w *Writer) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	w.out = w.enc.EncodeAll(w.buf, w.out[:0])
+	w.buf = w.buf[:0]
+	_, err := w.w.Write(w.out)
+	return err
+}
+
+// NewReader returns a reader that decompresses data written by [Writer] using the same dictionary.
+func NewReader(r io.Reader, dict []byte) (io.ReadCloser, error) {
+	var opts []zstd.DOption
+	if len(dict) > 0 {
+		opts = append(opts, zstd.WithDecoderDicts(dict))
+	}
+	dec, err := zstd.NewReader(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
@@ -0,0 +1,115 @@
+package zstdlog_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/maxbolgarin/logze/v2"
+	"github.com/maxbolgarin/logze/v2/zstdlog"
+)
+
+func generateLogs(n int) []byte {
+	var b bytes.Buffer
+	logger := logze.New(logze.C(&b).WithNoDiode().WithLevel(logze.LevelDebug), "service", "billing")
+	for i := 0; i < n; i++ {
+		logger.Info("request handled", "method", "GET", "path", "/api/users", "status", 200, "id", i)
+		logger.Debug("cache lookup", "key", "user", "hit", i%2 == 0, "id", i)
+	}
+	return b.Bytes()
+}
+
+func TestWriterWithDict(t *testing.T) {
+	dict, err := zstdlog.TrainDictFromReader(bytes.NewReader(generateLogs(300)), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := generateLogs(50)
+
+	var withDict, noDict bytes.Buffer
+	for _, c := range []struct {
+		out  *bytes.Buffer
+		dict []byte
+	}{{&withDict, dict}, {&noDict, nil}} {
+		w, err := zstdlog.NewWriter(c.out, c.dict, 1024)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+			if _, err := w.Write(line); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if withDict.Len() >= noDict.Len() {
+		t.Errorf("expected dictionary to reduce size, got %d with dict and %d without", withDict.Len(), noDict.Len())
+	}
+
+	r, err := zstdlog.NewReader(&withDict, dict)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Errorf("expected decoded data to be equal to original")
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w, err := zstdlog.NewWriter(io.Discard, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("message")); err != zstdlog.ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk is full")
+}
+
+func TestWriterFlushError(t *testing.T) {
+	w, err := zstdlog.NewWriter(failingWriter{}, nil, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The entry is consumed by the failed batch, so it is not retried by callers
+	n, err := w.Write([]byte("message"))
+	if err == nil || n != len("message") {
+		t.Errorf("expected %d and error, got %d, %v", len("message"), n, err)
+	}
+}
+
+func TestReadSamples(t *testing.T) {
+	samples, err := zstdlog.ReadSamples(strings.NewReader("a\n\n  \nb\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(samples) != 2 || string(samples[0]) != "a" || string(samples[1]) != "b" {
+		t.Errorf("unexpected samples: %q", samples)
+	}
+}
+
+func TestFeature(t *testing.T) {
+	if !logze.HasFeature(logze.FeatureZstd) {
+		t.Errorf("expected %s feature, got %v", logze.FeatureZstd, logze.Features())
+	}
+}